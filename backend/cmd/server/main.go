@@ -4,8 +4,13 @@ import (
 	"backend/internal/api"
 	"backend/internal/config"
 	"backend/internal/database"
+	"backend/internal/services"
+	"flag"
 	"fmt"
+	"io/fs"
 	"log"
+	"os"
+	"path/filepath"
 
 	"github.com/joho/godotenv"
 )
@@ -21,14 +26,43 @@ func main() {
 	// Load configuration from environment
 	cfg := config.Load()
 
+	// "server migrate status|up" manages the schema without starting the server
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(cfg, os.Args[2:])
+		return
+	}
+
+	// "server backup [list|verify <archive>]" and "server restore <archive>"
+	// work on the configured instance without starting the server
+	if len(os.Args) > 1 && os.Args[1] == "backup" {
+		runBackup(cfg, os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "restore" {
+		runRestore(cfg, os.Args[2:])
+		return
+	}
+
+	// "server import <dir|file>..." imports other tools' transcript exports
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		runImport(cfg, os.Args[2:])
+		return
+	}
+
+	// "server migrate-sqlite-to-postgres" copies echo.db into DATABASE_URL
+	if len(os.Args) > 1 && os.Args[1] == "migrate-sqlite-to-postgres" {
+		runMigrateToPostgres(cfg, os.Args[2:])
+		return
+	}
+
 	// Initialize database
-	if err := database.Initialize(cfg.DatabasePath, cfg.StoragePath); err != nil {
+	if err := initializeDatabase(cfg); err != nil {
 		log.Fatal("Failed to initialize database:", err)
 	}
 	defer database.Close()
 
 	// Setup router with all handlers
-	router := api.SetupRouter(cfg)
+	router := api.SetupRouter(cfg, services.NewSQLRepositories(database.DB, database.Current))
 
 	// Start server
 	fmt.Printf("🚀 ECHO server starting on port %s\n", cfg.Port)
@@ -44,3 +78,268 @@ func main() {
 		log.Fatal("Failed to start server:", err)
 	}
 }
+
+// initializeDatabase opens the PostgreSQL database when DATABASE_URL is set
+// and the SQLite one otherwise, applying pending migrations
+func initializeDatabase(cfg *config.Config) error {
+	if cfg.DatabaseURL != "" {
+		return database.InitializePostgres(cfg.DatabaseURL, cfg.StoragePath)
+	}
+	return database.Initialize(cfg.DatabasePath, cfg.StoragePath)
+}
+
+// openDatabase opens the configured database without migrating it
+func openDatabase(cfg *config.Config) error {
+	if cfg.DatabaseURL != "" {
+		return database.OpenPostgres(cfg.DatabaseURL)
+	}
+	return database.Open(cfg.DatabasePath)
+}
+
+// runMigrate implements the migrate subcommand
+func runMigrate(cfg *config.Config, args []string) {
+	command := "status"
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	if err := openDatabase(cfg); err != nil {
+		log.Fatal(err)
+	}
+	defer database.Close()
+
+	switch command {
+	case "status":
+		statuses, err := database.MigrationStatuses()
+		if err != nil {
+			log.Fatal("Failed to read migration status:", err)
+		}
+		for _, s := range statuses {
+			state := "pending"
+			if s.AppliedAt != nil {
+				state = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			} else if s.Applied {
+				state = "applied (before versioning)"
+			}
+			fmt.Printf("%04d_%-24s %s\n", s.Version, s.Name, state)
+		}
+
+	case "up":
+		applied, err := database.Migrate()
+		if err != nil {
+			log.Fatal("Migration failed:", err)
+		}
+		if len(applied) == 0 {
+			fmt.Println("Database is up to date")
+			return
+		}
+		fmt.Printf("Applied %d migration(s)\n", len(applied))
+
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown migrate command %q, use: migrate status|up\n", command)
+		os.Exit(2)
+	}
+}
+
+// runBackup implements the backup subcommand
+func runBackup(cfg *config.Config, args []string) {
+	command := "create"
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	switch command {
+	case "create":
+		if err := openDatabase(cfg); err != nil {
+			log.Fatal(err)
+		}
+		defer database.Close()
+
+		service := services.NewBackupService(database.DB, database.Current, cfg.StoragePath, cfg.BackupDir, cfg.BackupKeep)
+		backup, err := service.Create()
+		if err != nil {
+			log.Fatal("Backup failed:", err)
+		}
+		fmt.Printf("Backup written to %s (%d bytes)\n", filepath.Join(cfg.BackupDir, backup.Name), backup.Size)
+
+	case "list":
+		// Listing only reads the backup directory
+		service := services.NewBackupService(nil, database.Current, cfg.StoragePath, cfg.BackupDir, cfg.BackupKeep)
+		backups, err := service.List()
+		if err != nil {
+			log.Fatal("Failed to list backups:", err)
+		}
+		for _, b := range backups {
+			fmt.Printf("%s  %12d bytes  %s\n", b.Name, b.Size, b.CreatedAt.Format("2006-01-02 15:04:05"))
+		}
+
+	case "verify":
+		if len(args) != 2 {
+			fmt.Fprintln(os.Stderr, "Usage: backup verify <archive>")
+			os.Exit(2)
+		}
+		manifest, err := services.VerifyBackup(args[1])
+		if err != nil {
+			log.Fatal("Verification failed: ", err)
+		}
+		fmt.Printf("OK: %d files, schema version %d, taken %s\n", len(manifest.Files), manifest.SchemaVersion, manifest.CreatedAt.Local().Format("2006-01-02 15:04:05"))
+
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown backup command %q, use: backup [create|list|verify <archive>]\n", command)
+		os.Exit(2)
+	}
+}
+
+// runRestore implements the restore subcommand. The server must be stopped.
+func runRestore(cfg *config.Config, args []string) {
+	flags := flag.NewFlagSet("restore", flag.ExitOnError)
+	force := flags.Bool("force", false, "replace the existing database and audio")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: restore [--force] <archive>")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	if cfg.DatabaseURL != "" {
+		log.Fatal("Restore replaces the SQLite database, restore PostgreSQL with pg_restore")
+	}
+
+	manifest, err := services.RestoreBackup(flags.Arg(0), cfg.DatabasePath, cfg.StoragePath, *force)
+	if err != nil {
+		log.Fatal("Restore failed: ", err)
+	}
+	fmt.Printf("Restored %d files from the backup taken %s\n", len(manifest.Files), manifest.CreatedAt.Local().Format("2006-01-02 15:04:05"))
+	fmt.Println("Pending migrations are applied when the server starts")
+}
+
+// runImport implements the import subcommand, importing every transcript
+// export in the given files and directories
+func runImport(cfg *config.Config, args []string) {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	format := flags.String("format", "", "transcript format (vtt, srt, teams or otter), detected per file by default")
+	folderID := flags.Int("folder", 0, "ID of the folder to import into")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: import [--format F] [--folder ID] <dir|file>...")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	if *format != "" && !services.ValidImportFormat(*format) {
+		log.Fatalf("Invalid format %q", *format)
+	}
+
+	if err := initializeDatabase(cfg); err != nil {
+		log.Fatal("Failed to initialize database:", err)
+	}
+	defer database.Close()
+
+	repos := services.NewSQLRepositories(database.DB, database.Current)
+	folders := repos.Folders
+	opts := services.ImportOptions{Format: *format}
+	if *folderID != 0 {
+		folder, err := folders.GetByID(*folderID)
+		if err != nil || folder == nil {
+			log.Fatalf("Folder %d not found", *folderID)
+		}
+		opts.FolderID = folderID
+	}
+
+	var files []string
+	for _, arg := range flags.Args() {
+		err := filepath.WalkDir(arg, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			// Files named explicitly are imported whatever their extension
+			if !d.IsDir() && (path == arg || services.ImportableFile(path)) {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	service := services.NewImportService(repos.Meetings, folders, cfg.StoragePath)
+	imported, duplicates, failed := 0, 0, 0
+	for _, path := range files {
+		result, err := service.ImportFile(path, opts)
+		switch {
+		case err != nil:
+			failed++
+			fmt.Printf("✗ %s: %v\n", path, err)
+		case result.Restored:
+			duplicates++
+			fmt.Printf("= %s: already imported as meeting %d, restored from the trash\n", path, result.MeetingID)
+		case result.Duplicate:
+			duplicates++
+			fmt.Printf("= %s: already imported as meeting %d\n", path, result.MeetingID)
+		default:
+			imported++
+			audio := ""
+			if result.HasAudio {
+				audio = " with audio"
+			}
+			fmt.Printf("✓ %s: meeting %d %q, %d segments%s\n", path, result.MeetingID, result.Title, result.Segments, audio)
+		}
+	}
+
+	fmt.Printf("Imported %d, skipped %d already imported, %d failed\n", imported, duplicates, failed)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// runMigrateToPostgres implements the migrate-sqlite-to-postgres subcommand,
+// copying a SQLite database into the empty PostgreSQL database at DATABASE_URL.
+// Audio files stay where they are in STORAGE_PATH.
+func runMigrateToPostgres(cfg *config.Config, args []string) {
+	flags := flag.NewFlagSet("migrate-sqlite-to-postgres", flag.ExitOnError)
+	source := flags.String("sqlite", cfg.DatabasePath, "SQLite database to copy")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: migrate-sqlite-to-postgres [--sqlite echo.db]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	if cfg.DatabaseURL == "" {
+		log.Fatal("Set DATABASE_URL to the PostgreSQL database to copy into")
+	}
+
+	if err := database.OpenPostgres(cfg.DatabaseURL); err != nil {
+		log.Fatal(err)
+	}
+	defer database.Close()
+	if _, err := database.Migrate(); err != nil {
+		log.Fatal("Migration failed:", err)
+	}
+
+	copies, err := database.CopyFromSQLite(*source)
+	if err != nil {
+		log.Fatal("Copy failed: ", err)
+	}
+	for _, c := range copies {
+		skipped := ""
+		if c.Skipped > 0 {
+			skipped = fmt.Sprintf(", skipped %d orphaned", c.Skipped)
+		}
+		fmt.Printf("%-22s %8d rows%s\n", c.Table, c.Rows, skipped)
+	}
+
+	indexed, err := services.NewSearchService(database.DB, database.Current).EnsureIndexed()
+	if err != nil {
+		log.Fatal("Failed to build the search index:", err)
+	}
+	fmt.Printf("Indexed %d meetings for search\n", indexed)
+	fmt.Printf("Copied %s, the server uses PostgreSQL while DATABASE_URL is set\n", *source)
+}
@@ -2,25 +2,37 @@ package handlers
 
 import (
 	"backend/internal/services"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
 type AIHandler struct {
-	Service *services.GeminiService
+	Service        *services.GeminiService
+	Cache          *services.AICacheService
+	MeetingService services.MeetingRepository
+	TaskService    services.TaskRepository
 }
 
-func NewAIHandler(service *services.GeminiService) *AIHandler {
-	return &AIHandler{Service: service}
+func NewAIHandler(service *services.GeminiService, cache *services.AICacheService, meetingService services.MeetingRepository, taskService services.TaskRepository) *AIHandler {
+	return &AIHandler{Service: service, Cache: cache, MeetingService: meetingService, TaskService: taskService}
 }
 
 type AIRequest struct {
 	Text   string `json:"text" binding:"required"`
 	Action string `json:"action" binding:"required"`
+	// MeetingID is required to extract tasks: relative due dates are
+	// resolved against the meeting's date and the tasks are stored with the
+	// meeting. It is optional for beautify, which then follows the meeting's
+	// prompt template.
+	MeetingID int `json:"meeting_id"`
 }
 
 // HandleAIFormat processes AI formatting requests (beautify, extract tasks, etc.)
+// Results are served from the cache unless the client sends Cache-Control: no-cache.
 func (h *AIHandler) HandleAIFormat(c *gin.Context) {
 	var req AIRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -28,24 +40,138 @@ func (h *AIHandler) HandleAIFormat(c *gin.Context) {
 		return
 	}
 
-	var result interface{}
-	var err error
+	cacheInput := req.Text
 
+	var meeting *services.Meeting
+	if req.MeetingID != 0 {
+		var err error
+		meeting, err = h.MeetingService.GetByID(req.MeetingID)
+		if err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		if meeting == nil {
+			c.JSON(404, gin.H{"error": "Meeting not found"})
+			return
+		}
+	}
+
+	gemini := h.Service
+	if meeting != nil {
+		gemini = h.Service.ForWorkspace(meeting.Workspace)
+	}
+
+	var run func() (interface{}, error)
 	switch req.Action {
 	case "beautify":
-		result, err = h.Service.Beautify(req.Text)
+		instructions := ""
+		if meeting != nil {
+			instructions = meeting.PromptTemplate
+		}
+		if instructions != "" {
+			cacheInput += "\x00" + instructions
+		}
+		run = func() (interface{}, error) { return gemini.Beautify(req.Text, instructions) }
 	case "extract-tasks":
-		result, err = h.Service.ExtractTasks(req.Text)
+		if meeting == nil {
+			c.JSON(400, gin.H{"error": "meeting_id is required to extract tasks"})
+			return
+		}
+		meetingDate := meeting.CreatedAt
+		cacheInput += "\x00" + meetingDate.Format("2006-01-02")
+		run = func() (interface{}, error) { return gemini.ExtractTasks(req.Text, meetingDate) }
 	default:
 		c.JSON(400, gin.H{"error": "Unknown action. Use 'beautify' or 'extract-tasks'"})
 		return
 	}
 
+	settings, _ := h.Service.ActionSettings(req.Action)
+	key := h.Cache.Key(req.Action, settings, cacheInput)
+	if skipCache(c) {
+		h.Cache.RecordBypass()
+	} else if cached, err := h.Cache.Get(key); err != nil {
+		fmt.Printf("AI Cache Error: %v\n", err)
+	} else if cached != nil {
+		if !h.saveTasks(c, req.Action, meeting, cached) {
+			return
+		}
+		c.Header("X-Cache", "HIT")
+		c.JSON(200, gin.H{"result": cached})
+		return
+	}
+
+	result, err := run()
+	if errors.Is(err, services.ErrInvalidModelOutput) {
+		fmt.Printf("AI Error: %v\n", err)
+		c.JSON(502, gin.H{"error": "The AI returned a response in an unexpected format, please try again", "details": err.Error()})
+		return
+	}
 	if err != nil {
 		fmt.Printf("AI Error: %v\n", err)
 		c.JSON(500, gin.H{"error": err.Error()})
 		return
 	}
 
+	if err := h.Cache.Set(key, req.Action, result); err != nil {
+		fmt.Printf("AI Cache Error: %v\n", err)
+	}
+	if !h.saveTasks(c, req.Action, meeting, result) {
+		return
+	}
+
+	c.Header("X-Cache", "MISS")
 	c.JSON(200, gin.H{"result": result})
 }
+
+// saveTasks stores the tasks extracted for a meeting, so digests and exports
+// list them. result is the []ExtractedTask returned by Gemini or its cached
+// JSON. It reports false after responding with an error.
+func (h *AIHandler) saveTasks(c *gin.Context, action string, meeting *services.Meeting, result interface{}) bool {
+	if action != "extract-tasks" || meeting == nil {
+		return true
+	}
+
+	var tasks []services.ExtractedTask
+	switch r := result.(type) {
+	case []services.ExtractedTask:
+		tasks = r
+	case json.RawMessage:
+		if err := json.Unmarshal(r, &tasks); err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return false
+		}
+	}
+
+	if _, err := h.TaskService.AddExtracted(meeting.ID, tasks); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return false
+	}
+	return true
+}
+
+// CacheStats returns hit/miss metrics for the AI result cache
+func (h *AIHandler) CacheStats(c *gin.Context) {
+	stats, err := h.Cache.Stats()
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, stats)
+}
+
+// ClearCache drops every cached AI result
+func (h *AIHandler) ClearCache(c *gin.Context) {
+	if err := h.Cache.Clear(); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "AI cache cleared"})
+}
+
+// skipCache reports whether the client asked for a freshly generated result
+func skipCache(c *gin.Context) bool {
+	directives := strings.ToLower(c.GetHeader("Cache-Control"))
+	return strings.Contains(directives, "no-cache") || strings.Contains(directives, "no-store")
+}
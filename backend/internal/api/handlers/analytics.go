@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"backend/internal/services"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AnalyticsHandler struct {
+	Service        *services.AnalyticsService
+	MeetingService services.MeetingRepository
+	SegmentService services.SegmentRepository
+}
+
+func NewAnalyticsHandler(service *services.AnalyticsService, meetingService services.MeetingRepository, segmentService services.SegmentRepository) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		Service:        service,
+		MeetingService: meetingService,
+		SegmentService: segmentService,
+	}
+}
+
+// GetMeetingAnalytics returns a meeting's conversation analytics, computing
+// them on first request or when refresh=true
+func (h *AnalyticsHandler) GetMeetingAnalytics(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meeting ID"})
+		return
+	}
+
+	if c.Query("refresh") != "true" {
+		analytics, err := h.Service.Get(id)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if analytics != nil {
+			c.JSON(http.StatusOK, analytics)
+			return
+		}
+	}
+
+	meeting, err := h.MeetingService.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if meeting == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Meeting not found"})
+		return
+	}
+
+	analytics, err := h.Service.Compute(meeting)
+	if errors.Is(err, services.ErrNoTranscript) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Meeting has no transcript to analyze"})
+		return
+	}
+	if err != nil {
+		fmt.Printf("Analytics Error: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, analytics)
+}
+
+// GetTrends aggregates stored analytics of meetings between ?from= and ?to=
+// (YYYY-MM-DD, inclusive). Defaults to the last 90 days.
+func (h *AnalyticsHandler) GetTrends(c *gin.Context) {
+	to := time.Now()
+	from := to.AddDate(0, 0, -90)
+
+	if v := c.Query("from"); v != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'from' date, use YYYY-MM-DD"})
+			return
+		}
+		from = parsed
+	}
+	if v := c.Query("to"); v != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'to' date, use YYYY-MM-DD"})
+			return
+		}
+		to = parsed.AddDate(0, 0, 1)
+	}
+
+	trends, err := h.Service.Trends(from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, trends)
+}
+
+// GetSegments returns a meeting's speaker-labelled transcript segments
+func (h *AnalyticsHandler) GetSegments(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meeting ID"})
+		return
+	}
+
+	segments, err := h.SegmentService.GetByMeeting(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if segments == nil {
+		segments = []services.Segment{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"segments": segments})
+}
+
+// UpdateSegmentSpeaker labels a segment with its speaker. Like meeting
+// updates, it requires If-Match.
+func (h *AnalyticsHandler) UpdateSegmentSpeaker(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meeting ID"})
+		return
+	}
+	segmentID, err := strconv.Atoi(c.Param("segmentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid segment ID"})
+		return
+	}
+
+	var req struct {
+		Speaker string `json:"speaker" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	if !checkVersion(c, h.MeetingService, id) {
+		return
+	}
+
+	found, err := h.SegmentService.SetSpeaker(id, segmentID, req.Speaker)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Segment not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Speaker updated"})
+}
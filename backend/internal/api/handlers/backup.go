@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"backend/internal/services"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BackupHandler struct {
+	Service *services.BackupService
+}
+
+func NewBackupHandler(service *services.BackupService) *BackupHandler {
+	return &BackupHandler{Service: service}
+}
+
+// GetAll lists the backup archives, newest first
+func (h *BackupHandler) GetAll(c *gin.Context) {
+	backups, err := h.Service.List()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, backups)
+}
+
+// Create takes a backup of the running instance
+func (h *BackupHandler) Create(c *gin.Context) {
+	backup, err := h.Service.Create()
+	if errors.Is(err, services.ErrBackupRunning) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrBackupUnsupported) {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, backup)
+}
+
+// Download sends a backup archive
+func (h *BackupHandler) Download(c *gin.Context) {
+	path, ok := h.archive(c)
+	if !ok {
+		return
+	}
+
+	c.FileAttachment(path, c.Param("name"))
+}
+
+// Verify checks a backup archive against its manifest's checksums
+func (h *BackupHandler) Verify(c *gin.Context) {
+	path, ok := h.archive(c)
+	if !ok {
+		return
+	}
+
+	manifest, err := services.VerifyBackup(path)
+	if errors.Is(err, services.ErrInvalidBackup) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error(), "valid": false})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"valid": true, "manifest": manifest})
+}
+
+// Delete removes a backup archive
+func (h *BackupHandler) Delete(c *gin.Context) {
+	deleted, err := h.Service.Delete(c.Param("name"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Backup not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Backup deleted"})
+}
+
+// archive resolves the :name parameter to an archive, responding 404 if there is none
+func (h *BackupHandler) archive(c *gin.Context) (string, bool) {
+	path, err := h.Service.Path(c.Param("name"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return "", false
+	}
+	if path == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Backup not found"})
+		return "", false
+	}
+	return path, true
+}
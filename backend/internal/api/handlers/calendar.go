@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"backend/internal/services"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxCalendarUpload bounds uploaded .ics files
+const maxCalendarUpload = 10 << 20
+
+type CalendarHandler struct {
+	Service       *services.CalendarService
+	FolderService services.FolderRepository
+}
+
+func NewCalendarHandler(service *services.CalendarService, folderService services.FolderRepository) *CalendarHandler {
+	return &CalendarHandler{
+		Service:       service,
+		FolderService: folderService,
+	}
+}
+
+// Import creates scheduled meetings from a calendar, either an uploaded .ics
+// file (multipart "file") or an ICS feed URL ({"url": ...}). Both take an
+// optional folder_id for new meetings. Feeds imported this way are synced in
+// full, so events removed from the feed trash their scheduled meetings.
+func (h *CalendarHandler) Import(c *gin.Context) {
+	var folderID *int
+	var data []byte
+	var source string
+
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		file, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "No calendar file provided"})
+			return
+		}
+		if file.Size > maxCalendarUpload {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Calendar file is too large"})
+			return
+		}
+		f, err := file.Open()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		defer f.Close()
+		if data, err = io.ReadAll(f); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if v := c.PostForm("folder_id"); v != "" {
+			id, err := strconv.Atoi(v)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid folder ID"})
+				return
+			}
+			folderID = &id
+		}
+	} else {
+		var req struct {
+			URL      string `json:"url" binding:"required"`
+			FolderID *int   `json:"folder_id"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Upload an .ics file or send the feed's url"})
+			return
+		}
+		folderID = req.FolderID
+		source = strings.TrimSpace(req.URL)
+
+		var err error
+		if data, err = h.Service.Fetch(source); err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if folderID != nil {
+		folder, err := h.FolderService.GetByID(*folderID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if folder == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Folder not found"})
+			return
+		}
+	}
+
+	result, err := h.Service.Import(data, source, folderID)
+	if errors.Is(err, services.ErrInvalidCalendar) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
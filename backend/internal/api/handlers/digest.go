@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"backend/internal/services"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxDigestDays bounds a digest's range so a single request can't summarize the whole archive
+const maxDigestDays = 93
+
+type DigestHandler struct {
+	Service *services.DigestService
+}
+
+func NewDigestHandler(service *services.DigestService) *DigestHandler {
+	return &DigestHandler{Service: service}
+}
+
+// GetDigest returns the digest for ?from=YYYY-MM-DD&to=YYYY-MM-DD, generating
+// and storing it on first request (or when refresh=true). Without a range it
+// lists the stored digests. Digests cover the meetings of ?workspace= (default
+// workspace if omitted).
+func (h *DigestHandler) GetDigest(c *gin.Context) {
+	fromParam, toParam := c.Query("from"), c.Query("to")
+	if fromParam == "" && toParam == "" {
+		h.list(c)
+		return
+	}
+
+	from, err := services.ParseDigestDate(fromParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'from' date, use YYYY-MM-DD"})
+		return
+	}
+	to, err := services.ParseDigestDate(toParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'to' date, use YYYY-MM-DD"})
+		return
+	}
+	if to.Before(from) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "'to' must not be before 'from'"})
+		return
+	}
+	if to.Sub(from).Hours()/24 >= maxDigestDays {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Digest range is limited to %d days", maxDigestDays)})
+		return
+	}
+
+	if c.Query("refresh") != "true" {
+		digest, err := h.Service.GetForRange(workspaceParam(c), from, to)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if digest != nil {
+			c.JSON(http.StatusOK, digest)
+			return
+		}
+	}
+
+	digest, err := h.Service.Generate(workspaceParam(c), from, to)
+	if err != nil {
+		fmt.Printf("Digest Error: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, digest)
+}
+
+// GetOne returns a stored digest
+func (h *DigestHandler) GetOne(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid digest ID"})
+		return
+	}
+
+	digest, err := h.Service.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if digest == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Digest not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, digest)
+}
+
+func (h *DigestHandler) list(c *gin.Context) {
+	digests, err := h.Service.GetAll(workspaceParam(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if digests == nil {
+		digests = []services.Digest{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"digests": digests})
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"backend/internal/services"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExportHandler struct {
+	Service *services.ExportService
+}
+
+func NewExportHandler(service *services.ExportService) *ExportHandler {
+	return &ExportHandler{Service: service}
+}
+
+// Export downloads a meeting as ?format=md (default), html, pdf, docx or json,
+// or as a zip bundle of the Markdown, HTML and JSON exports with the audio
+func (h *ExportHandler) Export(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meeting ID"})
+		return
+	}
+
+	format := c.DefaultQuery("format", services.ExportMarkdown)
+	if !services.ValidExportFormat(format) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format, use " + strings.Join(services.ExportFormats, ", ")})
+		return
+	}
+
+	export, err := h.Service.Build(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if export == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Meeting not found"})
+		return
+	}
+
+	if format == services.ExportBundle {
+		c.Header("Content-Type", services.ExportContentType(format))
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, services.ExportFilename(export, format)))
+		c.Status(http.StatusOK)
+		// The response has started, so a failure can only be logged
+		if err := h.Service.WriteBundle(c.Writer, export); err != nil {
+			log.Printf("⚠️  Export bundle of meeting %d failed: %v", id, err)
+		}
+		return
+	}
+
+	data, err := h.Service.Render(export, format)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, services.ExportFilename(export, format)))
+	c.Data(http.StatusOK, services.ExportContentType(format), data)
+}
@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"backend/internal/services"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FolderHandler struct {
+	Service        services.FolderRepository
+	MeetingService services.MeetingRepository
+}
+
+func NewFolderHandler(service services.FolderRepository, meetingService services.MeetingRepository) *FolderHandler {
+	return &FolderHandler{
+		Service:        service,
+		MeetingService: meetingService,
+	}
+}
+
+type folderRequest struct {
+	Name     string `json:"name" binding:"required"`
+	ParentID *int   `json:"parent_id"`
+	services.FolderDefaults
+}
+
+// GetAll lists every folder with its meeting count
+func (h *FolderHandler) GetAll(c *gin.Context) {
+	folders, err := h.Service.GetAll()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"folders": folders})
+}
+
+// GetOne returns a folder and the defaults its new meetings inherit
+func (h *FolderHandler) GetOne(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid folder ID"})
+		return
+	}
+
+	folder, err := h.Service.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if folder == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Folder not found"})
+		return
+	}
+
+	defaults, err := h.Service.EffectiveDefaults(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"folder": folder, "effective_defaults": defaults})
+}
+
+// Create creates a folder, nested when parent_id is set
+func (h *FolderHandler) Create(c *gin.Context) {
+	var req folderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	folder, err := h.Service.Create(req.Name, req.ParentID, req.FolderDefaults)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, folder)
+}
+
+// Update renames or moves a folder and replaces its defaults
+func (h *FolderHandler) Update(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid folder ID"})
+		return
+	}
+
+	var req folderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	folder, err := h.Service.Update(id, req.Name, req.ParentID, req.FolderDefaults)
+	if errors.Is(err, services.ErrFolderCycle) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if folder == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Folder not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, folder)
+}
+
+// Delete removes a folder; its meetings and subfolders move to its parent
+func (h *FolderHandler) Delete(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid folder ID"})
+		return
+	}
+
+	if err := h.Service.Delete(id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Folder deleted"})
+}
+
+// MoveMeeting moves a meeting into {"folder_id": N}, or to the top level with null
+func (h *FolderHandler) MoveMeeting(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meeting ID"})
+		return
+	}
+
+	var req struct {
+		FolderID *int `json:"folder_id"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	if req.FolderID != nil {
+		folder, err := h.Service.GetByID(*req.FolderID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if folder == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Folder not found"})
+			return
+		}
+	}
+
+	found, err := h.Service.MoveMeeting(id, req.FolderID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Meeting not found"})
+		return
+	}
+
+	meeting, _ := h.MeetingService.GetByID(id)
+	c.JSON(http.StatusOK, meeting)
+}
+
+// folderScope reads ?folder= (a folder ID, or "root" for meetings outside any
+// folder) and ?recursive=true to include subfolders. It writes an error
+// response and returns ok=false on invalid input.
+func folderScope(c *gin.Context, folders services.FolderRepository) (ids []int, unfiled bool, ok bool) {
+	value := c.Query("folder")
+	if value == "" {
+		return nil, false, true
+	}
+	if value == "root" {
+		return nil, true, true
+	}
+
+	id, err := strconv.Atoi(value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid folder, use a folder ID or 'root'"})
+		return nil, false, false
+	}
+
+	folder, err := folders.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false, false
+	}
+	if folder == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Folder not found"})
+		return nil, false, false
+	}
+
+	if c.Query("recursive") != "true" {
+		return []int{id}, false, true
+	}
+
+	ids, err = folders.Subtree(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false, false
+	}
+	return ids, false, true
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"backend/internal/services"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FollowUpHandler struct {
+	MeetingService services.MeetingRepository
+	PeopleService  *services.PeopleService
+	GeminiService  *services.GeminiService
+	EmailService   *services.EmailService
+}
+
+func NewFollowUpHandler(meetingService services.MeetingRepository, people *services.PeopleService, gemini *services.GeminiService, email *services.EmailService) *FollowUpHandler {
+	return &FollowUpHandler{
+		MeetingService: meetingService,
+		PeopleService:  people,
+		GeminiService:  gemini,
+		EmailService:   email,
+	}
+}
+
+type FollowUpRequest struct {
+	Recipients []string `json:"recipients"`
+	Send       bool     `json:"send"`
+	// Email is a draft from an earlier response, possibly edited. When set it
+	// is used as is instead of drafting a new one, so the .eml or the sent
+	// email match what the user reviewed.
+	Email *services.FollowUpEmail `json:"email"`
+}
+
+// HandleFollowUp drafts a recap email for a meeting. It responds with JSON by
+// default, or with a downloadable .eml file when called with ?format=eml.
+// Setting "send" delivers the email through the configured SMTP server.
+// Without recipients, it is addressed to the participants with an email;
+// sending requires at least one.
+func (h *FollowUpHandler) HandleFollowUp(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meeting ID"})
+		return
+	}
+
+	var req FollowUpRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+			return
+		}
+	}
+
+	if req.Send && !h.EmailService.CanSend() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "SMTP is not configured"})
+		return
+	}
+
+	meeting, err := h.MeetingService.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if meeting == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Meeting not found"})
+		return
+	}
+
+	if len(req.Recipients) == 0 {
+		if req.Recipients, err = h.PeopleService.ParticipantEmails(id); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if req.Send && len(req.Recipients) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No recipients, pass them or add emails to the participants"})
+		return
+	}
+
+	email := req.Email
+	if email == nil {
+		if strings.TrimSpace(meeting.Notes) == "" && strings.TrimSpace(meeting.Transcript) == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Meeting has no notes or transcript"})
+			return
+		}
+
+		email, err = h.GeminiService.ForWorkspace(meeting.Workspace).DraftFollowUp(meeting.Title, meeting.Notes, meeting.Transcript)
+		if err != nil {
+			fmt.Printf("AI Error: %v\n", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	} else if strings.TrimSpace(email.Subject) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The email needs a subject"})
+		return
+	}
+
+	eml, err := h.EmailService.BuildEML(email, req.Recipients, time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sent := false
+	if req.Send {
+		if err := h.EmailService.Send(req.Recipients, eml); err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to send email: " + err.Error()})
+			return
+		}
+		sent = true
+		fmt.Printf("✉️  Follow-up for meeting %d sent to %d recipients\n", id, len(req.Recipients))
+	}
+
+	if c.Query("format") == "eml" {
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="meeting_%d_followup.eml"`, id))
+		c.Data(http.StatusOK, "message/rfc822", eml)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"email":      email,
+		"body":       h.EmailService.RenderText(email),
+		"recipients": req.Recipients,
+		"eml":        string(eml),
+		"sent":       sent,
+	})
+}
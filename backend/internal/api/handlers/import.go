@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"backend/internal/services"
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportTranscript bounds uploaded transcript files
+const maxImportTranscript = 20 << 20
+
+type ImportHandler struct {
+	Service       *services.ImportService
+	FolderService services.FolderRepository
+}
+
+func NewImportHandler(service *services.ImportService, folderService services.FolderRepository) *ImportHandler {
+	return &ImportHandler{
+		Service:       service,
+		FolderService: folderService,
+	}
+}
+
+// Import creates a meeting from another tool's transcript export, uploaded
+// as multipart "file" (.vtt, .srt, Teams .docx or Otter .txt) with an
+// optional "audio" recording. Optional fields: format, title, date
+// (YYYY-MM-DD or RFC 3339) and folder_id. Importing a file again returns
+// the existing meeting with duplicate set, restoring it if it was in the
+// trash.
+func (h *ImportHandler) Import(c *gin.Context) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No transcript file provided"})
+		return
+	}
+	if file.Size > maxImportTranscript {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Transcript file is too large"})
+		return
+	}
+
+	opts := services.ImportOptions{Title: c.PostForm("title")}
+	if format := c.PostForm("format"); format != "" {
+		if !services.ValidImportFormat(format) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format, use one of: " + strings.Join(services.ImportFormats, ", ")})
+			return
+		}
+		opts.Format = format
+	}
+	if v := c.PostForm("date"); v != "" {
+		date, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			if date, err = time.Parse(time.RFC3339, v); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date, use YYYY-MM-DD or RFC 3339"})
+				return
+			}
+		}
+		opts.Date = &date
+	}
+	if v := c.PostForm("folder_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid folder ID"})
+			return
+		}
+		folder, err := h.FolderService.GetByID(id)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if folder == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Folder not found"})
+			return
+		}
+		opts.FolderID = &id
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if audio, err := c.FormFile("audio"); err == nil {
+		a, err := audio.Open()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		defer a.Close()
+		opts.Audio, opts.AudioExt = a, filepath.Ext(audio.Filename)
+	}
+
+	result, err := h.Service.Import(file.Filename, data, opts)
+	if errors.Is(err, services.ErrInvalidTranscript) || errors.Is(err, services.ErrUnsupportedAudio) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	status := http.StatusCreated
+	if result.Duplicate {
+		status = http.StatusOK
+	}
+	c.JSON(status, result)
+}
@@ -2,37 +2,144 @@ package handlers
 
 import (
 	"backend/internal/services"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	defaultMeetingLimit = 50
+	maxMeetingLimit     = 200
+)
+
 type MeetingHandler struct {
-	MeetingService     *services.MeetingService
+	MeetingService     services.MeetingRepository
 	AudioMergerService *services.AudioMergerService
+	GeminiService      *services.GeminiService
+	TagService         services.TagRepository
+	FolderService      services.FolderRepository
+	TemplateService    *services.TemplateService
+	AutoTitle          bool // Global switch for generating titles after recording
+	AutoTag            bool // Global switch for suggesting tags after recording
 }
 
-func NewMeetingHandler(meetingService *services.MeetingService, audioMerger *services.AudioMergerService) *MeetingHandler {
+func NewMeetingHandler(meetingService services.MeetingRepository, audioMerger *services.AudioMergerService, gemini *services.GeminiService, tagService services.TagRepository, folderService services.FolderRepository, templateService *services.TemplateService, autoTitle, autoTag bool) *MeetingHandler {
 	return &MeetingHandler{
 		MeetingService:     meetingService,
 		AudioMergerService: audioMerger,
+		GeminiService:      gemini,
+		TagService:         tagService,
+		FolderService:      folderService,
+		TemplateService:    templateService,
+		AutoTitle:          autoTitle,
+		AutoTag:            autoTag,
 	}
 }
 
-// GetAll returns all meetings
+// GetAll returns a page of meeting summaries. Supports ?folder= with
+// ?recursive=true, ?tag=, ?type=,
+// ?from= and ?to= (YYYY-MM-DD, inclusive), ?status=, ?is_recording=, ?has_audio=,
+// ?sort= with ?order=asc|desc, ?limit= and ?cursor= from the previous page.
 func (h *MeetingHandler) GetAll(c *gin.Context) {
-	meetings, err := h.MeetingService.GetAll()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	filter := services.MeetingFilter{
+		Tag:    c.Query("tag"),
+		Type:   c.Query("type"),
+		Sort:   c.DefaultQuery("sort", "created_at"),
+		Cursor: c.Query("cursor"),
+		Limit:  defaultMeetingLimit,
+	}
+
+	if !services.ValidMeetingType(filter.Type) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown meeting type", "meeting_types": services.MeetingTypes})
+		return
+	}
+	var ok bool
+	if filter.FolderIDs, filter.Unfiled, ok = folderScope(c, h.FolderService); !ok {
+		return
+	}
+	if _, ok := services.MeetingSortFields[filter.Sort]; !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sort, use created_at, updated_at, title or duration"})
+		return
+	}
+
+	switch c.DefaultQuery("order", "desc") {
+	case "asc":
+		filter.Ascending = true
+	case "desc":
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order, use asc or desc"})
+		return
+	}
+
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxMeetingLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid limit, use 1 to %d", maxMeetingLimit)})
+			return
+		}
+		filter.Limit = limit
+	}
+
+	if v := c.Query("from"); v != "" {
+		from, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'from' date, use YYYY-MM-DD"})
+			return
+		}
+		filter.From = &from
+	}
+	if v := c.Query("to"); v != "" {
+		to, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'to' date, use YYYY-MM-DD"})
+			return
+		}
+		to = to.AddDate(0, 0, 1)
+		filter.To = &to
+	}
+
+	if filter.Status = c.Query("status"); filter.Status != "" && !services.ValidMeetingStatus(filter.Status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status, use " + strings.Join(services.MeetingStatuses, ", ")})
 		return
 	}
 
-	if meetings == nil {
-		meetings = []services.Meeting{}
+	if v := c.Query("person"); v != "" {
+		personID, err := strconv.Atoi(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid person ID"})
+			return
+		}
+		filter.PersonID = personID
+	}
+
+	for param, target := range map[string]**bool{"is_recording": &filter.IsRecording, "has_audio": &filter.HasAudio} {
+		if v := c.Query(param); v != "" {
+			value, err := strconv.ParseBool(v)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid '" + param + "', use true or false"})
+				return
+			}
+			*target = &value
+		}
+	}
+
+	page, err := h.MeetingService.List(filter)
+	if errors.Is(err, services.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"meetings": meetings})
+	c.JSON(http.StatusOK, page)
 }
 
 // GetOne returns a single meeting
@@ -54,33 +161,122 @@ func (h *MeetingHandler) GetOne(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", meetingETag(meeting))
 	c.JSON(http.StatusOK, meeting)
 }
 
-// Create creates a new meeting
+// Create creates a new meeting. With folder_id it is created in that folder
+// and inherits the folder's glossary, prompt template, retention and
+// workspace; workspace overrides the folder's. With template_id it is titled,
+// tagged and its notes laid out by that template, whose prompt replaces the
+// folder's.
 func (h *MeetingHandler) Create(c *gin.Context) {
 	var req struct {
-		Title string `json:"title"`
+		Title      string `json:"title"`
+		AutoTitle  *bool  `json:"auto_title"`
+		FolderID   *int   `json:"folder_id"`
+		TemplateID *int   `json:"template_id"`
+		Workspace  string `json:"workspace"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		req.Title = "Untitled Meeting"
+		req.Title = services.DefaultMeetingTitle
+	}
+
+	var template *services.MeetingTemplate
+	if req.TemplateID != nil {
+		var err error
+		if template, err = h.TemplateService.GetByID(*req.TemplateID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if template == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Template not found"})
+			return
+		}
 	}
 
+	autoTitle := true
+	if req.Title == "" && template != nil && template.TitlePattern != "" {
+		// The pattern is the title, generating one would replace it
+		req.Title = template.Title(time.Now())
+		autoTitle = false
+	}
 	if req.Title == "" {
-		req.Title = "Untitled Meeting"
+		req.Title = services.DefaultMeetingTitle
+	}
+	if req.AutoTitle != nil {
+		autoTitle = *req.AutoTitle
 	}
 
-	meeting, err := h.MeetingService.Create(req.Title)
+	var defaults services.FolderDefaults
+	if req.FolderID != nil {
+		folder, err := h.FolderService.GetByID(*req.FolderID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if folder == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Folder not found"})
+			return
+		}
+		if defaults, err = h.FolderService.EffectiveDefaults(folder.ID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if template != nil && template.Prompt != "" {
+		defaults.PromptTemplate = template.Prompt
+	}
+	if req.Workspace != "" {
+		if !services.ValidWorkspace(req.Workspace) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid workspace"})
+			return
+		}
+		defaults.Workspace = req.Workspace
+	}
+
+	meeting, err := h.MeetingService.Create(req.Title, autoTitle, req.FolderID, defaults)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	if template != nil {
+		if meeting, err = h.applyTemplate(meeting.ID, template, c.GetString("username")); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
 	c.JSON(http.StatusCreated, meeting)
 }
 
-// Update updates a meeting's title or notes
+// applyTemplate lays out a new meeting's notes and adds the template's tags
+func (h *MeetingHandler) applyTemplate(id int, template *services.MeetingTemplate, author string) (*services.Meeting, error) {
+	if err := h.MeetingService.SetTemplate(id, template.ID); err != nil {
+		return nil, err
+	}
+	if skeleton := template.Skeleton(); skeleton != "" {
+		if err := h.MeetingService.UpdateNotes(id, skeleton, author, services.NoteSourceTemplate); err != nil {
+			return nil, err
+		}
+	}
+	for _, tag := range template.Tags {
+		if _, err := h.TagService.AddToMeeting(id, tag, services.TagSourceManual); err != nil {
+			return nil, err
+		}
+	}
+
+	return h.MeetingService.GetByID(id)
+}
+
+// Update updates a meeting's title, notes, transcript, type or inherited
+// settings in one transaction. It requires an If-Match header carrying the
+// ETag from GetOne (or "*"): the edit only applies if nobody changed the
+// meeting since; otherwise it responds 412 with the current meeting so the
+// client can merge and retry.
 func (h *MeetingHandler) Update(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -89,8 +285,17 @@ func (h *MeetingHandler) Update(c *gin.Context) {
 	}
 
 	var req struct {
-		Title string `json:"title,omitempty"`
-		Notes string `json:"notes,omitempty"`
+		Title       string  `json:"title,omitempty"`
+		Notes       string  `json:"notes,omitempty"`
+		NotesSource string  `json:"notes_source,omitempty"` // manual (default) or the AI action that produced the notes
+		Transcript  *string `json:"transcript,omitempty"`
+		AutoTitle   *bool   `json:"auto_title,omitempty"`
+		MeetingType *string `json:"meeting_type,omitempty"`
+
+		Glossary       *string `json:"glossary,omitempty"`
+		PromptTemplate *string `json:"prompt_template,omitempty"`
+		RetentionDays  *int    `json:"retention_days,omitempty"`
+		Workspace      *string `json:"workspace,omitempty"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -98,25 +303,152 @@ func (h *MeetingHandler) Update(c *gin.Context) {
 		return
 	}
 
+	// Empty title and notes mean unchanged, as before merge patches existed
+	patch := services.MeetingPatch{
+		NotesSource:    req.NotesSource,
+		Transcript:     req.Transcript,
+		AutoTitle:      req.AutoTitle,
+		MeetingType:    req.MeetingType,
+		Glossary:       req.Glossary,
+		PromptTemplate: req.PromptTemplate,
+		RetentionDays:  req.RetentionDays,
+		Workspace:      req.Workspace,
+	}
 	if req.Title != "" {
-		if err := h.MeetingService.UpdateTitle(id, req.Title); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
+		patch.Title = &req.Title
 	}
-
 	if req.Notes != "" {
-		if err := h.MeetingService.UpdateNotes(id, req.Notes); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
+		patch.Notes = &req.Notes
+	}
+
+	h.applyPatch(c, id, patch)
+}
+
+// Patch applies a JSON Merge Patch (RFC 7396) to a meeting's editable fields
+// in one transaction. Fields set to null are cleared, absent fields are left
+// alone. Like Update, it requires If-Match.
+func (h *MeetingHandler) Patch(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meeting ID"})
+		return
+	}
+
+	var fields map[string]json.RawMessage
+	if err := c.ShouldBindJSON(&fields); err != nil || fields == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request, send a JSON object of the fields to change"})
+		return
+	}
+
+	patch, err := parseMeetingPatch(fields)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.applyPatch(c, id, patch)
+}
+
+// applyPatch validates patch, then claims the If-Match version and applies
+// it in one transaction, responding with the updated meeting and its ETag
+func (h *MeetingHandler) applyPatch(c *gin.Context, id int, patch services.MeetingPatch) {
+	if err := patch.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	versions, ok := ifMatchVersions(c)
+	if !ok {
+		return
+	}
+
+	meeting, err := h.MeetingService.Patch(id, patch, c.GetString("username"), versions)
+	switch {
+	case errors.Is(err, services.ErrInvalidPatch):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrStillRecording):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrVersionConflict):
+		preconditionFailed(c, h.MeetingService, id)
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	case meeting == nil:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Meeting not found"})
+	default:
+		c.Header("ETag", meetingETag(meeting))
+		c.JSON(http.StatusOK, meeting)
+	}
+}
+
+// parseMeetingPatch maps a merge patch document onto a MeetingPatch. A null
+// value decodes to the field's zero value; unknown and read-only fields are rejected.
+func parseMeetingPatch(fields map[string]json.RawMessage) (services.MeetingPatch, error) {
+	var patch services.MeetingPatch
+	for name, raw := range fields {
+		var err error
+		switch name {
+		case "title":
+			patch.Title, err = patchString(raw)
+		case "description":
+			patch.Description, err = patchString(raw)
+		case "notes":
+			patch.Notes, err = patchString(raw)
+		case "transcript":
+			patch.Transcript, err = patchString(raw)
+		case "meeting_type":
+			patch.MeetingType, err = patchString(raw)
+		case "language":
+			patch.Language, err = patchString(raw)
+		case "glossary":
+			patch.Glossary, err = patchString(raw)
+		case "prompt_template":
+			patch.PromptTemplate, err = patchString(raw)
+		case "workspace":
+			patch.Workspace, err = patchString(raw)
+		case "auto_title":
+			autoTitle := true // null restores the default
+			err = decodePatchValue(raw, &autoTitle)
+			patch.AutoTitle = &autoTitle
+		case "retention_days":
+			var days int
+			err = decodePatchValue(raw, &days)
+			patch.RetentionDays = &days
+		case "tags":
+			tags := []string{}
+			err = decodePatchValue(raw, &tags)
+			patch.Tags = &tags
+		case "participants":
+			participants := []string{}
+			err = decodePatchValue(raw, &participants)
+			patch.Participants = &participants
+		case "folder_id":
+			patch.SetFolder = true
+			err = decodePatchValue(raw, &patch.FolderID)
+		default:
+			return patch, fmt.Errorf("unknown or read-only field %q", name)
+		}
+		if err != nil {
+			return patch, fmt.Errorf("invalid value for %s", name)
 		}
 	}
+	return patch, nil
+}
 
-	meeting, _ := h.MeetingService.GetByID(id)
-	c.JSON(http.StatusOK, meeting)
+func patchString(raw json.RawMessage) (*string, error) {
+	var value string
+	err := decodePatchValue(raw, &value)
+	return &value, err
+}
+
+// decodePatchValue decodes raw into dest, leaving dest untouched for null
+func decodePatchValue(raw json.RawMessage, dest interface{}) error {
+	if string(raw) == "null" {
+		return nil
+	}
+	return json.Unmarshal(raw, dest)
 }
 
-// Delete removes a meeting
+// Delete moves a meeting to the trash; it is purged later or via DELETE /trash/:id
 func (h *MeetingHandler) Delete(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -124,22 +456,57 @@ func (h *MeetingHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	// Get meeting to find audio path
-	meeting, _ := h.MeetingService.GetByID(id)
-	if meeting != nil {
-		// Clean up audio files
-		h.AudioMergerService.CleanupMeeting(id, meeting.AudioPath)
+	found, err := h.MeetingService.Trash(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Meeting not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Meeting moved to trash"})
+}
+
+// Start turns a scheduled meeting into a recording that starts now
+func (h *MeetingHandler) Start(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meeting ID"})
+		return
+	}
+
+	meeting, err := h.MeetingService.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if meeting == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Meeting not found"})
+		return
 	}
 
-	if err := h.MeetingService.Delete(id); err != nil {
+	started, err := h.MeetingService.Start(id)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	if !started {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only scheduled meetings can be started", "status": meeting.Status})
+		return
+	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Meeting deleted"})
+	meeting, _ = h.MeetingService.GetByID(id)
+	if meeting != nil {
+		c.Header("ETag", meetingETag(meeting))
+	}
+	c.JSON(http.StatusOK, meeting)
 }
 
-// FinishRecording merges audio chunks and marks recording complete
+// FinishRecording merges audio chunks and marks recording complete. The title
+// and tags are generated after responding, so the client doesn't wait on
+// the AI; they show up on the next fetch.
 func (h *MeetingHandler) FinishRecording(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -160,6 +527,145 @@ func (h *MeetingHandler) FinishRecording(c *gin.Context) {
 		return
 	}
 
-	meeting, _ := h.MeetingService.GetByID(id)
+	meeting, err := h.MeetingService.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if meeting == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Meeting not found"})
+		return
+	}
+
 	c.JSON(http.StatusOK, meeting)
+
+	go func() {
+		h.generateTitle(meeting)
+		h.suggestTags(meeting)
+	}()
+}
+
+// generateTitle names a meeting from its transcript when it still has the
+// default title and auto titling is enabled. Failures are logged, not returned,
+// since a missing title should never fail the recording.
+func (h *MeetingHandler) generateTitle(meeting *services.Meeting) {
+	if !h.AutoTitle || meeting == nil || !meeting.AutoTitle || meeting.Title != services.DefaultMeetingTitle {
+		return
+	}
+	if strings.TrimSpace(meeting.Transcript) == "" {
+		return
+	}
+
+	title, err := h.GeminiService.ForWorkspace(meeting.Workspace).GenerateTitle(meeting.Transcript)
+	if err != nil {
+		fmt.Printf("⚠️  Title generation failed for meeting %d: %v\n", meeting.ID, err)
+		return
+	}
+
+	applied, err := h.MeetingService.ApplyGeneratedTitle(meeting.ID, title.Title, title.Description)
+	if err != nil {
+		fmt.Printf("⚠️  Failed to store generated title for meeting %d: %v\n", meeting.ID, err)
+		return
+	}
+
+	if applied {
+		fmt.Printf("🏷️  Meeting %d titled: %s\n", meeting.ID, title.Title)
+	}
+}
+
+// suggestTags classifies a finished meeting from its transcript. Like
+// generateTitle, failures are only logged.
+func (h *MeetingHandler) suggestTags(meeting *services.Meeting) {
+	if !h.AutoTag || meeting == nil || strings.TrimSpace(meeting.Transcript) == "" {
+		return
+	}
+
+	existing, err := h.TagService.Names()
+	if err != nil {
+		fmt.Printf("⚠️  Failed to load tags for meeting %d: %v\n", meeting.ID, err)
+		return
+	}
+
+	classification, err := h.GeminiService.ForWorkspace(meeting.Workspace).ClassifyMeeting(meeting.Transcript, existing)
+	if err != nil {
+		fmt.Printf("⚠️  Tag suggestion failed for meeting %d: %v\n", meeting.ID, err)
+		return
+	}
+
+	if err := h.TagService.ApplyClassification(meeting.ID, classification); err != nil {
+		fmt.Printf("⚠️  Failed to store suggested tags for meeting %d: %v\n", meeting.ID, err)
+		return
+	}
+
+	fmt.Printf("🏷️  Meeting %d classified as %s: %s\n", meeting.ID, classification.MeetingType, strings.Join(classification.Tags, ", "))
+}
+
+// meetingETag is the entity tag of a meeting's current version
+func meetingETag(meeting *services.Meeting) string {
+	return fmt.Sprintf(`"%d"`, meeting.Version)
+}
+
+// ifMatchVersions parses the If-Match header into meeting versions. It
+// returns nil for "*", which edits whatever the current version is. It
+// responds 428 when the header is missing and 400 when it is malformed, and
+// returns ok=false.
+func ifMatchVersions(c *gin.Context) (versions []int, ok bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		c.JSON(http.StatusPreconditionRequired, gin.H{"error": "Missing If-Match header, send the ETag of the meeting you edited"})
+		return nil, false
+	}
+	if header == "*" {
+		return nil, true
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		version, err := strconv.Atoi(strings.Trim(tag, `"`))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid If-Match header, use the ETag of the meeting"})
+			return nil, false
+		}
+		versions = append(versions, version)
+	}
+	return versions, true
+}
+
+// checkVersion enforces If-Match before an edit to a meeting. When the
+// meeting changed in the meantime it responds 412 with the current meeting
+// and its ETag, and returns false.
+func checkVersion(c *gin.Context, meetings services.MeetingRepository, id int) bool {
+	versions, ok := ifMatchVersions(c)
+	if !ok {
+		return false
+	}
+	if versions == nil {
+		return true
+	}
+
+	claimed, err := meetings.ClaimVersion(id, versions)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	if !claimed {
+		preconditionFailed(c, meetings, id)
+	}
+	return claimed
+}
+
+// preconditionFailed responds 412 with the meeting's current state and ETag
+func preconditionFailed(c *gin.Context, meetings services.MeetingRepository, id int) {
+	current, err := meetings.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if current == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Meeting not found"})
+		return
+	}
+
+	c.Header("ETag", meetingETag(current))
+	c.JSON(http.StatusPreconditionFailed, gin.H{"error": "The meeting was changed since you loaded it", "meeting": current})
 }
@@ -0,0 +1,923 @@
+package handlers_test
+
+import (
+	"backend/internal/api"
+	"backend/internal/config"
+	"backend/internal/database"
+	"backend/internal/services"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type meetingTestServer struct {
+	router *gin.Engine
+	repos  services.Repositories
+	store  *services.MemoryStore // Only set when serving from memory
+	audio  *services.AudioMergerService
+	token  string
+}
+
+// newMeetingTestServer serves the API from an in-memory store, with AI
+// titles and tags switched off
+func newMeetingTestServer(t *testing.T) *meetingTestServer {
+	t.Helper()
+	store, err := services.NewMemoryStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	s := serveRepositories(t, store.Repositories())
+	s.store = store
+	return s
+}
+
+// newSQLMeetingTestServer serves the API from a SQLite database, for the
+// routes whose services only run on SQL storage, like participants and
+// revisions
+func newSQLMeetingTestServer(t *testing.T) *meetingTestServer {
+	t.Helper()
+	db, err := database.OpenSQLite(filepath.Join(t.TempDir(), "echo.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return serveRepositories(t, services.NewSQLRepositories(db, database.SQLite))
+}
+
+// serveRepositories serves the API from repos and logs in
+func serveRepositories(t *testing.T, repos services.Repositories) *meetingTestServer {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	storage := t.TempDir()
+	for _, dir := range []string{"audio", "temp"} {
+		if err := os.MkdirAll(filepath.Join(storage, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg := &config.Config{
+		GeminiAPIKey: "test", // Never used, AI features are off or not exercised
+		AuthUsername: "tester",
+		AuthPassword: "secret",
+		StoragePath:  storage,
+		BackupDir:    filepath.Join(storage, "backups"),
+	}
+
+	s := &meetingTestServer{
+		router: api.SetupRouter(cfg, repos),
+		repos:  repos,
+		audio:  services.NewAudioMergerService(storage),
+	}
+	w := s.do(t, http.MethodPost, "/auth/login", `{"username": "tester", "password": "secret"}`)
+	expectStatus(t, w, http.StatusOK)
+	s.token = decodeBody[map[string]string](t, w)["token"]
+	return s
+}
+
+// createMeeting creates a meeting through the API
+func (s *meetingTestServer) createMeeting(t *testing.T, title string) services.Meeting {
+	t.Helper()
+	w := s.do(t, http.MethodPost, "/meetings", `{"title": "`+title+`"}`)
+	expectStatus(t, w, http.StatusCreated)
+	return decodeBody[services.Meeting](t, w)
+}
+
+// do sends a request with an optional JSON body and header name/value pairs
+func (s *meetingTestServer) do(t *testing.T, method, path, body string, headers ...string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+func expectStatus(t *testing.T, w *httptest.ResponseRecorder, status int) {
+	t.Helper()
+	if w.Code != status {
+		t.Fatalf("expected status %d, got %d: %s", status, w.Code, w.Body.String())
+	}
+}
+
+func decodeBody[T any](t *testing.T, w *httptest.ResponseRecorder) T {
+	t.Helper()
+	var v T
+	if err := json.Unmarshal(w.Body.Bytes(), &v); err != nil {
+		t.Fatalf("invalid response body %q: %v", w.Body.String(), err)
+	}
+	return v
+}
+
+func meetingTitles(page services.MeetingPage) []string {
+	titles := []string{}
+	for _, m := range page.Meetings {
+		titles = append(titles, m.Title)
+	}
+	return titles
+}
+
+func TestListMeetings(t *testing.T) {
+	s := newMeetingTestServer(t)
+	parent, err := s.store.Folders.Create("Clients", nil, services.FolderDefaults{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	child, err := s.store.Folders.Create("Acme", &parent.ID, services.FolderDefaults{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	day := time.Date(2026, 3, 2, 10, 0, 0, 0, time.Local)
+	s.store.Meetings.Add(services.Meeting{Title: "Kickoff", CreatedAt: day, FolderID: &parent.ID, Tags: []string{"Acme"}, AudioPath: "/audio/1.webm", DurationSeconds: 300})
+	s.store.Meetings.Add(services.Meeting{Title: "Standup", CreatedAt: day.AddDate(0, 0, 1), MeetingType: "standup", DurationSeconds: 60})
+	s.store.Meetings.Add(services.Meeting{Title: "Review", CreatedAt: day.AddDate(0, 0, 2), FolderID: &child.ID, Tags: []string{"acme"}, DurationSeconds: 1800})
+	s.store.Meetings.Add(services.Meeting{Title: "Planning", Status: services.MeetingStatusScheduled, CreatedAt: day.AddDate(0, 0, 3)})
+	trashed := s.store.Meetings.Add(services.Meeting{Title: "Deleted", CreatedAt: day.AddDate(0, 0, 4)})
+	if _, err := s.store.Meetings.Trash(trashed.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"Planning", "Review", "Standup", "Kickoff"}},
+		{"?order=asc", []string{"Kickoff", "Standup", "Review", "Planning"}},
+		{"?sort=title&order=asc", []string{"Kickoff", "Planning", "Review", "Standup"}},
+		{"?sort=duration", []string{"Review", "Kickoff", "Standup", "Planning"}},
+		{"?tag=ACME", []string{"Review", "Kickoff"}},
+		{"?type=standup", []string{"Standup"}},
+		{"?folder=root", []string{"Planning", "Standup"}},
+		{"?folder=" + strconv.Itoa(parent.ID), []string{"Kickoff"}},
+		{"?folder=" + strconv.Itoa(parent.ID) + "&recursive=true", []string{"Review", "Kickoff"}},
+		{"?status=scheduled", []string{"Planning"}},
+		{"?has_audio=true", []string{"Kickoff"}},
+		{"?from=2026-03-03&to=2026-03-04", []string{"Review", "Standup"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			w := s.do(t, http.MethodGet, "/meetings"+tt.query, "")
+			expectStatus(t, w, http.StatusOK)
+			if got := meetingTitles(decodeBody[services.MeetingPage](t, w)); strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	invalid := []struct {
+		query  string
+		status int
+	}{
+		{"?sort=notes", http.StatusBadRequest},
+		{"?order=up", http.StatusBadRequest},
+		{"?limit=0", http.StatusBadRequest},
+		{"?limit=201", http.StatusBadRequest},
+		{"?type=party", http.StatusBadRequest},
+		{"?status=paused", http.StatusBadRequest},
+		{"?from=yesterday", http.StatusBadRequest},
+		{"?has_audio=maybe", http.StatusBadRequest},
+		{"?person=bob", http.StatusBadRequest},
+		{"?cursor=nonsense", http.StatusBadRequest},
+		{"?folder=abc", http.StatusBadRequest},
+		{"?folder=999", http.StatusNotFound},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.query, func(t *testing.T) {
+			expectStatus(t, s.do(t, http.MethodGet, "/meetings"+tt.query, ""), tt.status)
+		})
+	}
+}
+
+func TestListMeetingsPages(t *testing.T) {
+	s := newMeetingTestServer(t)
+	start := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		// Two meetings share each start time, so paging relies on the ID tiebreak
+		s.store.Meetings.Add(services.Meeting{Title: strconv.Itoa(i), CreatedAt: start.Add(time.Duration(i/2) * time.Hour)})
+	}
+
+	var titles []string
+	cursor := ""
+	for pages := 0; pages < 5; pages++ {
+		w := s.do(t, http.MethodGet, "/meetings?limit=2&cursor="+cursor, "")
+		expectStatus(t, w, http.StatusOK)
+		page := decodeBody[services.MeetingPage](t, w)
+		titles = append(titles, meetingTitles(page)...)
+		if cursor = page.NextCursor; cursor == "" {
+			break
+		}
+	}
+	if got := strings.Join(titles, ","); got != "4,3,2,1,0" {
+		t.Errorf("expected pages to list 4,3,2,1,0, got %s", got)
+	}
+
+	w := s.do(t, http.MethodGet, "/meetings?limit=2", "")
+	next := decodeBody[services.MeetingPage](t, w).NextCursor
+	expectStatus(t, s.do(t, http.MethodGet, "/meetings?sort=title&cursor="+next, ""), http.StatusBadRequest)
+}
+
+func TestGetMeeting(t *testing.T) {
+	s := newMeetingTestServer(t)
+	m := s.store.Meetings.Add(services.Meeting{Title: "Retro", Participants: []string{"Ana"}, Tags: []string{"team"}})
+
+	w := s.do(t, http.MethodGet, "/meetings/"+strconv.Itoa(m.ID), "")
+	expectStatus(t, w, http.StatusOK)
+	got := decodeBody[services.Meeting](t, w)
+	if got.Title != "Retro" || strings.Join(got.Tags, ",") != "team" || strings.Join(got.Participants, ",") != "Ana" {
+		t.Errorf("unexpected meeting %+v", got)
+	}
+	if etag := w.Header().Get("ETag"); etag != `"1"` {
+		t.Errorf(`expected ETag "1", got %s`, etag)
+	}
+
+	expectStatus(t, s.do(t, http.MethodGet, "/meetings/abc", ""), http.StatusBadRequest)
+	expectStatus(t, s.do(t, http.MethodGet, "/meetings/999", ""), http.StatusNotFound)
+
+	if _, err := s.store.Meetings.Trash(m.ID); err != nil {
+		t.Fatal(err)
+	}
+	expectStatus(t, s.do(t, http.MethodGet, "/meetings/"+strconv.Itoa(m.ID), ""), http.StatusNotFound)
+}
+
+func TestCreateMeeting(t *testing.T) {
+	s := newMeetingTestServer(t)
+
+	w := s.do(t, http.MethodPost, "/meetings", "")
+	expectStatus(t, w, http.StatusCreated)
+	m := decodeBody[services.Meeting](t, w)
+	if m.Title != services.DefaultMeetingTitle || !m.AutoTitle || !m.IsRecording || m.Status != services.MeetingStatusRecording {
+		t.Errorf("unexpected new meeting %+v", m)
+	}
+
+	w = s.do(t, http.MethodPost, "/meetings", `{"title": "Weekly sync", "auto_title": false}`)
+	expectStatus(t, w, http.StatusCreated)
+	if m := decodeBody[services.Meeting](t, w); m.Title != "Weekly sync" || m.AutoTitle {
+		t.Errorf("unexpected new meeting %+v", m)
+	}
+
+	parent, err := s.store.Folders.Create("Clients", nil, services.FolderDefaults{Glossary: "Acme", RetentionDays: 30})
+	if err != nil {
+		t.Fatal(err)
+	}
+	child, err := s.store.Folders.Create("Acme", &parent.ID, services.FolderDefaults{PromptTemplate: "Use bullet points"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w = s.do(t, http.MethodPost, "/meetings", `{"folder_id": `+strconv.Itoa(child.ID)+`}`)
+	expectStatus(t, w, http.StatusCreated)
+	m = decodeBody[services.Meeting](t, w)
+	if m.FolderID == nil || *m.FolderID != child.ID {
+		t.Errorf("expected folder %d, got %v", child.ID, m.FolderID)
+	}
+	if m.Glossary != "Acme" || m.PromptTemplate != "Use bullet points" || m.RetentionDays != 30 {
+		t.Errorf("expected inherited folder defaults, got %+v", m)
+	}
+
+	expectStatus(t, s.do(t, http.MethodPost, "/meetings", `{"folder_id": 999}`), http.StatusNotFound)
+}
+
+func TestUpdateMeeting(t *testing.T) {
+	s := newMeetingTestServer(t)
+	m := s.store.Meetings.Add(services.Meeting{Title: "Standup", Transcript: "hello"})
+	path := "/meetings/" + strconv.Itoa(m.ID)
+
+	w := s.do(t, http.MethodPut, path, `{"title": "Daily standup", "notes": "<p>Shipped</p>", "meeting_type": "standup", "retention_days": 14}`, "If-Match", `"1"`)
+	expectStatus(t, w, http.StatusOK)
+	got := decodeBody[services.Meeting](t, w)
+	if got.Title != "Daily standup" || got.Notes != "<p>Shipped</p>" || got.MeetingType != "standup" || got.RetentionDays != 14 {
+		t.Errorf("update not applied: %+v", got)
+	}
+	if w.Header().Get("ETag") != `"2"` || got.Version != 2 {
+		t.Errorf("expected one version bump for the whole update, got ETag %s for version %d", w.Header().Get("ETag"), got.Version)
+	}
+
+	// The ETag from before the first update is stale now
+	w = s.do(t, http.MethodPut, path, `{"title": "Lost"}`, "If-Match", `"1"`)
+	expectStatus(t, w, http.StatusPreconditionFailed)
+	if current := decodeBody[struct{ Meeting services.Meeting }](t, w).Meeting; current.Title != "Daily standup" {
+		t.Errorf("expected the current meeting in the 412 response, got %+v", current)
+	}
+
+	expectStatus(t, s.do(t, http.MethodPut, path, `{"title": "Unversioned"}`), http.StatusPreconditionRequired)
+
+	w = s.do(t, http.MethodPut, path, `{"transcript": "edited"}`, "If-Match", "*")
+	expectStatus(t, w, http.StatusOK)
+	if got := decodeBody[services.Meeting](t, w); got.Transcript != "edited" {
+		t.Errorf("expected edited transcript, got %q", got.Transcript)
+	}
+
+	recording := s.store.Meetings.Add(services.Meeting{Title: "Live", IsRecording: true, Status: services.MeetingStatusRecording})
+	expectStatus(t, s.do(t, http.MethodPut, "/meetings/"+strconv.Itoa(recording.ID), `{"transcript": "edited"}`, "If-Match", "*"), http.StatusConflict)
+
+	expectStatus(t, s.do(t, http.MethodPut, path, `{"meeting_type": "party"}`), http.StatusBadRequest)
+	expectStatus(t, s.do(t, http.MethodPut, path, `{"notes": "x", "notes_source": "robot"}`), http.StatusBadRequest)
+	expectStatus(t, s.do(t, http.MethodPut, path, `{"retention_days": -1}`), http.StatusBadRequest)
+	expectStatus(t, s.do(t, http.MethodPut, path, `not json`), http.StatusBadRequest)
+	expectStatus(t, s.do(t, http.MethodPut, path, `{"title": "x"}`, "If-Match", "latest"), http.StatusBadRequest)
+	expectStatus(t, s.do(t, http.MethodPut, "/meetings/999", `{"title": "x"}`, "If-Match", "*"), http.StatusNotFound)
+	expectStatus(t, s.do(t, http.MethodPut, "/meetings/abc", `{"title": "x"}`), http.StatusBadRequest)
+}
+
+func TestPatchMeeting(t *testing.T) {
+	s := newMeetingTestServer(t)
+	folder, err := s.store.Folders.Create("Team", nil, services.FolderDefaults{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := s.store.Meetings.Add(services.Meeting{Title: "Retro", Description: "Sprint 12", Tags: []string{"old"}, Participants: []string{"Ana"}})
+	path := "/meetings/" + strconv.Itoa(m.ID)
+
+	w := s.do(t, http.MethodPatch, path, `{
+		"title": "  ",
+		"description": null,
+		"tags": ["Team Health", "retro"],
+		"participants": ["ana", "Ben", "ben "],
+		"folder_id": `+strconv.Itoa(folder.ID)+`,
+		"language": "de"
+	}`, "If-Match", `W/"1"`)
+	expectStatus(t, w, http.StatusOK)
+	got := decodeBody[services.Meeting](t, w)
+	if got.Title != services.DefaultMeetingTitle || got.Description != "" || got.Language != "de" {
+		t.Errorf("patch not applied: %+v", got)
+	}
+	if tags := strings.Join(got.Tags, ","); tags != "retro,team-health" {
+		t.Errorf("expected tags retro,team-health, got %s", tags)
+	}
+	if participants := strings.Join(got.Participants, ","); participants != "Ana,Ben" {
+		t.Errorf("expected participants Ana,Ben, got %s", participants)
+	}
+	if got.FolderID == nil || *got.FolderID != folder.ID {
+		t.Errorf("expected folder %d, got %v", folder.ID, got.FolderID)
+	}
+	if w.Header().Get("ETag") != `"2"` {
+		t.Errorf(`expected ETag "2", got %s`, w.Header().Get("ETag"))
+	}
+
+	w = s.do(t, http.MethodPatch, path, `{"folder_id": null}`, "If-Match", "*")
+	expectStatus(t, w, http.StatusOK)
+	if got := decodeBody[services.Meeting](t, w); got.FolderID != nil {
+		t.Errorf("expected the meeting at the top level, got folder %d", *got.FolderID)
+	}
+
+	// Limits count characters, not bytes
+	expectStatus(t, s.do(t, http.MethodPatch, path, `{"title": "`+strings.Repeat("é", 200)+`"}`, "If-Match", "*"), http.StatusOK)
+	expectStatus(t, s.do(t, http.MethodPatch, path, `{"title": "`+strings.Repeat("é", 201)+`"}`, "If-Match", "*"), http.StatusBadRequest)
+
+	expectStatus(t, s.do(t, http.MethodPatch, path, `{"title": "Stale"}`, "If-Match", `"1"`), http.StatusPreconditionFailed)
+	expectStatus(t, s.do(t, http.MethodPatch, path, `{"title": "x"}`, "If-Match", "latest"), http.StatusBadRequest)
+	expectStatus(t, s.do(t, http.MethodPatch, path, `{"version": 7}`), http.StatusBadRequest)
+	expectStatus(t, s.do(t, http.MethodPatch, path, `{"retention_days": "forever"}`), http.StatusBadRequest)
+	expectStatus(t, s.do(t, http.MethodPatch, path, `{"language": "German"}`), http.StatusBadRequest)
+	expectStatus(t, s.do(t, http.MethodPatch, path, `{"folder_id": 999}`, "If-Match", "*"), http.StatusBadRequest)
+	expectStatus(t, s.do(t, http.MethodPatch, path, `[]`), http.StatusBadRequest)
+	expectStatus(t, s.do(t, http.MethodPatch, "/meetings/999", `{"title": "x"}`, "If-Match", "*"), http.StatusNotFound)
+	expectStatus(t, s.do(t, http.MethodPatch, path, `{"title": "x"}`), http.StatusPreconditionRequired)
+
+	recording := s.store.Meetings.Add(services.Meeting{Title: "Live", IsRecording: true, Status: services.MeetingStatusRecording})
+	expectStatus(t, s.do(t, http.MethodPatch, "/meetings/"+strconv.Itoa(recording.ID), `{"transcript": "edited"}`, "If-Match", "*"), http.StatusConflict)
+}
+
+func TestMeetingWorkspace(t *testing.T) {
+	s := newMeetingTestServer(t)
+
+	w := s.do(t, http.MethodPost, "/folders", `{"name": "Acme", "workspace": "acme"}`)
+	expectStatus(t, w, http.StatusCreated)
+	parent := decodeBody[services.Folder](t, w)
+	w = s.do(t, http.MethodPost, "/folders", `{"name": "Calls", "parent_id": `+strconv.Itoa(parent.ID)+`}`)
+	expectStatus(t, w, http.StatusCreated)
+	child := decodeBody[services.Folder](t, w)
+	expectStatus(t, s.do(t, http.MethodPost, "/folders", `{"name": "Bad", "workspace": "a b"}`), http.StatusBadRequest)
+
+	tests := []struct {
+		body string
+		want string
+	}{
+		{``, services.DefaultWorkspace},
+		{`{"folder_id": ` + strconv.Itoa(child.ID) + `}`, "acme"},
+		{`{"folder_id": ` + strconv.Itoa(child.ID) + `, "workspace": "beta"}`, "beta"},
+		{`{"workspace": "beta"}`, "beta"},
+	}
+	var created services.Meeting
+	for _, tt := range tests {
+		w := s.do(t, http.MethodPost, "/meetings", tt.body)
+		expectStatus(t, w, http.StatusCreated)
+		if created = decodeBody[services.Meeting](t, w); created.Workspace != tt.want {
+			t.Errorf("%s: expected workspace %s, got %s", tt.body, tt.want, created.Workspace)
+		}
+	}
+	expectStatus(t, s.do(t, http.MethodPost, "/meetings", `{"workspace": "a/b"}`), http.StatusBadRequest)
+
+	path := "/meetings/" + strconv.Itoa(created.ID)
+	w = s.do(t, http.MethodPatch, path, `{"workspace": "acme"}`, "If-Match", "*")
+	expectStatus(t, w, http.StatusOK)
+	if got := decodeBody[services.Meeting](t, w); got.Workspace != "acme" {
+		t.Errorf("expected workspace acme, got %s", got.Workspace)
+	}
+	w = s.do(t, http.MethodPatch, path, `{"workspace": null}`, "If-Match", "*")
+	expectStatus(t, w, http.StatusOK)
+	if got := decodeBody[services.Meeting](t, w); got.Workspace != services.DefaultWorkspace {
+		t.Errorf("expected null to reset the workspace, got %s", got.Workspace)
+	}
+	w = s.do(t, http.MethodPut, path, `{"workspace": "acme"}`, "If-Match", "*")
+	expectStatus(t, w, http.StatusOK)
+	if got := decodeBody[services.Meeting](t, w); got.Workspace != "acme" {
+		t.Errorf("expected workspace acme, got %s", got.Workspace)
+	}
+	expectStatus(t, s.do(t, http.MethodPatch, path, `{"workspace": "a b"}`, "If-Match", "*"), http.StatusBadRequest)
+
+	// A digest only covers its workspace's meetings: summarizing the default
+	// workspace's meeting would need Gemini, which isn't reachable here
+	day := time.Now().Format("2006-01-02")
+	s.store.Meetings.Add(services.Meeting{Title: "Secret", Notes: "Acme's numbers"})
+	w = s.do(t, http.MethodGet, "/digests?workspace=beta&from="+day+"&to="+day, "")
+	expectStatus(t, w, http.StatusOK)
+	if digest := decodeBody[services.Digest](t, w); digest.Workspace != "beta" || len(digest.Meetings) != 0 {
+		t.Errorf("expected an empty beta digest, got %+v", digest)
+	}
+	w = s.do(t, http.MethodGet, "/digests?workspace=beta", "")
+	expectStatus(t, w, http.StatusOK)
+	if digests := decodeBody[struct{ Digests []services.Digest }](t, w).Digests; len(digests) != 1 {
+		t.Errorf("expected the beta digest listed, got %+v", digests)
+	}
+	w = s.do(t, http.MethodGet, "/digests", "")
+	expectStatus(t, w, http.StatusOK)
+	if digests := decodeBody[struct{ Digests []services.Digest }](t, w).Digests; len(digests) != 0 {
+		t.Errorf("expected no default workspace digests, got %+v", digests)
+	}
+}
+
+func TestDeleteMeeting(t *testing.T) {
+	s := newMeetingTestServer(t)
+	m := s.store.Meetings.Add(services.Meeting{Title: "Old"})
+	path := "/meetings/" + strconv.Itoa(m.ID)
+
+	expectStatus(t, s.do(t, http.MethodDelete, path, ""), http.StatusOK)
+	expectStatus(t, s.do(t, http.MethodGet, path, ""), http.StatusNotFound)
+	expectStatus(t, s.do(t, http.MethodDelete, path, ""), http.StatusNotFound)
+	expectStatus(t, s.do(t, http.MethodDelete, "/meetings/abc", ""), http.StatusBadRequest)
+
+	// Trashed meetings can still be restored
+	if restored, err := s.store.Meetings.Restore(m.ID); err != nil || !restored {
+		t.Fatalf("expected the meeting to be restorable, got %v, %v", restored, err)
+	}
+	expectStatus(t, s.do(t, http.MethodGet, path, ""), http.StatusOK)
+}
+
+func TestStartMeeting(t *testing.T) {
+	s := newMeetingTestServer(t)
+	scheduled := s.store.Meetings.Add(services.Meeting{
+		Title:     "Planning",
+		Status:    services.MeetingStatusScheduled,
+		CreatedAt: time.Now().Add(24 * time.Hour),
+	})
+	path := "/meetings/" + strconv.Itoa(scheduled.ID) + "/start"
+
+	w := s.do(t, http.MethodPost, path, "")
+	expectStatus(t, w, http.StatusOK)
+	got := decodeBody[services.Meeting](t, w)
+	if got.Status != services.MeetingStatusRecording || !got.IsRecording || got.CreatedAt.After(time.Now()) {
+		t.Errorf("expected a recording that started now, got %+v", got)
+	}
+
+	w = s.do(t, http.MethodPost, path, "")
+	expectStatus(t, w, http.StatusConflict)
+	if status := decodeBody[map[string]string](t, w)["status"]; status != services.MeetingStatusRecording {
+		t.Errorf("expected the current status in the conflict, got %q", status)
+	}
+
+	expectStatus(t, s.do(t, http.MethodPost, "/meetings/999/start", ""), http.StatusNotFound)
+	expectStatus(t, s.do(t, http.MethodPost, "/meetings/abc/start", ""), http.StatusBadRequest)
+}
+
+func TestFinishMeeting(t *testing.T) {
+	s := newMeetingTestServer(t)
+
+	w := s.do(t, http.MethodPost, "/meetings", `{"title": "Call"}`)
+	expectStatus(t, w, http.StatusCreated)
+	m := decodeBody[services.Meeting](t, w)
+	if _, err := s.audio.SaveChunk(m.ID, strings.NewReader("webm"), "chunk_0001.webm"); err != nil {
+		t.Fatal(err)
+	}
+
+	w = s.do(t, http.MethodPost, "/meetings/"+strconv.Itoa(m.ID)+"/finish", "")
+	expectStatus(t, w, http.StatusOK)
+	got := decodeBody[services.Meeting](t, w)
+	if got.Status != services.MeetingStatusFinished || got.IsRecording || got.AudioPath == "" {
+		t.Errorf("expected a finished meeting with audio, got %+v", got)
+	}
+
+	// Finishing is an edit: the ETag from before it is stale
+	stale := `"` + strconv.Itoa(m.Version) + `"`
+	expectStatus(t, s.do(t, http.MethodPatch, "/meetings/"+strconv.Itoa(m.ID), `{"title": "x"}`, "If-Match", stale), http.StatusPreconditionFailed)
+
+	// Without chunks the meeting still finishes, just without audio
+	w = s.do(t, http.MethodPost, "/meetings", "")
+	m = decodeBody[services.Meeting](t, w)
+	w = s.do(t, http.MethodPost, "/meetings/"+strconv.Itoa(m.ID)+"/finish", "")
+	expectStatus(t, w, http.StatusOK)
+	if got := decodeBody[services.Meeting](t, w); got.Status != services.MeetingStatusFinished || got.AudioPath != "" {
+		t.Errorf("expected a finished meeting without audio, got %+v", got)
+	}
+
+	expectStatus(t, s.do(t, http.MethodPost, "/meetings/abc/finish", ""), http.StatusBadRequest)
+}
+
+func TestMeetingFollowUp(t *testing.T) {
+	s := newSQLMeetingTestServer(t)
+	m := s.createMeeting(t, "Kickoff")
+	path := "/meetings/" + strconv.Itoa(m.ID) + "/followup"
+
+	w := s.do(t, http.MethodPost, "/people", `{"name": "Ana", "email": "ana@example.com"}`)
+	expectStatus(t, w, http.StatusCreated)
+	person := decodeBody[services.Person](t, w)
+	expectStatus(t, s.do(t, http.MethodPost, "/meetings/"+strconv.Itoa(m.ID)+"/participants", `{"person_id": `+strconv.Itoa(person.ID)+`}`), http.StatusCreated)
+
+	// A reviewed draft is used as is, so no AI is involved
+	draft := `{"email": {"subject": "Kickoff recap", "summary": "We agreed on the scope."}}`
+	w = s.do(t, http.MethodPost, path, draft)
+	expectStatus(t, w, http.StatusOK)
+	got := decodeBody[struct {
+		Recipients []string
+		Sent       bool
+		EML        string
+	}](t, w)
+	if len(got.Recipients) != 1 || got.Recipients[0] != "ana@example.com" || got.Sent {
+		t.Errorf("expected an unsent email to the participant, got %+v", got)
+	}
+	if !strings.Contains(got.EML, "Kickoff recap") {
+		t.Errorf("expected the subject in the .eml, got %q", got.EML)
+	}
+
+	w = s.do(t, http.MethodPost, path+"?format=eml", draft)
+	expectStatus(t, w, http.StatusOK)
+	if ct := w.Header().Get("Content-Type"); ct != "message/rfc822" {
+		t.Errorf("expected a message/rfc822 download, got %s", ct)
+	}
+
+	expectStatus(t, s.do(t, http.MethodPost, path, `{"send": true, "email": {"subject": "Recap"}}`), http.StatusBadRequest)
+	expectStatus(t, s.do(t, http.MethodPost, path, `{"email": {"subject": " "}}`), http.StatusBadRequest)
+	expectStatus(t, s.do(t, http.MethodPost, path, ""), http.StatusBadRequest) // Nothing to draft from
+	expectStatus(t, s.do(t, http.MethodPost, "/meetings/999/followup", draft), http.StatusNotFound)
+	expectStatus(t, s.do(t, http.MethodPost, "/meetings/abc/followup", draft), http.StatusBadRequest)
+}
+
+func TestExportMeeting(t *testing.T) {
+	s := newMeetingTestServer(t)
+	m := s.store.Meetings.Add(services.Meeting{Title: "Board review", Notes: "<p>Budget approved</p>"})
+	path := "/meetings/" + strconv.Itoa(m.ID) + "/export"
+
+	w := s.do(t, http.MethodGet, path+"?format=json", "")
+	expectStatus(t, w, http.StatusOK)
+	if got := decodeBody[services.MeetingExport](t, w); got.Title != "Board review" || got.Notes != m.Notes {
+		t.Errorf("expected the meeting exported, got %+v", got)
+	}
+
+	w = s.do(t, http.MethodGet, path, "")
+	expectStatus(t, w, http.StatusOK)
+	if !strings.Contains(w.Body.String(), "Budget approved") {
+		t.Errorf("expected the notes in the Markdown export, got %q", w.Body.String())
+	}
+	if cd := w.Header().Get("Content-Disposition"); !strings.Contains(cd, "attachment") {
+		t.Errorf("expected a download, got %q", cd)
+	}
+
+	expectStatus(t, s.do(t, http.MethodGet, path+"?format=odt", ""), http.StatusBadRequest)
+	expectStatus(t, s.do(t, http.MethodGet, "/meetings/999/export", ""), http.StatusNotFound)
+	expectStatus(t, s.do(t, http.MethodGet, "/meetings/abc/export", ""), http.StatusBadRequest)
+}
+
+func TestMeetingRevisions(t *testing.T) {
+	s := newSQLMeetingTestServer(t)
+	m := s.createMeeting(t, "Planning")
+	other := s.createMeeting(t, "Retro")
+	if err := s.repos.Meetings.UpdateNotes(m.ID, "<p>First</p>", "tester", services.NoteSourceManual); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.repos.Meetings.UpdateNotes(m.ID, "<p>Second</p>", "tester", "beautify"); err != nil {
+		t.Fatal(err)
+	}
+	path := "/meetings/" + strconv.Itoa(m.ID) + "/revisions"
+
+	list := func() []services.NoteRevision {
+		w := s.do(t, http.MethodGet, path, "")
+		expectStatus(t, w, http.StatusOK)
+		return decodeBody[struct{ Revisions []services.NoteRevision }](t, w).Revisions
+	}
+	revisions := list()
+	if len(revisions) != 2 || revisions[0].Source != "beautify" || revisions[0].Notes != "" {
+		t.Fatalf("expected 2 revisions newest first without notes, got %+v", revisions)
+	}
+	first, second := revisions[1], revisions[0]
+
+	w := s.do(t, http.MethodGet, path+"/"+strconv.Itoa(first.ID), "")
+	expectStatus(t, w, http.StatusOK)
+	if got := decodeBody[services.NoteRevision](t, w); got.Notes != "<p>First</p>" {
+		t.Errorf("expected the first notes, got %q", got.Notes)
+	}
+	expectStatus(t, s.do(t, http.MethodGet, "/meetings/"+strconv.Itoa(other.ID)+"/revisions/"+strconv.Itoa(first.ID), ""), http.StatusNotFound)
+	expectStatus(t, s.do(t, http.MethodGet, path+"/abc", ""), http.StatusBadRequest)
+
+	w = s.do(t, http.MethodGet, path+"/diff?from="+strconv.Itoa(first.ID), "")
+	expectStatus(t, w, http.StatusOK)
+	if got := decodeBody[map[string]any](t, w); got["from"] != float64(first.ID) || got["to"] != nil {
+		t.Errorf("expected a diff from the first revision to the current notes, got %+v", got)
+	}
+	w = s.do(t, http.MethodGet, path+"/diff?from="+strconv.Itoa(first.ID)+"&to="+strconv.Itoa(second.ID), "")
+	expectStatus(t, w, http.StatusOK)
+	if got := decodeBody[map[string]any](t, w); got["to"] != float64(second.ID) {
+		t.Errorf("expected a diff to the second revision, got %+v", got)
+	}
+	expectStatus(t, s.do(t, http.MethodGet, path+"/diff", ""), http.StatusBadRequest)
+
+	restore := path + "/" + strconv.Itoa(first.ID) + "/restore"
+	expectStatus(t, s.do(t, http.MethodPost, restore, ""), http.StatusPreconditionRequired)
+	w = s.do(t, http.MethodPost, restore, "", "If-Match", "*")
+	expectStatus(t, w, http.StatusOK)
+	if got := decodeBody[services.Meeting](t, w); got.Notes != "<p>First</p>" {
+		t.Errorf("expected the first notes restored, got %q", got.Notes)
+	}
+	if revisions := list(); len(revisions) != 3 || revisions[0].Source != services.NoteSourceRestore {
+		t.Errorf("expected the restore recorded as a revision, got %+v", revisions)
+	}
+
+	expectStatus(t, s.do(t, http.MethodGet, "/meetings/999/revisions", ""), http.StatusNotFound)
+}
+
+func TestRestoreMeeting(t *testing.T) {
+	s := newMeetingTestServer(t)
+	m := s.store.Meetings.Add(services.Meeting{Title: "Old"})
+	path := "/meetings/" + strconv.Itoa(m.ID)
+
+	expectStatus(t, s.do(t, http.MethodPost, path+"/restore", ""), http.StatusNotFound) // Not in the trash
+	expectStatus(t, s.do(t, http.MethodDelete, path, ""), http.StatusOK)
+
+	w := s.do(t, http.MethodPost, path+"/restore", "")
+	expectStatus(t, w, http.StatusOK)
+	if got := decodeBody[services.Meeting](t, w); got.ID != m.ID || got.Title != "Old" {
+		t.Errorf("expected the restored meeting, got %+v", got)
+	}
+	expectStatus(t, s.do(t, http.MethodGet, path, ""), http.StatusOK)
+	expectStatus(t, s.do(t, http.MethodPost, "/meetings/abc/restore", ""), http.StatusBadRequest)
+}
+
+func TestMoveMeetingToFolder(t *testing.T) {
+	s := newMeetingTestServer(t)
+	folder, err := s.store.Folders.Create("Clients", nil, services.FolderDefaults{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := s.store.Meetings.Add(services.Meeting{Title: "Call"})
+	path := "/meetings/" + strconv.Itoa(m.ID) + "/folder"
+
+	w := s.do(t, http.MethodPut, path, `{"folder_id": `+strconv.Itoa(folder.ID)+`}`)
+	expectStatus(t, w, http.StatusOK)
+	if got := decodeBody[services.Meeting](t, w); got.FolderID == nil || *got.FolderID != folder.ID {
+		t.Errorf("expected the meeting in folder %d, got %v", folder.ID, got.FolderID)
+	}
+	w = s.do(t, http.MethodPut, path, `{"folder_id": null}`)
+	expectStatus(t, w, http.StatusOK)
+	if got := decodeBody[services.Meeting](t, w); got.FolderID != nil {
+		t.Errorf("expected the meeting back at the root, got %v", *got.FolderID)
+	}
+
+	expectStatus(t, s.do(t, http.MethodPut, path, `{"folder_id": 999}`), http.StatusNotFound)
+	expectStatus(t, s.do(t, http.MethodPut, path, `not json`), http.StatusBadRequest)
+	expectStatus(t, s.do(t, http.MethodPut, "/meetings/999/folder", `{"folder_id": null}`), http.StatusNotFound)
+}
+
+func TestAutoFillMeeting(t *testing.T) {
+	s := newMeetingTestServer(t)
+	w := s.do(t, http.MethodPost, "/templates", `{"name": "Retro", "sections": [{"name": "Decisions"}]}`)
+	expectStatus(t, w, http.StatusCreated)
+	template := decodeBody[services.MeetingTemplate](t, w)
+
+	// Filling blank sections needs Gemini, which isn't reachable here, so
+	// the meeting's only section is already written
+	notes := "<h2>Decisions</h2><ul><li>Ship it</li></ul>"
+	m := s.store.Meetings.Add(services.Meeting{Title: "Retro", Transcript: "We ship it.", Notes: notes, TemplateID: &template.ID})
+	path := "/meetings/" + strconv.Itoa(m.ID) + "/autofill"
+
+	expectStatus(t, s.do(t, http.MethodPost, path, ""), http.StatusPreconditionRequired)
+	w = s.do(t, http.MethodPost, path, "", "If-Match", "*")
+	expectStatus(t, w, http.StatusOK)
+	got := decodeBody[struct {
+		Meeting services.Meeting
+		Filled  []string
+	}](t, w)
+	if len(got.Filled) != 0 || got.Meeting.Notes != notes {
+		t.Errorf("expected written sections left alone, got %+v", got)
+	}
+
+	missing := 999
+	for _, meeting := range []services.Meeting{
+		{Title: "Untemplated", Transcript: "Hello."},
+		{Title: "Silent", TemplateID: &template.ID},
+	} {
+		m := s.store.Meetings.Add(meeting)
+		expectStatus(t, s.do(t, http.MethodPost, "/meetings/"+strconv.Itoa(m.ID)+"/autofill", "", "If-Match", "*"), http.StatusBadRequest)
+	}
+	m = s.store.Meetings.Add(services.Meeting{Title: "Orphan", Transcript: "Hello.", TemplateID: &missing})
+	expectStatus(t, s.do(t, http.MethodPost, "/meetings/"+strconv.Itoa(m.ID)+"/autofill", "", "If-Match", "*"), http.StatusNotFound)
+	expectStatus(t, s.do(t, http.MethodPost, "/meetings/999/autofill", "", "If-Match", "*"), http.StatusNotFound)
+}
+
+func TestMeetingParticipants(t *testing.T) {
+	s := newSQLMeetingTestServer(t)
+	m := s.createMeeting(t, "Interview")
+	path := "/meetings/" + strconv.Itoa(m.ID) + "/participants"
+
+	w := s.do(t, http.MethodGet, path, "")
+	expectStatus(t, w, http.StatusOK)
+	if got := decodeBody[struct {
+		Participants []services.Participant
+		Roles        []string
+	}](t, w); len(got.Participants) != 0 || len(got.Roles) != len(services.ParticipantRoles) {
+		t.Errorf("expected no participants and the roles, got %+v", got)
+	}
+
+	w = s.do(t, http.MethodPost, path, `{"name": "Ana", "role": "host"}`)
+	expectStatus(t, w, http.StatusCreated)
+	ana := decodeBody[services.Participant](t, w)
+	if ana.Name != "Ana" || ana.Role != services.ParticipantRoleHost {
+		t.Errorf("expected Ana as host, got %+v", ana)
+	}
+	expectStatus(t, s.do(t, http.MethodPost, path, `{"name": "Ben", "role": "boss"}`), http.StatusBadRequest)
+
+	w = s.do(t, http.MethodPost, "/people", `{"name": "Cleo"}`)
+	expectStatus(t, w, http.StatusCreated)
+	body := `{"person_id": ` + strconv.Itoa(decodeBody[services.Person](t, w).ID) + `}`
+	expectStatus(t, s.do(t, http.MethodPost, path, body), http.StatusCreated)
+	expectStatus(t, s.do(t, http.MethodPost, path, body), http.StatusConflict)
+
+	participant := path + "/" + strconv.Itoa(ana.ID)
+	w = s.do(t, http.MethodPut, participant, `{"role": "external"}`)
+	expectStatus(t, w, http.StatusOK)
+	if got := decodeBody[services.Participant](t, w); got.Role != services.ParticipantRoleExternal {
+		t.Errorf("expected Ana external, got %+v", got)
+	}
+	expectStatus(t, s.do(t, http.MethodPut, participant, `{"role": "boss"}`), http.StatusBadRequest)
+	expectStatus(t, s.do(t, http.MethodPut, path+"/999", `{"role": "host"}`), http.StatusNotFound)
+
+	expectStatus(t, s.do(t, http.MethodDelete, participant, ""), http.StatusOK)
+	expectStatus(t, s.do(t, http.MethodDelete, participant, ""), http.StatusNotFound)
+	expectStatus(t, s.do(t, http.MethodDelete, path+"/abc", ""), http.StatusBadRequest)
+	expectStatus(t, s.do(t, http.MethodGet, "/meetings/999/participants", ""), http.StatusNotFound)
+}
+
+func TestMapMeetingSpeaker(t *testing.T) {
+	s := newSQLMeetingTestServer(t)
+	m := s.createMeeting(t, "Interview")
+	for _, seg := range []services.Segment{
+		{MeetingID: m.ID, Speaker: "Speaker 1", StartMs: 0, EndMs: 4000, Text: "Hello"},
+		{MeetingID: m.ID, Speaker: "Speaker 2", StartMs: 4000, EndMs: 8000, Text: "Hi"},
+		{MeetingID: m.ID, Speaker: "Speaker 1", StartMs: 8000, EndMs: 9000, Text: "Welcome"},
+	} {
+		if err := s.repos.Segments.Add(&seg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	w := s.do(t, http.MethodPost, "/meetings/"+strconv.Itoa(m.ID)+"/participants", `{"name": "Ana"}`)
+	expectStatus(t, w, http.StatusCreated)
+	ana := decodeBody[services.Participant](t, w)
+	path := "/meetings/" + strconv.Itoa(m.ID) + "/speakers"
+
+	w = s.do(t, http.MethodPut, path, `{"speaker": "Speaker 1", "participant_id": `+strconv.Itoa(ana.ID)+`}`)
+	expectStatus(t, w, http.StatusOK)
+	got := decodeBody[struct {
+		Participant        services.Participant
+		SegmentsRelabelled int `json:"segments_relabelled"`
+	}](t, w)
+	if got.Participant.Speaker != "Speaker 1" || got.SegmentsRelabelled != 2 {
+		t.Errorf("expected Speaker 1 mapped to Ana on 2 segments, got %+v", got)
+	}
+
+	w = s.do(t, http.MethodGet, "/meetings/"+strconv.Itoa(m.ID)+"/segments", "")
+	expectStatus(t, w, http.StatusOK)
+	if segments := decodeBody[struct{ Segments []services.Segment }](t, w).Segments; len(segments) != 3 || segments[0].Speaker != "Ana" || segments[1].Speaker != "Speaker 2" {
+		t.Errorf("expected Speaker 1's segments relabelled, got %+v", segments)
+	}
+
+	expectStatus(t, s.do(t, http.MethodPut, path, `{"speaker": "Speaker 2", "participant_id": 999}`), http.StatusNotFound)
+	expectStatus(t, s.do(t, http.MethodPut, path, `{"participant_id": `+strconv.Itoa(ana.ID)+`}`), http.StatusBadRequest)
+	expectStatus(t, s.do(t, http.MethodPut, "/meetings/999/speakers", `{"speaker": "Speaker 1", "participant_id": 1}`), http.StatusNotFound)
+}
+
+func TestMeetingTags(t *testing.T) {
+	s := newMeetingTestServer(t)
+	m := s.store.Meetings.Add(services.Meeting{Title: "Demo"})
+	path := "/meetings/" + strconv.Itoa(m.ID) + "/tags"
+
+	list := func() []services.MeetingTag {
+		w := s.do(t, http.MethodGet, path, "")
+		expectStatus(t, w, http.StatusOK)
+		return decodeBody[struct{ Tags []services.MeetingTag }](t, w).Tags
+	}
+
+	w := s.do(t, http.MethodPost, path, `{"name": "Sales"}`)
+	expectStatus(t, w, http.StatusCreated)
+	tag := decodeBody[services.MeetingTag](t, w)
+	if tags := list(); len(tags) != 1 || !strings.EqualFold(tags[0].Name, "Sales") || tags[0].ID != tag.ID {
+		t.Errorf("expected the Sales tag, got %+v", tags)
+	}
+
+	expectStatus(t, s.do(t, http.MethodDelete, path+"/"+strconv.Itoa(tag.ID), ""), http.StatusOK)
+	if tags := list(); len(tags) != 0 {
+		t.Errorf("expected the tag removed, got %+v", tags)
+	}
+
+	expectStatus(t, s.do(t, http.MethodPost, path, `{}`), http.StatusBadRequest)
+	expectStatus(t, s.do(t, http.MethodPost, "/meetings/999/tags", `{"name": "Sales"}`), http.StatusNotFound)
+	expectStatus(t, s.do(t, http.MethodDelete, path+"/abc", ""), http.StatusBadRequest)
+}
+
+func TestClassifyMeeting(t *testing.T) {
+	s := newMeetingTestServer(t)
+
+	// Classifying a transcript needs Gemini, which isn't reachable here
+	m := s.store.Meetings.Add(services.Meeting{Title: "Empty"})
+	expectStatus(t, s.do(t, http.MethodPost, "/meetings/"+strconv.Itoa(m.ID)+"/classify", ""), http.StatusBadRequest)
+	expectStatus(t, s.do(t, http.MethodPost, "/meetings/999/classify", ""), http.StatusNotFound)
+	expectStatus(t, s.do(t, http.MethodPost, "/meetings/abc/classify", ""), http.StatusBadRequest)
+}
+
+func TestMeetingSegments(t *testing.T) {
+	s := newMeetingTestServer(t)
+	m := s.store.Meetings.Add(services.Meeting{Title: "Interview"})
+	for _, seg := range []services.Segment{
+		{MeetingID: m.ID, Speaker: "Speaker 2", StartMs: 5000, EndMs: 9000, Text: "second"},
+		{MeetingID: m.ID, Speaker: "Speaker 1", StartMs: 0, EndMs: 5000, Text: "first"},
+	} {
+		if err := s.store.Segments.Add(&seg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	path := "/meetings/" + strconv.Itoa(m.ID) + "/segments"
+
+	list := func() []services.Segment {
+		w := s.do(t, http.MethodGet, path, "")
+		expectStatus(t, w, http.StatusOK)
+		return decodeBody[struct{ Segments []services.Segment }](t, w).Segments
+	}
+	segments := list()
+	if len(segments) != 2 || segments[0].Text != "first" {
+		t.Fatalf("expected both segments in time order, got %+v", segments)
+	}
+
+	segment := path + "/" + strconv.Itoa(segments[1].ID)
+	expectStatus(t, s.do(t, http.MethodPut, segment, `{"speaker": "Ana"}`), http.StatusPreconditionRequired)
+	expectStatus(t, s.do(t, http.MethodPut, segment, `{"speaker": "Ana"}`, "If-Match", "*"), http.StatusOK)
+	if segments := list(); segments[1].Speaker != "Ana" {
+		t.Errorf("expected the second segment by Ana, got %+v", segments[1])
+	}
+
+	expectStatus(t, s.do(t, http.MethodPut, segment, `{}`, "If-Match", "*"), http.StatusBadRequest)
+	expectStatus(t, s.do(t, http.MethodPut, path+"/999", `{"speaker": "Ana"}`, "If-Match", "*"), http.StatusNotFound)
+	expectStatus(t, s.do(t, http.MethodGet, "/meetings/abc/segments", ""), http.StatusBadRequest)
+}
+
+func TestMeetingAnalytics(t *testing.T) {
+	s := newSQLMeetingTestServer(t)
+	m := s.createMeeting(t, "Standup")
+	path := "/meetings/" + strconv.Itoa(m.ID) + "/analytics"
+
+	// Computing analytics asks Gemini for the sentiment, which isn't
+	// reachable here, so only stored analytics are served
+	expectStatus(t, s.do(t, http.MethodGet, path, ""), http.StatusBadRequest) // No transcript
+	_, err := s.repos.DB.Exec(
+		"INSERT INTO meeting_analytics (meeting_id, data, computed_at) VALUES (?, ?, ?)",
+		m.ID, `{"meeting_id": `+strconv.Itoa(m.ID)+`, "total_words": 42}`, time.Now().Unix(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := s.do(t, http.MethodGet, path, "")
+	expectStatus(t, w, http.StatusOK)
+	if got := decodeBody[services.MeetingAnalytics](t, w); got.MeetingID != m.ID || got.TotalWords != 42 {
+		t.Errorf("expected the stored analytics, got %+v", got)
+	}
+
+	expectStatus(t, s.do(t, http.MethodGet, "/meetings/999/analytics", ""), http.StatusNotFound)
+	expectStatus(t, s.do(t, http.MethodGet, "/meetings/abc/analytics", ""), http.StatusBadRequest)
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"backend/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ModelsHandler struct {
+	Settings             *services.AISettingsService
+	GeminiService        *services.GeminiService
+	TranscriptionService *services.TranscriptionService
+}
+
+func NewModelsHandler(settings *services.AISettingsService, gemini *services.GeminiService, transcription *services.TranscriptionService) *ModelsHandler {
+	return &ModelsHandler{
+		Settings:             settings,
+		GeminiService:        gemini,
+		TranscriptionService: transcription,
+	}
+}
+
+// ListModels returns the models available from each configured provider
+func (h *ModelsHandler) ListModels(c *gin.Context) {
+	catalogs := services.BuildModelCatalog(c.Request.Context(), h.GeminiService, h.TranscriptionService)
+	c.JSON(http.StatusOK, gin.H{
+		"providers": catalogs,
+		"warnings":  h.Settings.Validate(catalogs),
+	})
+}
+
+// GetSettings returns the model settings of every AI action
+func (h *ModelsHandler) GetSettings(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"settings":          h.Settings.All(),
+		"safety_categories": services.SafetyCategories,
+		"safety_thresholds": services.SafetyThresholds,
+	})
+}
+
+// UpdateSettings changes the model settings of one action. The model must be
+// offered by the action's provider.
+func (h *ModelsHandler) UpdateSettings(c *gin.Context) {
+	action := c.Param("action")
+	current, ok := h.Settings.Get(action)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Unknown action"})
+		return
+	}
+
+	var req services.AIActionSettings
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	catalogs := services.BuildModelCatalog(c.Request.Context(), h.GeminiService, h.TranscriptionService)
+	for _, catalog := range catalogs {
+		if catalog.Provider != current.Provider {
+			continue
+		}
+		if catalog.Error != "" {
+			c.JSON(http.StatusBadGateway, gin.H{"error": "Could not list " + catalog.Provider + " models to validate the setting: " + catalog.Error})
+			return
+		}
+		if !catalog.Has(req.Model) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Model '" + req.Model + "' is not offered by " + catalog.Provider})
+			return
+		}
+	}
+
+	settings, err := h.Settings.Update(action, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// ResetSettings restores the built-in settings of one action
+func (h *ModelsHandler) ResetSettings(c *gin.Context) {
+	settings, err := h.Settings.Reset(c.Param("action"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
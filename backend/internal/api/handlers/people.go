@@ -0,0 +1,309 @@
+package handlers
+
+import (
+	"backend/internal/services"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PeopleHandler struct {
+	Service        *services.PeopleService
+	MeetingService services.MeetingRepository
+}
+
+func NewPeopleHandler(service *services.PeopleService, meetingService services.MeetingRepository) *PeopleHandler {
+	return &PeopleHandler{
+		Service:        service,
+		MeetingService: meetingService,
+	}
+}
+
+// GetAll lists the people directory, filtered by ?q= on name, email or alias
+func (h *PeopleHandler) GetAll(c *gin.Context) {
+	people, err := h.Service.GetAll(c.Query("q"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"people": people})
+}
+
+// GetOne returns a person
+func (h *PeopleHandler) GetOne(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid person ID"})
+		return
+	}
+
+	person, err := h.Service.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if person == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Person not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, person)
+}
+
+// Create adds a person with {"name", "email", "aliases"}
+func (h *PeopleHandler) Create(c *gin.Context) {
+	var req services.PersonInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	person, err := h.Service.Create(req)
+	if errors.Is(err, services.ErrDuplicateEmail) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, person)
+}
+
+// Update replaces a person's name, email and aliases
+func (h *PeopleHandler) Update(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid person ID"})
+		return
+	}
+
+	var req services.PersonInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	person, err := h.Service.Update(id, req)
+	if errors.Is(err, services.ErrDuplicateEmail) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if person == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Person not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, person)
+}
+
+// Delete removes a person; meetings keep them as a participant by name
+func (h *PeopleHandler) Delete(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid person ID"})
+		return
+	}
+
+	if err := h.Service.Delete(id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Person deleted"})
+}
+
+// GetMeetings pages through the meetings a person took part in, newest
+// first. It is GET /meetings?person=:id with only limit and cursor.
+func (h *PeopleHandler) GetMeetings(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid person ID"})
+		return
+	}
+
+	filter := services.MeetingFilter{PersonID: id, Limit: defaultMeetingLimit, Cursor: c.Query("cursor")}
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxMeetingLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid limit, use 1 to %d", maxMeetingLimit)})
+			return
+		}
+		filter.Limit = limit
+	}
+
+	page, err := h.MeetingService.List(filter)
+	if errors.Is(err, services.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// GetParticipants lists who took part in a meeting
+func (h *PeopleHandler) GetParticipants(c *gin.Context) {
+	meeting, ok := h.meeting(c)
+	if !ok {
+		return
+	}
+
+	participants, err := h.Service.GetParticipants(meeting.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"participants": participants, "roles": services.ParticipantRoles})
+}
+
+// AddParticipant adds {"person_id": N} or {"name": "..."} to a meeting, with an optional role
+func (h *PeopleHandler) AddParticipant(c *gin.Context) {
+	meeting, ok := h.meeting(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		PersonID *int   `json:"person_id"`
+		Name     string `json:"name"`
+		Role     string `json:"role"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	participant, err := h.Service.AddParticipant(meeting.ID, req.PersonID, req.Name, req.Role)
+	if errors.Is(err, services.ErrDuplicateParticipant) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, participant)
+}
+
+// UpdateParticipant changes a participant's {"role"}
+func (h *PeopleHandler) UpdateParticipant(c *gin.Context) {
+	meeting, ok := h.meeting(c)
+	if !ok {
+		return
+	}
+	participantID, err := strconv.Atoi(c.Param("participantId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid participant ID"})
+		return
+	}
+
+	var req struct {
+		Role string `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	participant, err := h.Service.SetParticipantRole(meeting.ID, participantID, req.Role)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if participant == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Participant not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, participant)
+}
+
+// RemoveParticipant takes someone off a meeting's participant list
+func (h *PeopleHandler) RemoveParticipant(c *gin.Context) {
+	meeting, ok := h.meeting(c)
+	if !ok {
+		return
+	}
+	participantID, err := strconv.Atoi(c.Param("participantId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid participant ID"})
+		return
+	}
+
+	found, err := h.Service.RemoveParticipant(meeting.ID, participantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Participant not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Participant removed"})
+}
+
+// MapSpeaker attributes a diarized {"speaker": "Speaker 1"} to {"participant_id": N},
+// relabelling that speaker's transcript segments with the participant's name
+func (h *PeopleHandler) MapSpeaker(c *gin.Context) {
+	meeting, ok := h.meeting(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Speaker       string `json:"speaker" binding:"required"`
+		ParticipantID int    `json:"participant_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	participant, relabelled, err := h.Service.MapSpeaker(meeting.ID, req.Speaker, req.ParticipantID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if participant == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Participant not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"participant": participant, "segments_relabelled": relabelled})
+}
+
+// meeting loads the meeting named in the path, writing an error response if it can't
+func (h *PeopleHandler) meeting(c *gin.Context) (*services.Meeting, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meeting ID"})
+		return nil, false
+	}
+
+	meeting, err := h.MeetingService.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if meeting == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Meeting not found"})
+		return nil, false
+	}
+	return meeting, true
+}
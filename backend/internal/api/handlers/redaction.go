@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"backend/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RedactionHandler struct {
+	Service *services.RedactionService
+}
+
+func NewRedactionHandler(service *services.RedactionService) *RedactionHandler {
+	return &RedactionHandler{Service: service}
+}
+
+// GetPolicy returns the redaction policy of ?workspace= (default workspace if omitted)
+func (h *RedactionHandler) GetPolicy(c *gin.Context) {
+	policy, err := h.Service.GetPolicy(workspaceParam(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// UpdatePolicy replaces the redaction policy of a workspace
+func (h *RedactionHandler) UpdatePolicy(c *gin.Context) {
+	var req struct {
+		Enabled   *bool    `json:"enabled" binding:"required"`
+		Detectors []string `json:"detectors"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	policy := &services.RedactionPolicy{Workspace: workspaceParam(c), Enabled: *req.Enabled, Detectors: req.Detectors}
+	if policy.Detectors == nil {
+		policy.Detectors = []string{}
+	}
+	if err := h.Service.SetPolicy(policy); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// GetTerms lists the names that are always redacted
+func (h *RedactionHandler) GetTerms(c *gin.Context) {
+	terms, err := h.Service.GetTerms(workspaceParam(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if terms == nil {
+		terms = []services.RedactionTerm{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"terms": terms})
+}
+
+// AddTerm adds a name to the deny-list
+func (h *RedactionHandler) AddTerm(c *gin.Context) {
+	var req struct {
+		Term string `json:"term" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	term, err := h.Service.AddTerm(workspaceParam(c), req.Term)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, term)
+}
+
+// DeleteTerm removes a name from the deny-list
+func (h *RedactionHandler) DeleteTerm(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid term ID"})
+		return
+	}
+
+	if err := h.Service.DeleteTerm(id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Term deleted"})
+}
+
+// GetReports lists what was redacted from recent AI calls
+func (h *RedactionHandler) GetReports(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 500 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 500"})
+		return
+	}
+
+	reports, err := h.Service.GetReports(workspaceParam(c), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if reports == nil {
+		reports = []services.RedactionReport{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"reports": reports})
+}
+
+func workspaceParam(c *gin.Context) string {
+	return c.DefaultQuery("workspace", services.DefaultWorkspace)
+}
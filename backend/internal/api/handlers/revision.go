@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"backend/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RevisionHandler struct {
+	Service        *services.NoteRevisionService
+	MeetingService services.MeetingRepository
+}
+
+func NewRevisionHandler(service *services.NoteRevisionService, meetingService services.MeetingRepository) *RevisionHandler {
+	return &RevisionHandler{
+		Service:        service,
+		MeetingService: meetingService,
+	}
+}
+
+// GetAll lists a meeting's notes revisions, newest first
+func (h *RevisionHandler) GetAll(c *gin.Context) {
+	meeting, ok := h.meeting(c)
+	if !ok {
+		return
+	}
+
+	revisions, err := h.Service.List(meeting.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"revisions": revisions})
+}
+
+// GetOne returns a revision including its notes
+func (h *RevisionHandler) GetOne(c *gin.Context) {
+	meeting, ok := h.meeting(c)
+	if !ok {
+		return
+	}
+	revision, ok := h.revision(c, meeting.ID, c.Param("revisionId"))
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, revision)
+}
+
+// Diff compares revision ?from= with revision ?to=, or with the current notes when to is omitted
+func (h *RevisionHandler) Diff(c *gin.Context) {
+	meeting, ok := h.meeting(c)
+	if !ok {
+		return
+	}
+
+	from, ok := h.revision(c, meeting.ID, c.Query("from"))
+	if !ok {
+		return
+	}
+
+	toNotes := meeting.Notes
+	var toID *int
+	if c.Query("to") != "" {
+		to, ok := h.revision(c, meeting.ID, c.Query("to"))
+		if !ok {
+			return
+		}
+		toNotes = to.Notes
+		toID = &to.ID
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"from": from.ID,
+		"to":   toID,
+		"diff": services.DiffNotes(from.Notes, toNotes),
+	})
+}
+
+// Restore makes a revision the meeting's current notes. The restore is itself
+// recorded as a new revision, so it can be undone. It requires If-Match.
+func (h *RevisionHandler) Restore(c *gin.Context) {
+	meeting, ok := h.meeting(c)
+	if !ok {
+		return
+	}
+	revision, ok := h.revision(c, meeting.ID, c.Param("revisionId"))
+	if !ok {
+		return
+	}
+
+	if !checkVersion(c, h.MeetingService, meeting.ID) {
+		return
+	}
+
+	if err := h.MeetingService.UpdateNotes(meeting.ID, revision.Notes, c.GetString("username"), services.NoteSourceRestore); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	meeting, _ = h.MeetingService.GetByID(meeting.ID)
+	if meeting != nil {
+		c.Header("ETag", meetingETag(meeting))
+	}
+	c.JSON(http.StatusOK, meeting)
+}
+
+// meeting loads the meeting named in the path, writing an error response if it can't
+func (h *RevisionHandler) meeting(c *gin.Context) (*services.Meeting, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meeting ID"})
+		return nil, false
+	}
+
+	meeting, err := h.MeetingService.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if meeting == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Meeting not found"})
+		return nil, false
+	}
+	return meeting, true
+}
+
+// revision loads one of the meeting's revisions, writing an error response if it can't
+func (h *RevisionHandler) revision(c *gin.Context, meetingID int, value string) (*services.NoteRevision, bool) {
+	id, err := strconv.Atoi(value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid revision ID"})
+		return nil, false
+	}
+
+	revision, err := h.Service.GetByID(meetingID, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if revision == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Revision not found"})
+		return nil, false
+	}
+	return revision, true
+}
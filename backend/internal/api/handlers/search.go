@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"backend/internal/services"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+type SearchHandler struct {
+	Service       *services.SearchService
+	FolderService services.FolderRepository
+}
+
+func NewSearchHandler(service *services.SearchService, folderService services.FolderRepository) *SearchHandler {
+	return &SearchHandler{Service: service, FolderService: folderService}
+}
+
+// Search returns meetings matching ?q=, best first, with highlighted snippets.
+// q uses FTS5 syntax: "exact phrase", prefix*, AND, OR, NOT and parentheses.
+// ?folder= with ?recursive=true limits results to a folder.
+func (h *SearchHandler) Search(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter 'q' is required"})
+		return
+	}
+
+	limit := defaultSearchLimit
+	if v := c.Query("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 || parsed > maxSearchLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit, use 1 to " + strconv.Itoa(maxSearchLimit)})
+			return
+		}
+		limit = parsed
+	}
+
+	folderIDs, unfiled, ok := folderScope(c, h.FolderService)
+	if !ok {
+		return
+	}
+	if unfiled {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Search by folder ID; use no folder to search everything"})
+		return
+	}
+
+	hits, err := h.Service.Search(query, limit, folderIDs)
+	if errors.Is(err, services.ErrInvalidQuery) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"query": query, "results": hits})
+}
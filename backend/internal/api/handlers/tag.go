@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"backend/internal/services"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TagHandler struct {
+	Service        services.TagRepository
+	MeetingService services.MeetingRepository
+	GeminiService  *services.GeminiService
+}
+
+func NewTagHandler(service services.TagRepository, meetingService services.MeetingRepository, gemini *services.GeminiService) *TagHandler {
+	return &TagHandler{
+		Service:        service,
+		MeetingService: meetingService,
+		GeminiService:  gemini,
+	}
+}
+
+// GetAll lists all tags with their usage counts
+func (h *TagHandler) GetAll(c *gin.Context) {
+	tags, err := h.Service.GetAll()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if tags == nil {
+		tags = []services.Tag{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tags": tags})
+}
+
+// GetMeetingTypes lists the accepted meeting types
+func (h *TagHandler) GetMeetingTypes(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"meeting_types": services.MeetingTypes})
+}
+
+// Create creates a tag, returning the existing one if the name is taken
+func (h *TagHandler) Create(c *gin.Context) {
+	var req struct {
+		Name string `json:"name" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	tag, err := h.Service.GetOrCreate(req.Name)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, tag)
+}
+
+// Rename renames a tag
+func (h *TagHandler) Rename(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tag ID"})
+		return
+	}
+
+	var req struct {
+		Name string `json:"name" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	tag, err := h.Service.Rename(id, req.Name)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if tag == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+// Delete removes a tag from all meetings and deletes it
+func (h *TagHandler) Delete(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tag ID"})
+		return
+	}
+
+	if err := h.Service.Delete(id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tag deleted"})
+}
+
+// GetMeetingTags lists a meeting's tags and whether they were added by hand or suggested
+func (h *TagHandler) GetMeetingTags(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meeting ID"})
+		return
+	}
+
+	tags, err := h.Service.GetForMeeting(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tags": tags})
+}
+
+// AddMeetingTag tags a meeting by name, creating the tag if needed
+func (h *TagHandler) AddMeetingTag(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meeting ID"})
+		return
+	}
+
+	var req struct {
+		Name string `json:"name" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	meeting, err := h.MeetingService.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if meeting == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Meeting not found"})
+		return
+	}
+
+	tag, err := h.Service.AddToMeeting(id, req.Name, services.TagSourceManual)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, tag)
+}
+
+// RemoveMeetingTag untags a meeting
+func (h *TagHandler) RemoveMeetingTag(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meeting ID"})
+		return
+	}
+	tagID, err := strconv.Atoi(c.Param("tagId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tag ID"})
+		return
+	}
+
+	if err := h.Service.RemoveFromMeeting(id, tagID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tag removed"})
+}
+
+// Classify suggests tags and a meeting type from the transcript on demand
+func (h *TagHandler) Classify(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meeting ID"})
+		return
+	}
+
+	meeting, err := h.MeetingService.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if meeting == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Meeting not found"})
+		return
+	}
+	if strings.TrimSpace(meeting.Transcript) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Meeting has no transcript to classify"})
+		return
+	}
+
+	existing, err := h.Service.Names()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	classification, err := h.GeminiService.ForWorkspace(meeting.Workspace).ClassifyMeeting(meeting.Transcript, existing)
+	if errors.Is(err, services.ErrInvalidModelOutput) {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		fmt.Printf("Classify Error: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.Service.ApplyClassification(id, classification); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	meeting, _ = h.MeetingService.GetByID(id)
+	c.JSON(http.StatusOK, gin.H{"suggestion": classification, "meeting": meeting})
+}
@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"backend/internal/services"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TemplateHandler struct {
+	Service        *services.TemplateService
+	MeetingService services.MeetingRepository
+	GeminiService  *services.GeminiService
+}
+
+func NewTemplateHandler(service *services.TemplateService, meetingService services.MeetingRepository, gemini *services.GeminiService) *TemplateHandler {
+	return &TemplateHandler{
+		Service:        service,
+		MeetingService: meetingService,
+		GeminiService:  gemini,
+	}
+}
+
+// GetAll lists every meeting template
+func (h *TemplateHandler) GetAll(c *gin.Context) {
+	templates, err := h.Service.GetAll()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"templates": templates})
+}
+
+// GetOne returns a meeting template
+func (h *TemplateHandler) GetOne(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID"})
+		return
+	}
+
+	template, err := h.Service.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if template == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Template not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// Create adds a meeting template
+func (h *TemplateHandler) Create(c *gin.Context) {
+	var req services.TemplateInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	template, err := h.Service.Create(req)
+	if errors.Is(err, services.ErrTemplateExists) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+// Update replaces a meeting template. Existing meetings keep their notes.
+func (h *TemplateHandler) Update(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID"})
+		return
+	}
+
+	var req services.TemplateInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	template, err := h.Service.Update(id, req)
+	if errors.Is(err, services.ErrTemplateExists) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if template == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Template not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// Delete removes a meeting template
+func (h *TemplateHandler) Delete(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID"})
+		return
+	}
+
+	if err := h.Service.Delete(id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Template deleted"})
+}
+
+// AutoFill writes the blank sections of a templated meeting's notes from its
+// transcript. Sections with any text in them, whether typed or filled
+// before, are left as they are. Requires If-Match like Update.
+func (h *TemplateHandler) AutoFill(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meeting ID"})
+		return
+	}
+
+	meeting, err := h.MeetingService.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if meeting == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Meeting not found"})
+		return
+	}
+	if meeting.TemplateID == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Meeting was not created from a template"})
+		return
+	}
+	if strings.TrimSpace(meeting.Transcript) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Meeting has no transcript to fill the notes from"})
+		return
+	}
+
+	template, err := h.Service.GetByID(*meeting.TemplateID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if template == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Template not found"})
+		return
+	}
+
+	if !checkVersion(c, h.MeetingService, id) {
+		return
+	}
+
+	sections := template.EmptySections(meeting.Notes)
+	filled := []string{}
+	if len(sections) > 0 {
+		written, err := h.GeminiService.ForWorkspace(meeting.Workspace).FillTemplateSections(meeting.Title, meeting.Transcript, template.Agenda, sections, meeting.PromptTemplate)
+		if errors.Is(err, services.ErrInvalidModelOutput) {
+			c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			fmt.Printf("Auto-fill Error: %v\n", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		items := map[string][]string{}
+		for _, section := range written {
+			for _, requested := range sections {
+				if strings.EqualFold(section.Name, requested.Name) {
+					items[requested.Name] = section.Items
+				}
+			}
+		}
+
+		// The notes may have been edited while the model was writing, so
+		// only sections that are still blank now are filled
+		current, err := h.MeetingService.GetByID(id)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if current == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Meeting not found"})
+			return
+		}
+
+		var notes string
+		if notes, filled = services.FillSections(current.Notes, items); len(filled) > 0 {
+			if err := h.MeetingService.UpdateNotes(id, notes, c.GetString("username"), "fill-template"); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+		}
+	}
+
+	meeting, err = h.MeetingService.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if meeting == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Meeting not found"})
+		return
+	}
+
+	c.Header("ETag", meetingETag(meeting))
+	c.JSON(http.StatusOK, gin.H{"meeting": meeting, "filled": filled})
+}
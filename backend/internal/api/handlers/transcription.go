@@ -5,18 +5,29 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
 type TranscriptionHandler struct {
-	Service *services.TranscriptionService
+	Service        *services.TranscriptionService
+	MeetingService services.MeetingRepository
+	SegmentService services.SegmentRepository
 }
 
-func NewTranscriptionHandler(service *services.TranscriptionService) *TranscriptionHandler {
-	return &TranscriptionHandler{Service: service}
+func NewTranscriptionHandler(service *services.TranscriptionService, meetingService services.MeetingRepository, segmentService services.SegmentRepository) *TranscriptionHandler {
+	return &TranscriptionHandler{
+		Service:        service,
+		MeetingService: meetingService,
+		SegmentService: segmentService,
+	}
 }
 
+// liveChunkDuration matches the chunk length of the frontend recorder and is
+// used to place segments on the timeline when the client sends no timing
+const liveChunkDuration = 10000
+
 // HandleUpload handles file-based transcription (existing functionality)
 func (h *TranscriptionHandler) HandleUpload(c *gin.Context) {
 	file, err := c.FormFile("audio")
@@ -30,7 +41,7 @@ func (h *TranscriptionHandler) HandleUpload(c *gin.Context) {
 	c.SaveUploadedFile(file, filePath)
 
 	// Transcribe using Groq
-	transcript, err := h.Service.TranscribeFile(filePath)
+	transcript, err := h.Service.TranscribeFile(filePath, "", "")
 	if err != nil {
 		fmt.Println("Groq Error:", err)
 		c.JSON(500, gin.H{"error": "Transcription failed"})
@@ -50,7 +61,9 @@ func (h *TranscriptionHandler) HandleUpload(c *gin.Context) {
 	})
 }
 
-// HandleLiveChunk handles real-time 5-second audio chunks
+// HandleLiveChunk handles real-time 10-second audio chunks. When meeting_id is
+// sent, the text is appended to the meeting's transcript and stored as a
+// segment (optionally with speaker, start_ms and end_ms form fields).
 func (h *TranscriptionHandler) HandleLiveChunk(c *gin.Context) {
 	file, err := c.FormFile("audio")
 	if err != nil {
@@ -67,8 +80,19 @@ func (h *TranscriptionHandler) HandleLiveChunk(c *gin.Context) {
 	}
 	defer os.Remove(tmpPath) // Clean up after transcription
 
+	// Use the meeting's glossary so names and jargon are spelled right, and
+	// its language so Whisper doesn't have to guess it from a short chunk
+	meetingID, meetingErr := strconv.Atoi(c.PostForm("meeting_id"))
+	transcriber, glossary, language := h.Service, "", ""
+	if meetingErr == nil {
+		if meeting, err := h.MeetingService.GetByID(meetingID); err == nil && meeting != nil {
+			transcriber = h.Service.ForWorkspace(meeting.Workspace)
+			glossary, language = meeting.Glossary, meeting.Language
+		}
+	}
+
 	// Transcribe the chunk
-	text, err := h.Service.TranscribeFile(tmpPath)
+	text, err := transcriber.TranscribeFile(tmpPath, glossary, language)
 	if err != nil {
 		fmt.Println("Live chunk transcription error:", err)
 		c.JSON(500, gin.H{"error": "Transcription failed"})
@@ -77,6 +101,36 @@ func (h *TranscriptionHandler) HandleLiveChunk(c *gin.Context) {
 
 	fmt.Printf("🎤 Live chunk: %s\n", text)
 
+	if meetingErr == nil && text != "" {
+		if err := h.saveSegment(c, meetingID, text); err != nil {
+			fmt.Printf("⚠️  Failed to store segment for meeting %d: %v\n", meetingID, err)
+		}
+	}
+
 	// Send back just the text
 	c.JSON(200, gin.H{"text": text})
 }
+
+func (h *TranscriptionHandler) saveSegment(c *gin.Context, meetingID int, text string) error {
+	seg := &services.Segment{
+		MeetingID: meetingID,
+		Speaker:   c.PostForm("speaker"),
+		Text:      text,
+	}
+
+	start, startErr := strconv.ParseInt(c.PostForm("start_ms"), 10, 64)
+	end, endErr := strconv.ParseInt(c.PostForm("end_ms"), 10, 64)
+	if startErr != nil || endErr != nil || end < start {
+		last, err := h.SegmentService.LastEnd(meetingID)
+		if err != nil {
+			return err
+		}
+		start, end = last, last+liveChunkDuration
+	}
+	seg.StartMs, seg.EndMs = start, end
+
+	if err := h.SegmentService.Add(seg); err != nil {
+		return err
+	}
+	return h.MeetingService.AppendTranscript(meetingID, text)
+}
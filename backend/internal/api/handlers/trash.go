@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"backend/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TrashHandler struct {
+	Service        *services.TrashService
+	MeetingService services.MeetingRepository
+}
+
+func NewTrashHandler(service *services.TrashService, meetingService services.MeetingRepository) *TrashHandler {
+	return &TrashHandler{
+		Service:        service,
+		MeetingService: meetingService,
+	}
+}
+
+// GetAll lists the meetings in the trash and when they will be purged
+func (h *TrashHandler) GetAll(c *gin.Context) {
+	meetings, err := h.Service.GetAll()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"meetings": meetings, "retention_days": h.Service.RetentionDays})
+}
+
+// Restore takes a meeting out of the trash
+func (h *TrashHandler) Restore(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meeting ID"})
+		return
+	}
+
+	found, err := h.MeetingService.Restore(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Meeting not found in trash"})
+		return
+	}
+
+	meeting, _ := h.MeetingService.GetByID(id)
+	c.JSON(http.StatusOK, meeting)
+}
+
+// Purge permanently deletes a trashed meeting and its audio
+func (h *TrashHandler) Purge(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meeting ID"})
+		return
+	}
+
+	found, err := h.Service.Purge(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Meeting not found in trash"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Meeting permanently deleted"})
+}
+
+// Empty permanently deletes everything in the trash
+func (h *TrashHandler) Empty(c *gin.Context) {
+	purged, err := h.Service.Empty()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "purged": purged})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Trash emptied", "purged": purged})
+}
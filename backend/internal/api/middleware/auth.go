@@ -41,7 +41,12 @@ func JWTAuth() gin.HandlerFunc {
 			return
 		}
 
-		// Token is valid, continue
+		// Token is valid; handlers record the username as the author of changes
+		if claims, ok := token.Claims.(jwt.MapClaims); ok {
+			if username, ok := claims["username"].(string); ok {
+				c.Set("username", username)
+			}
+		}
 		c.Next()
 	}
 }
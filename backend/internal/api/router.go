@@ -5,6 +5,7 @@ import (
 	"backend/internal/api/middleware"
 	"backend/internal/config"
 	"backend/internal/services"
+	"context"
 	"log"
 	"time"
 
@@ -12,34 +13,114 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func SetupRouter(cfg *config.Config) *gin.Engine {
+// SetupRouter wires the services and handlers to repos and registers the routes
+func SetupRouter(cfg *config.Config, repos services.Repositories) *gin.Engine {
 	r := gin.Default()
 
 	// Enable CORS for frontend
 	corsConfig := cors.Config{
 		AllowOrigins:     []string{"http://localhost:3000"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "Cache-Control", "If-Match"},
+		ExposeHeaders:    []string{"Content-Length", "Content-Disposition", "X-Cache", "ETag"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}
 	r.Use(cors.New(corsConfig))
 
 	// Initialize services
-	transcriptionService := services.NewTranscriptionService(cfg.GroqAPIKey)
-	geminiService, err := services.NewGeminiService(cfg.GeminiAPIKey)
+	aiSettingsService, err := services.NewAISettingsService(repos.DB)
+	if err != nil {
+		log.Fatalf("Failed to load AI settings: %v", err)
+	}
+	redactionService := services.NewRedactionService(repos.DB)
+	transcriptionService := services.NewTranscriptionService(cfg.GroqAPIKey, redactionService, aiSettingsService)
+	geminiService, err := services.NewGeminiService(cfg.GeminiAPIKey, redactionService, aiSettingsService)
 	if err != nil {
 		log.Fatalf("Failed to initialize Gemini service: %v", err)
 	}
-	meetingService := services.NewMeetingService()
+
+	// Check configured models against the provider catalogs without delaying startup
+	go func() {
+		catalogs := services.BuildModelCatalog(context.Background(), geminiService, transcriptionService)
+		for _, catalog := range catalogs {
+			if catalog.Error != "" {
+				log.Printf("⚠️  Could not list %s models: %s", catalog.Provider, catalog.Error)
+			}
+		}
+		for _, warning := range aiSettingsService.Validate(catalogs) {
+			log.Printf("⚠️  WARNING: %s", warning)
+		}
+	}()
+	meetingService := repos.Meetings
+	segmentService := repos.Segments
+	taskService := repos.Tasks
+	tagService := repos.Tags
+	searchService := services.NewSearchService(repos.DB, repos.Dialect)
+	folderService := repos.Folders
+	revisionService := services.NewNoteRevisionService(repos.DB)
+	peopleService := services.NewPeopleService(repos.DB)
+	templateService := services.NewTemplateService(repos.DB)
+	analyticsService := services.NewAnalyticsService(repos.DB, meetingService, segmentService, geminiService)
 	audioMergerService := services.NewAudioMergerService(cfg.StoragePath)
+	aiCacheService := services.NewAICacheService(repos.DB, cfg.AICacheTTL, cfg.AICacheMaxEntries)
+	digestService := services.NewDigestService(repos.DB, meetingService, taskService, geminiService, aiCacheService)
+	trashService := services.NewTrashService(repos.DB, meetingService, audioMergerService, cfg.TrashRetentionDays)
+	calendarService := services.NewCalendarService(repos.DB, folderService, cfg.CalendarHorizonDays)
+	exportService := services.NewExportService(meetingService, segmentService, taskService, peopleService, folderService, cfg.ExportTemplateDir)
+	importService := services.NewImportService(meetingService, folderService, cfg.StoragePath)
+	backupService := services.NewBackupService(repos.DB, repos.Dialect, cfg.StoragePath, cfg.BackupDir, cfg.BackupKeep)
+	emailService := services.NewEmailService(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPFrom)
+
+	// Index meetings stored before search existed
+	if indexed, err := searchService.EnsureIndexed(); err != nil {
+		log.Printf("⚠️  Failed to build search index: %v", err)
+	} else if indexed > 0 {
+		log.Printf("🔎 Indexed %d meetings for search", indexed)
+	}
 
 	// Initialize handlers
-	transcriptionHandler := handlers.NewTranscriptionHandler(transcriptionService)
-	aiHandler := handlers.NewAIHandler(geminiService)
+	transcriptionHandler := handlers.NewTranscriptionHandler(transcriptionService, meetingService, segmentService)
+	aiHandler := handlers.NewAIHandler(geminiService, aiCacheService, meetingService, taskService)
 	authHandler := handlers.NewAuthHandler(cfg)
-	meetingHandler := handlers.NewMeetingHandler(meetingService, audioMergerService)
+	meetingHandler := handlers.NewMeetingHandler(meetingService, audioMergerService, geminiService, tagService, folderService, templateService, cfg.AutoTitle, cfg.AutoTag)
+	followUpHandler := handlers.NewFollowUpHandler(meetingService, peopleService, geminiService, emailService)
+	digestHandler := handlers.NewDigestHandler(digestService)
+	redactionHandler := handlers.NewRedactionHandler(redactionService)
+	modelsHandler := handlers.NewModelsHandler(aiSettingsService, geminiService, transcriptionService)
+	analyticsHandler := handlers.NewAnalyticsHandler(analyticsService, meetingService, segmentService)
+	tagHandler := handlers.NewTagHandler(tagService, meetingService, geminiService)
+	searchHandler := handlers.NewSearchHandler(searchService, folderService)
+	folderHandler := handlers.NewFolderHandler(folderService, meetingService)
+	trashHandler := handlers.NewTrashHandler(trashService, meetingService)
+	revisionHandler := handlers.NewRevisionHandler(revisionService, meetingService)
+	peopleHandler := handlers.NewPeopleHandler(peopleService, meetingService)
+	calendarHandler := handlers.NewCalendarHandler(calendarService, folderService)
+	exportHandler := handlers.NewExportHandler(exportService)
+	backupHandler := handlers.NewBackupHandler(backupService)
+	importHandler := handlers.NewImportHandler(importService, folderService)
+	templateHandler := handlers.NewTemplateHandler(templateService, meetingService, geminiService)
+
+	// Scheduled weekly digest
+	if cfg.DigestWeekday != nil {
+		digestService.StartWeeklySchedule(*cfg.DigestWeekday, cfg.DigestHour)
+		log.Printf("📰 Weekly digest scheduled every %s at %02d:00", cfg.DigestWeekday, cfg.DigestHour)
+	}
+
+	// Trash purger, also applies per-meeting retention
+	trashService.StartPurger()
+
+	// Calendar feed, keeps scheduled meetings in step with the calendar
+	if cfg.CalendarFeedURL != "" {
+		calendarService.StartFeedPoller(cfg.CalendarFeedURL, cfg.CalendarPollInterval)
+		log.Printf("📅 Calendar feed synced every %s", cfg.CalendarPollInterval)
+	}
+
+	// Scheduled backups with rotation
+	if cfg.BackupInterval > 0 {
+		backupService.StartSchedule(cfg.BackupInterval)
+		log.Printf("💾 Backups scheduled every %s, keeping %d", cfg.BackupInterval, cfg.BackupKeep)
+	}
 
 	// Public routes (no authentication required)
 	r.POST("/auth/login", authHandler.HandleLogin)
@@ -53,13 +134,113 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 		protected.POST("/live-chunk", transcriptionHandler.HandleLiveChunk)
 		protected.POST("/ai-format", aiHandler.HandleAIFormat)
 
+		// AI cache endpoints
+		protected.GET("/ai/cache", aiHandler.CacheStats)
+		protected.DELETE("/ai/cache", aiHandler.ClearCache)
+
+		// AI model catalog and per-action settings
+		protected.GET("/ai/models", modelsHandler.ListModels)
+		protected.GET("/ai/settings", modelsHandler.GetSettings)
+		protected.PUT("/ai/settings/:action", modelsHandler.UpdateSettings)
+		protected.DELETE("/ai/settings/:action", modelsHandler.ResetSettings)
+
 		// Meeting CRUD endpoints
 		protected.GET("/meetings", meetingHandler.GetAll)
 		protected.GET("/meetings/:id", meetingHandler.GetOne)
 		protected.POST("/meetings", meetingHandler.Create)
 		protected.PUT("/meetings/:id", meetingHandler.Update)
+		protected.PATCH("/meetings/:id", meetingHandler.Patch)
 		protected.DELETE("/meetings/:id", meetingHandler.Delete)
+		protected.POST("/meetings/:id/start", meetingHandler.Start)
 		protected.POST("/meetings/:id/finish", meetingHandler.FinishRecording)
+		protected.POST("/meetings/:id/followup", followUpHandler.HandleFollowUp)
+		protected.GET("/meetings/:id/export", exportHandler.Export)
+
+		// Notes revision history
+		protected.GET("/meetings/:id/revisions", revisionHandler.GetAll)
+		protected.GET("/meetings/:id/revisions/diff", revisionHandler.Diff)
+		protected.GET("/meetings/:id/revisions/:revisionId", revisionHandler.GetOne)
+		protected.POST("/meetings/:id/revisions/:revisionId/restore", revisionHandler.Restore)
+
+		// Trash
+		protected.GET("/trash", trashHandler.GetAll)
+		protected.DELETE("/trash", trashHandler.Empty)
+		protected.DELETE("/trash/:id", trashHandler.Purge)
+		protected.POST("/meetings/:id/restore", trashHandler.Restore)
+
+		// Calendar import
+		protected.POST("/calendar/import", calendarHandler.Import)
+
+		// Transcript import from other meeting tools
+		protected.POST("/import", importHandler.Import)
+
+		// Folders
+		protected.GET("/folders", folderHandler.GetAll)
+		protected.GET("/folders/:id", folderHandler.GetOne)
+		protected.POST("/folders", folderHandler.Create)
+		protected.PUT("/folders/:id", folderHandler.Update)
+		protected.DELETE("/folders/:id", folderHandler.Delete)
+		protected.PUT("/meetings/:id/folder", folderHandler.MoveMeeting)
+
+		// Meeting templates and filling their sections from the transcript
+		protected.GET("/templates", templateHandler.GetAll)
+		protected.GET("/templates/:id", templateHandler.GetOne)
+		protected.POST("/templates", templateHandler.Create)
+		protected.PUT("/templates/:id", templateHandler.Update)
+		protected.DELETE("/templates/:id", templateHandler.Delete)
+		protected.POST("/meetings/:id/autofill", templateHandler.AutoFill)
+
+		// People directory and meeting participants
+		protected.GET("/people", peopleHandler.GetAll)
+		protected.GET("/people/:id", peopleHandler.GetOne)
+		protected.POST("/people", peopleHandler.Create)
+		protected.PUT("/people/:id", peopleHandler.Update)
+		protected.DELETE("/people/:id", peopleHandler.Delete)
+		protected.GET("/people/:id/meetings", peopleHandler.GetMeetings)
+		protected.GET("/meetings/:id/participants", peopleHandler.GetParticipants)
+		protected.POST("/meetings/:id/participants", peopleHandler.AddParticipant)
+		protected.PUT("/meetings/:id/participants/:participantId", peopleHandler.UpdateParticipant)
+		protected.DELETE("/meetings/:id/participants/:participantId", peopleHandler.RemoveParticipant)
+		protected.PUT("/meetings/:id/speakers", peopleHandler.MapSpeaker)
+
+		// Full-text search
+		protected.GET("/search", searchHandler.Search)
+
+		// Tags and meeting types
+		protected.GET("/tags", tagHandler.GetAll)
+		protected.POST("/tags", tagHandler.Create)
+		protected.PUT("/tags/:id", tagHandler.Rename)
+		protected.DELETE("/tags/:id", tagHandler.Delete)
+		protected.GET("/meeting-types", tagHandler.GetMeetingTypes)
+		protected.GET("/meetings/:id/tags", tagHandler.GetMeetingTags)
+		protected.POST("/meetings/:id/tags", tagHandler.AddMeetingTag)
+		protected.DELETE("/meetings/:id/tags/:tagId", tagHandler.RemoveMeetingTag)
+		protected.POST("/meetings/:id/classify", tagHandler.Classify)
+
+		// Transcript segments and conversation analytics
+		protected.GET("/meetings/:id/segments", analyticsHandler.GetSegments)
+		protected.PUT("/meetings/:id/segments/:segmentId", analyticsHandler.UpdateSegmentSpeaker)
+		protected.GET("/meetings/:id/analytics", analyticsHandler.GetMeetingAnalytics)
+		protected.GET("/analytics/trends", analyticsHandler.GetTrends)
+
+		// Digest endpoints
+		protected.GET("/digests", digestHandler.GetDigest)
+		protected.GET("/digests/:id", digestHandler.GetOne)
+
+		// PII redaction endpoints
+		protected.GET("/redaction/policy", redactionHandler.GetPolicy)
+		protected.PUT("/redaction/policy", redactionHandler.UpdatePolicy)
+		protected.GET("/redaction/terms", redactionHandler.GetTerms)
+		protected.POST("/redaction/terms", redactionHandler.AddTerm)
+		protected.DELETE("/redaction/terms/:id", redactionHandler.DeleteTerm)
+		protected.GET("/redaction/reports", redactionHandler.GetReports)
+
+		// Backups of the database and audio
+		protected.GET("/admin/backups", backupHandler.GetAll)
+		protected.POST("/admin/backups", backupHandler.Create)
+		protected.GET("/admin/backups/:name", backupHandler.Download)
+		protected.POST("/admin/backups/:name/verify", backupHandler.Verify)
+		protected.DELETE("/admin/backups/:name", backupHandler.Delete)
 	}
 
 	return r
@@ -3,6 +3,9 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
@@ -13,6 +16,34 @@ type Config struct {
 	AuthPassword string
 	StoragePath  string // Path to store audio files
 	DatabasePath string // Path to store SQLite database
+	DatabaseURL  string // PostgreSQL connection URL, used instead of DatabasePath when set
+
+	AICacheTTL        time.Duration // How long AI results are reused, 0 disables the cache
+	AICacheMaxEntries int           // Upper bound on cached AI results, 0 means unlimited
+
+	AutoTitle bool // Generate titles for untitled meetings after recording
+	AutoTag   bool // Suggest tags and a meeting type after recording
+
+	SMTPHost     string // Optional, enables sending follow-up emails
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	SMTPFrom     string
+
+	DigestWeekday *time.Weekday // Day to generate the weekly digest on, nil disables it
+	DigestHour    int
+
+	TrashRetentionDays int // Days before trashed meetings are purged, 0 keeps them until emptied by hand
+
+	CalendarFeedURL      string        // Optional ICS feed polled for scheduled meetings
+	CalendarPollInterval time.Duration // How often the feed is synced
+	CalendarHorizonDays  int           // How far ahead events become scheduled meetings
+
+	ExportTemplateDir string // Optional directory of templates overriding the built-in export templates
+
+	BackupDir      string        // Where backup archives are written
+	BackupInterval time.Duration // How often backups are taken, 0 disables scheduled backups
+	BackupKeep     int           // Archives kept by rotation, 0 keeps all
 }
 
 func Load() *Config {
@@ -53,6 +84,136 @@ func Load() *Config {
 		databasePath = "./data/echo.db" // Default local database
 	}
 
+	// PostgreSQL - a shared server instead of the SQLite file, for many concurrent users
+	databaseURL := os.Getenv("DATABASE_URL")
+	if databaseURL != "" && !strings.HasPrefix(databaseURL, "postgres://") && !strings.HasPrefix(databaseURL, "postgresql://") {
+		log.Fatal("Invalid DATABASE_URL, use a postgres:// URL")
+	}
+
+	// AI result cache - identical requests within the TTL reuse the stored result
+	aiCacheTTL := 24 * time.Hour
+	if v := os.Getenv("AI_CACHE_TTL"); v != "" {
+		ttl, err := time.ParseDuration(v)
+		if err != nil {
+			log.Fatalf("Invalid AI_CACHE_TTL %q: %v", v, err)
+		}
+		aiCacheTTL = ttl
+	}
+
+	aiCacheMaxEntries := 1000
+	if v := os.Getenv("AI_CACHE_MAX_ENTRIES"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			log.Fatalf("Invalid AI_CACHE_MAX_ENTRIES %q: %v", v, err)
+		}
+		aiCacheMaxEntries = n
+	}
+
+	// Auto titles - set AUTO_TITLE=false to keep "Untitled Meeting" until renamed by hand
+	autoTitle := true
+	if v := os.Getenv("AUTO_TITLE"); v != "" {
+		enabled, err := strconv.ParseBool(v)
+		if err != nil {
+			log.Fatalf("Invalid AUTO_TITLE %q: %v", v, err)
+		}
+		autoTitle = enabled
+	}
+
+	// Auto tags - set AUTO_TAG=false to only tag meetings by hand
+	autoTag := true
+	if v := os.Getenv("AUTO_TAG"); v != "" {
+		enabled, err := strconv.ParseBool(v)
+		if err != nil {
+			log.Fatalf("Invalid AUTO_TAG %q: %v", v, err)
+		}
+		autoTag = enabled
+	}
+
+	// SMTP - optional, follow-up emails can always be downloaded as .eml
+	smtpPort := os.Getenv("SMTP_PORT")
+	if smtpPort == "" {
+		smtpPort = "587"
+	}
+
+	// Weekly digest - e.g. DIGEST_WEEKDAY=monday generates last week's digest every Monday
+	var digestWeekday *time.Weekday
+	if v := os.Getenv("DIGEST_WEEKDAY"); v != "" {
+		for d := time.Sunday; d <= time.Saturday; d++ {
+			if strings.EqualFold(d.String(), v) {
+				day := d
+				digestWeekday = &day
+			}
+		}
+		if digestWeekday == nil {
+			log.Fatalf("Invalid DIGEST_WEEKDAY %q, use a day name like monday", v)
+		}
+	}
+
+	digestHour := 8
+	if v := os.Getenv("DIGEST_HOUR"); v != "" {
+		h, err := strconv.Atoi(v)
+		if err != nil || h < 0 || h > 23 {
+			log.Fatalf("Invalid DIGEST_HOUR %q, use 0-23", v)
+		}
+		digestHour = h
+	}
+
+	// Trash - meetings and their audio are permanently deleted this many days after being trashed
+	trashRetentionDays := 30
+	if v := os.Getenv("TRASH_RETENTION_DAYS"); v != "" {
+		days, err := strconv.Atoi(v)
+		if err != nil || days < 0 {
+			log.Fatalf("Invalid TRASH_RETENTION_DAYS %q, use a number of days or 0 to disable", v)
+		}
+		trashRetentionDays = days
+	}
+
+	// Calendar - events of the ICS feed within the horizon become scheduled meetings
+	calendarPollInterval := 15 * time.Minute
+	if v := os.Getenv("CALENDAR_POLL_INTERVAL"); v != "" {
+		interval, err := time.ParseDuration(v)
+		if err != nil || interval < time.Minute {
+			log.Fatalf("Invalid CALENDAR_POLL_INTERVAL %q, use a duration of at least 1m", v)
+		}
+		calendarPollInterval = interval
+	}
+
+	calendarHorizonDays := 30
+	if v := os.Getenv("CALENDAR_HORIZON_DAYS"); v != "" {
+		days, err := strconv.Atoi(v)
+		if err != nil || days < 1 {
+			log.Fatalf("Invalid CALENDAR_HORIZON_DAYS %q, use a positive number of days", v)
+		}
+		calendarHorizonDays = days
+	}
+
+	// Backups - archives of the database and audio, taken on a schedule and kept up to BACKUP_KEEP
+	backupDir := os.Getenv("BACKUP_DIR")
+	if backupDir == "" {
+		backupDir = "./data/backups"
+	}
+
+	var backupInterval time.Duration
+	if v := os.Getenv("BACKUP_INTERVAL"); v != "" {
+		interval, err := time.ParseDuration(v)
+		if err != nil || (interval != 0 && interval < time.Hour) {
+			log.Fatalf("Invalid BACKUP_INTERVAL %q, use a duration of at least 1h or 0 to disable", v)
+		}
+		backupInterval = interval
+	}
+	if databaseURL != "" && backupInterval > 0 {
+		log.Fatal("BACKUP_INTERVAL needs the SQLite database, back up PostgreSQL with pg_dump")
+	}
+
+	backupKeep := 7
+	if v := os.Getenv("BACKUP_KEEP"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			log.Fatalf("Invalid BACKUP_KEEP %q, use a number of archives or 0 to keep all", v)
+		}
+		backupKeep = n
+	}
+
 	return &Config{
 		GroqAPIKey:   groqKey,
 		GeminiAPIKey: geminiKey,
@@ -61,5 +222,33 @@ func Load() *Config {
 		AuthPassword: authPassword,
 		StoragePath:  storagePath,
 		DatabasePath: databasePath,
+		DatabaseURL:  databaseURL,
+
+		AICacheTTL:        aiCacheTTL,
+		AICacheMaxEntries: aiCacheMaxEntries,
+
+		AutoTitle: autoTitle,
+		AutoTag:   autoTag,
+
+		SMTPHost:     os.Getenv("SMTP_HOST"),
+		SMTPPort:     smtpPort,
+		SMTPUsername: os.Getenv("SMTP_USERNAME"),
+		SMTPPassword: os.Getenv("SMTP_PASSWORD"),
+		SMTPFrom:     os.Getenv("SMTP_FROM"),
+
+		DigestWeekday: digestWeekday,
+		DigestHour:    digestHour,
+
+		TrashRetentionDays: trashRetentionDays,
+
+		CalendarFeedURL:      os.Getenv("CALENDAR_FEED_URL"),
+		CalendarPollInterval: calendarPollInterval,
+		CalendarHorizonDays:  calendarHorizonDays,
+
+		ExportTemplateDir: os.Getenv("EXPORT_TEMPLATE_DIR"),
+
+		BackupDir:      backupDir,
+		BackupInterval: backupInterval,
+		BackupKeep:     backupKeep,
 	}
 }
@@ -0,0 +1,336 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// copyTables are the tables CopyFromSQLite copies, parents before children.
+// SQLite doesn't enforce foreign keys, so rows whose meeting, tag or person
+// no longer exists are left behind.
+var copyTables = []struct {
+	Name  string
+	Where string
+}{
+	{"folders", ""},
+	{"meeting_templates", ""},
+	{"meetings", ""},
+	{"tasks", "meeting_id IN (SELECT id FROM meetings)"},
+	{"ai_cache", ""},
+	{"digests", ""},
+	{"redaction_policies", ""},
+	{"redaction_terms", ""},
+	{"redaction_reports", ""},
+	{"ai_settings", ""},
+	{"transcript_segments", "meeting_id IN (SELECT id FROM meetings)"},
+	{"meeting_analytics", "meeting_id IN (SELECT id FROM meetings)"},
+	{"tags", ""},
+	{"meeting_tags", "meeting_id IN (SELECT id FROM meetings) AND tag_id IN (SELECT id FROM tags)"},
+	{"note_revisions", "meeting_id IN (SELECT id FROM meetings)"},
+	{"people", ""},
+	{"person_aliases", "person_id IN (SELECT id FROM people)"},
+	{"meeting_participants", "meeting_id IN (SELECT id FROM meetings)"},
+}
+
+// uncopiedTables are managed by Migrate or rebuilt by the search index
+var uncopiedTables = map[string]bool{"schema_migrations": true, "meeting_search": true, "segment_search": true}
+
+// copyBatchRows bounds the rows per INSERT, which PostgreSQL limits to 65535 parameters
+const copyBatchRows = 500
+
+// ErrTargetNotEmpty is returned when copying into a PostgreSQL database that already has data
+var ErrTargetNotEmpty = errors.New("the PostgreSQL database already has data")
+
+// TableCopy reports how many rows of a table were copied
+type TableCopy struct {
+	Table   string
+	Rows    int
+	Skipped int // Rows of meetings, tags or people that no longer exist
+}
+
+// postgresColumn is a column of the PostgreSQL schema
+type postgresColumn struct {
+	Name     string
+	DataType string
+	Identity bool
+}
+
+// CopyFromSQLite copies the SQLite database at path into DB, an empty
+// PostgreSQL database migrated to the same schema version, in one
+// transaction. The search index is not copied.
+func CopyFromSQLite(path string) ([]TableCopy, error) {
+	if Current != Postgres {
+		return nil, errors.New("the database to copy into must be PostgreSQL")
+	}
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+
+	src, err := sql.Open("sqlite", path+"?mode=ro")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer src.Close()
+
+	if err := checkCopyTarget(src, path); err != nil {
+		return nil, err
+	}
+
+	tx, err := DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("SET CONSTRAINTS ALL DEFERRED"); err != nil {
+		return nil, err
+	}
+
+	var copies []TableCopy
+	for _, table := range copyTables {
+		columns, err := tableColumns(tx, table.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		copied, err := copyTable(src, tx, table.Name, table.Where, columns)
+		if err != nil {
+			return nil, fmt.Errorf("failed to copy %s: %w", table.Name, err)
+		}
+
+		// IDs generated from now on continue after the copied ones
+		for _, c := range columns {
+			if !c.Identity {
+				continue
+			}
+			if _, err := tx.Exec(
+				"SELECT setval(pg_get_serial_sequence(?, ?), COALESCE((SELECT MAX("+c.Name+") FROM "+table.Name+"), 0) + 1, false)",
+				table.Name, c.Name,
+			); err != nil {
+				return nil, err
+			}
+		}
+		copies = append(copies, *copied)
+	}
+
+	return copies, tx.Commit()
+}
+
+// checkCopyTarget makes sure the source and DB are at the same schema
+// version, that every table of DB is copied and that DB has no data yet
+func checkCopyTarget(src *sql.DB, path string) error {
+	var srcVersion, version int
+	if err := src.QueryRow("SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&srcVersion); err != nil {
+		return fmt.Errorf("%s has no schema version, run \"migrate up\" on it first: %w", path, err)
+	}
+	if err := DB.QueryRow("SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&version); err != nil {
+		return err
+	}
+	if srcVersion != version {
+		return fmt.Errorf("%s is at schema version %d and PostgreSQL at %d, run \"migrate up\" on both with this build first", path, srcVersion, version)
+	}
+
+	copied := map[string]bool{}
+	for _, table := range copyTables {
+		copied[table.Name] = true
+	}
+	rows, err := DB.Query("SELECT table_name FROM information_schema.tables WHERE table_schema = current_schema() AND table_type = 'BASE TABLE'")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			return err
+		}
+		if !copied[table] && !uncopiedTables[table] {
+			return fmt.Errorf("table %s is not copied from SQLite", table)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, table := range copyTables {
+		var found bool
+		if err := DB.QueryRow("SELECT EXISTS (SELECT 1 FROM " + table.Name + ")").Scan(&found); err != nil {
+			return err
+		}
+		if found {
+			return fmt.Errorf("%w in %s", ErrTargetNotEmpty, table.Name)
+		}
+	}
+	return nil
+}
+
+// tableColumns lists the stored columns of a PostgreSQL table
+func tableColumns(tx *sql.Tx, table string) ([]postgresColumn, error) {
+	rows, err := tx.Query(`
+		SELECT column_name, data_type, is_identity = 'YES' FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = ? AND is_generated = 'NEVER'
+		ORDER BY ordinal_position
+	`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []postgresColumn
+	for rows.Next() {
+		var c postgresColumn
+		if err := rows.Scan(&c.Name, &c.DataType, &c.Identity); err != nil {
+			return nil, err
+		}
+		columns = append(columns, c)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("table %s does not exist in PostgreSQL", table)
+	}
+	return columns, rows.Err()
+}
+
+// copyTable copies the rows of table matching where in batches
+func copyTable(src *sql.DB, tx *sql.Tx, table, where string, columns []postgresColumn) (*TableCopy, error) {
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = c.Name
+	}
+	list := strings.Join(names, ", ")
+
+	result := &TableCopy{Table: table}
+	var total int
+	if err := src.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	query := "SELECT " + list + " FROM " + table
+	if where != "" {
+		query += " WHERE " + where
+	}
+	rows, err := src.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	row := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ") + ")"
+	var values []interface{}
+	flush := func() error {
+		n := len(values) / len(columns)
+		if n == 0 {
+			return nil
+		}
+		_, err := tx.Exec(
+			"INSERT INTO "+table+" ("+list+") VALUES "+strings.TrimSuffix(strings.Repeat(row+", ", n), ", "),
+			values...,
+		)
+		result.Rows += n
+		values = values[:0]
+		return err
+	}
+
+	scanned := make([]interface{}, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range scanned {
+		dest[i] = &scanned[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		for i, c := range columns {
+			value, err := postgresValue(scanned[i], c.DataType)
+			if err != nil {
+				return nil, fmt.Errorf("column %s: %w", c.Name, err)
+			}
+			values = append(values, value)
+		}
+		if len(values) >= copyBatchRows*len(columns) {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	result.Skipped = total - result.Rows
+	return result, nil
+}
+
+// postgresValue converts a value read from SQLite for a PostgreSQL column
+// of dataType, as named by information_schema
+func postgresValue(value interface{}, dataType string) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	switch dataType {
+	case "boolean":
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case int64:
+			return v != 0, nil
+		case string:
+			return strconv.ParseBool(v)
+		}
+	case "timestamp with time zone":
+		switch v := value.(type) {
+		case time.Time:
+			return v, nil
+		case string:
+			// SQLite's CURRENT_TIMESTAMP format is UTC
+			for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02T15:04:05"} {
+				if t, err := time.Parse(layout, v); err == nil {
+					return t, nil
+				}
+			}
+			return nil, fmt.Errorf("invalid timestamp %q", v)
+		}
+	case "bigint", "integer":
+		switch v := value.(type) {
+		case int64:
+			return v, nil
+		case bool:
+			if v {
+				return int64(1), nil
+			}
+			return int64(0), nil
+		case float64:
+			if v == math.Trunc(v) {
+				return int64(v), nil
+			}
+		case string:
+			return strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+		}
+	case "double precision":
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case int64:
+			return float64(v), nil
+		}
+	case "text":
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case []byte:
+			return string(v), nil
+		default:
+			return fmt.Sprint(v), nil
+		}
+	}
+	return nil, fmt.Errorf("cannot store %T %v as %s", value, value, dataType)
+}
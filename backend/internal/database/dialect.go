@@ -0,0 +1,32 @@
+package database
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Dialect is the database engine behind DB
+type Dialect string
+
+const (
+	SQLite   Dialect = "sqlite"
+	Postgres Dialect = "postgres"
+)
+
+// Current is the dialect of the open database
+var Current = SQLite
+
+// ErrSnapshotUnsupported is returned by Snapshot on PostgreSQL, which is
+// backed up with its own tools
+var ErrSnapshotUnsupported = errors.New("snapshots are only supported on SQLite")
+
+// IsUniqueViolation reports whether err is a failed UNIQUE constraint
+func IsUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "23505"
+	}
+	return err != nil && strings.Contains(err.Error(), "UNIQUE")
+}
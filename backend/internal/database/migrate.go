@@ -0,0 +1,357 @@
+package database
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed migrations/*.sql postgres_migrations/*.sql
+var migrationFiles embed.FS
+
+// Migration is one numbered schema change, loaded from migrations/NNNN_name.sql,
+// or postgres_migrations/NNNN_name.sql on PostgreSQL. PostgreSQL starts at the
+// SQLite schema version it matches, so versions mean the same on both.
+type Migration struct {
+	Version int
+	Name    string
+	SQL     string
+}
+
+// MigrationStatus reports whether a migration has been applied
+type MigrationStatus struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+// legacyMarkers identify how far databases created before versioned
+// migrations got, by the newest table or column of each version they contain
+var legacyMarkers = []struct {
+	Version int
+	Table   string
+	Column  string
+}{
+	{1, "meetings", ""},
+	{2, "ai_cache", ""},
+	{3, "meetings", "description"},
+	{4, "digests", ""},
+	{5, "redaction_policies", ""},
+	{6, "ai_settings", ""},
+	{7, "transcript_segments", ""},
+	{8, "meetings", "meeting_type"},
+}
+
+// migrator applies migrations to one database
+type migrator struct {
+	db      *sql.DB
+	dialect Dialect
+	path    string // File backed up before an existing SQLite database changes, empty for none
+}
+
+// migrationsDir is the embedded directory with the migrations for a dialect
+func migrationsDir(dialect Dialect) string {
+	if dialect == Postgres {
+		return "postgres_migrations"
+	}
+	return "migrations"
+}
+
+// loadMigrations returns the embedded migrations of a dialect ordered by version
+func loadMigrations(dialect Dialect) ([]Migration, error) {
+	dir := migrationsDir(dialect)
+	entries, err := fs.ReadDir(migrationFiles, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []Migration
+	seen := map[int]string{}
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".sql")
+		number, label, ok := strings.Cut(name, "_")
+		version, err := strconv.Atoi(number)
+		if !ok || err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration %s is not named NNNN_name.sql", entry.Name())
+		}
+		if other, dup := seen[version]; dup {
+			return nil, fmt.Errorf("migrations %s and %s share version %d", other, entry.Name(), version)
+		}
+		seen[version] = entry.Name()
+
+		content, err := migrationFiles.ReadFile(path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, Migration{Version: version, Name: label, SQL: string(content)})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// LatestVersion returns the version of the newest migration this build knows
+func LatestVersion() (int, error) {
+	migrations, err := loadMigrations(Current)
+	if err != nil || len(migrations) == 0 {
+		return 0, err
+	}
+	return migrations[len(migrations)-1].Version, nil
+}
+
+func (m migrator) ensureMigrationsTable() error {
+	appliedAt := "INTEGER"
+	if m.dialect == Postgres {
+		appliedAt = "BIGINT"
+	}
+
+	_, err := m.db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at ` + appliedAt + ` NOT NULL
+		)
+	`)
+	return err
+}
+
+func (m migrator) appliedMigrations() (map[int]time.Time, error) {
+	rows, err := m.db.Query("SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int]time.Time{}
+	for rows.Next() {
+		var version int
+		var at int64
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+		applied[version] = time.Unix(at, 0)
+	}
+
+	return applied, rows.Err()
+}
+
+// MigrationStatuses lists every known migration and whether it has been applied to DB
+func MigrationStatuses() ([]MigrationStatus, error) {
+	return MigrationStatusesOf(DB, Current)
+}
+
+// MigrationStatusesOf lists every known migration and whether it has been
+// applied to db, a database of the given dialect
+func MigrationStatusesOf(db *sql.DB, dialect Dialect) ([]MigrationStatus, error) {
+	return migrator{db: db, dialect: dialect}.statuses()
+}
+
+func (m migrator) statuses() ([]MigrationStatus, error) {
+	if err := m.ensureMigrationsTable(); err != nil {
+		return nil, err
+	}
+
+	migrations, err := loadMigrations(m.dialect)
+	if err != nil {
+		return nil, err
+	}
+	applied, err := m.appliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	// A database from before versioning counts as migrated up to its
+	// baseline, which is recorded by the next Migrate
+	baseline := 0
+	if len(applied) == 0 && m.dialect == SQLite {
+		if baseline, err = m.legacyBaseline(); err != nil {
+			return nil, err
+		}
+	}
+
+	statuses := make([]MigrationStatus, len(migrations))
+	for i, migration := range migrations {
+		statuses[i] = MigrationStatus{Version: migration.Version, Name: migration.Name, Applied: migration.Version <= baseline}
+		if at, ok := applied[migration.Version]; ok {
+			statuses[i].Applied = true
+			statuses[i].AppliedAt = &at
+		}
+	}
+	return statuses, nil
+}
+
+// Migrate applies all pending migrations to DB, each in its own transaction.
+// When an existing database is about to change, it is first copied next to
+// the database file.
+func Migrate() ([]Migration, error) {
+	return migrator{db: DB, dialect: Current, path: dbPath}.migrate()
+}
+
+func (m migrator) migrate() ([]Migration, error) {
+	if err := m.ensureMigrationsTable(); err != nil {
+		return nil, err
+	}
+	if err := m.adoptLegacyDatabase(); err != nil {
+		return nil, err
+	}
+
+	migrations, err := loadMigrations(m.dialect)
+	if err != nil {
+		return nil, err
+	}
+	applied, err := m.appliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, migration := range migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pending = append(pending, migration)
+		}
+	}
+	if len(pending) == 0 {
+		return nil, nil
+	}
+
+	if len(applied) > 0 {
+		backup, err := m.backupBeforeMigration(pending[len(pending)-1].Version)
+		if err != nil {
+			return nil, fmt.Errorf("pre-migration backup failed: %w", err)
+		}
+		if backup != "" {
+			log.Printf("💾 Database backed up to %s", backup)
+		}
+	}
+
+	for i, migration := range pending {
+		if err := m.apply(migration); err != nil {
+			return pending[:i], fmt.Errorf("migration %04d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+		log.Printf("📦 Applied migration %04d_%s", migration.Version, migration.Name)
+	}
+
+	return pending, nil
+}
+
+func (m migrator) apply(migration Migration) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(migration.SQL); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(
+		"INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)",
+		migration.Version, migration.Name, time.Now().Unix(),
+	); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// adoptLegacyDatabase records the migrations already contained in a database
+// created by the unversioned schema setup, so they aren't applied twice
+func (m migrator) adoptLegacyDatabase() error {
+	if m.dialect != SQLite {
+		return nil
+	}
+
+	var count int
+	if err := m.db.QueryRow("SELECT COUNT(*) FROM schema_migrations").Scan(&count); err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	baseline, err := m.legacyBaseline()
+	if err != nil || baseline == 0 {
+		return err
+	}
+
+	migrations, err := loadMigrations(m.dialect)
+	if err != nil {
+		return err
+	}
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().Unix()
+	for _, migration := range migrations {
+		if migration.Version > baseline {
+			break
+		}
+		if _, err := tx.Exec(
+			"INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)",
+			migration.Version, migration.Name, now,
+		); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("📦 Existing database adopted at schema version %d", baseline)
+	return tx.Commit()
+}
+
+// legacyBaseline returns the newest migration whose marker is present, 0 for an empty database
+func (m migrator) legacyBaseline() (int, error) {
+	baseline := 0
+	for _, marker := range legacyMarkers {
+		found, err := m.hasSchemaObject(marker.Table, marker.Column)
+		if err != nil {
+			return 0, err
+		}
+		if found {
+			baseline = marker.Version
+		}
+	}
+	return baseline, nil
+}
+
+// hasSchemaObject reports whether table exists, or when column is set,
+// whether the table has that column
+func (m migrator) hasSchemaObject(table, column string) (bool, error) {
+	if column == "" {
+		var name string
+		err := m.db.QueryRow("SELECT name FROM sqlite_master WHERE type = 'table' AND name = ?", table).Scan(&name)
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return err == nil, err
+	}
+
+	var found int
+	err := m.db.QueryRow("SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", table, column).Scan(&found)
+	return found > 0, err
+}
+
+// backupBeforeMigration copies the database to
+// <db>.pre-v<version>-<timestamp>.bak and returns the backup's path
+func (m migrator) backupBeforeMigration(version int) (string, error) {
+	if m.path == "" || m.path == ":memory:" {
+		return "", nil
+	}
+
+	backup := fmt.Sprintf("%s.pre-v%d-%s.bak", m.path, version, time.Now().Format("20060102-150405"))
+	if err := SnapshotOf(m.db, m.dialect, backup); err != nil {
+		return "", err
+	}
+	return backup, nil
+}
@@ -0,0 +1,21 @@
+-- Schema of the first release
+CREATE TABLE meetings (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	title TEXT DEFAULT 'Untitled Meeting',
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	transcript TEXT DEFAULT '',
+	notes TEXT DEFAULT '',
+	audio_path TEXT DEFAULT '',
+	duration_seconds INTEGER DEFAULT 0,
+	is_recording BOOLEAN DEFAULT FALSE
+);
+
+CREATE TABLE tasks (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	meeting_id INTEGER NOT NULL,
+	content TEXT NOT NULL,
+	completed BOOLEAN DEFAULT FALSE,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (meeting_id) REFERENCES meetings(id) ON DELETE CASCADE
+);
@@ -0,0 +1,10 @@
+-- Cached AI results keyed by action, model settings and input
+CREATE TABLE ai_cache (
+	key TEXT PRIMARY KEY,
+	action TEXT NOT NULL,
+	result TEXT NOT NULL,
+	hit_count INTEGER DEFAULT 0,
+	created_at INTEGER NOT NULL,
+	last_used_at INTEGER NOT NULL,
+	expires_at INTEGER NOT NULL
+);
@@ -0,0 +1,3 @@
+-- Generated meeting titles
+ALTER TABLE meetings ADD COLUMN description TEXT DEFAULT '';
+ALTER TABLE meetings ADD COLUMN auto_title BOOLEAN DEFAULT TRUE;
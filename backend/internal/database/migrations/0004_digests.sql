@@ -0,0 +1,8 @@
+-- Cross-meeting digests
+CREATE TABLE digests (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	period_from TEXT NOT NULL,
+	period_to TEXT NOT NULL,
+	content TEXT NOT NULL,
+	created_at INTEGER NOT NULL
+);
@@ -0,0 +1,22 @@
+-- PII redaction policies, custom terms and per-call reports
+CREATE TABLE redaction_policies (
+	workspace TEXT PRIMARY KEY,
+	enabled BOOLEAN DEFAULT TRUE,
+	detectors TEXT NOT NULL
+);
+
+CREATE TABLE redaction_terms (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	workspace TEXT NOT NULL,
+	term TEXT NOT NULL,
+	UNIQUE (workspace, term)
+);
+
+CREATE TABLE redaction_reports (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	workspace TEXT NOT NULL,
+	action TEXT NOT NULL,
+	counts TEXT NOT NULL,
+	placeholders TEXT NOT NULL,
+	created_at INTEGER NOT NULL
+);
@@ -0,0 +1,8 @@
+-- Per-action model settings overriding the built-in defaults
+CREATE TABLE ai_settings (
+	action TEXT PRIMARY KEY,
+	model TEXT NOT NULL,
+	temperature REAL NOT NULL,
+	max_output_tokens INTEGER DEFAULT 0,
+	safety_settings TEXT NOT NULL DEFAULT '[]'
+);
@@ -0,0 +1,19 @@
+-- Speaker-labelled transcript segments and conversation analytics
+CREATE TABLE transcript_segments (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	meeting_id INTEGER NOT NULL,
+	speaker TEXT NOT NULL DEFAULT 'Unknown',
+	start_ms INTEGER NOT NULL,
+	end_ms INTEGER NOT NULL,
+	text TEXT NOT NULL,
+	FOREIGN KEY (meeting_id) REFERENCES meetings(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_transcript_segments_meeting ON transcript_segments(meeting_id, start_ms);
+
+CREATE TABLE meeting_analytics (
+	meeting_id INTEGER PRIMARY KEY,
+	data TEXT NOT NULL,
+	computed_at INTEGER NOT NULL,
+	FOREIGN KEY (meeting_id) REFERENCES meetings(id) ON DELETE CASCADE
+);
@@ -0,0 +1,18 @@
+-- Meeting types and tags
+ALTER TABLE meetings ADD COLUMN meeting_type TEXT DEFAULT '';
+
+CREATE TABLE tags (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE meeting_tags (
+	meeting_id INTEGER NOT NULL,
+	tag_id INTEGER NOT NULL,
+	source TEXT NOT NULL DEFAULT 'manual',
+	PRIMARY KEY (meeting_id, tag_id),
+	FOREIGN KEY (meeting_id) REFERENCES meetings(id) ON DELETE CASCADE,
+	FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_meeting_tags_tag ON meeting_tags(tag_id);
@@ -0,0 +1,15 @@
+-- Full-text search over meetings and transcript segments. Rows are written by
+-- the services (notes are indexed as plain text), rowid is the meeting or segment ID.
+CREATE VIRTUAL TABLE meeting_search USING fts5(
+	title,
+	description,
+	notes,
+	transcript,
+	tokenize = 'porter unicode61 remove_diacritics 2'
+);
+
+CREATE VIRTUAL TABLE segment_search USING fts5(
+	text,
+	meeting_id UNINDEXED,
+	tokenize = 'porter unicode61 remove_diacritics 2'
+);
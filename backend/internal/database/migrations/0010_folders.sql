@@ -0,0 +1,21 @@
+-- Nestable folders. Meetings copy their folder's defaults when created;
+-- a NULL folder_id means the meeting or folder sits at the top level.
+CREATE TABLE folders (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	parent_id INTEGER,
+	name TEXT NOT NULL,
+	glossary TEXT NOT NULL DEFAULT '',
+	prompt_template TEXT NOT NULL DEFAULT '',
+	retention_days INTEGER NOT NULL DEFAULT 0,
+	created_at INTEGER NOT NULL,
+	FOREIGN KEY (parent_id) REFERENCES folders(id)
+);
+
+CREATE INDEX idx_folders_parent ON folders(parent_id);
+
+ALTER TABLE meetings ADD COLUMN folder_id INTEGER REFERENCES folders(id);
+ALTER TABLE meetings ADD COLUMN glossary TEXT DEFAULT '';
+ALTER TABLE meetings ADD COLUMN prompt_template TEXT DEFAULT '';
+ALTER TABLE meetings ADD COLUMN retention_days INTEGER DEFAULT 0;
+
+CREATE INDEX idx_meetings_folder ON meetings(folder_id);
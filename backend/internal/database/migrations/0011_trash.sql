@@ -0,0 +1,4 @@
+-- Soft delete: trashed meetings keep their data until purged
+ALTER TABLE meetings ADD COLUMN deleted_at INTEGER;
+
+CREATE INDEX idx_meetings_deleted_at ON meetings(deleted_at);
@@ -0,0 +1,20 @@
+-- Every notes save is kept as a revision. source is 'manual' for edits,
+-- 'restore' for restored revisions, or the AI action that produced them.
+CREATE TABLE note_revisions (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	meeting_id INTEGER NOT NULL,
+	notes TEXT NOT NULL,
+	author TEXT NOT NULL DEFAULT '',
+	source TEXT NOT NULL DEFAULT 'manual',
+	created_at INTEGER NOT NULL,
+	updated_at INTEGER NOT NULL,
+	FOREIGN KEY (meeting_id) REFERENCES meetings(id)
+);
+
+CREATE INDEX idx_note_revisions_meeting ON note_revisions(meeting_id, id);
+
+-- Notes written before history existed become each meeting's first revision
+INSERT INTO note_revisions (meeting_id, notes, author, source, created_at, updated_at)
+SELECT id, notes, '', 'manual', saved_at, saved_at
+FROM (SELECT id, notes, CAST(strftime('%s', COALESCE(updated_at, 'now')) AS INTEGER) AS saved_at FROM meetings)
+WHERE notes IS NOT NULL AND notes != '';
@@ -0,0 +1,2 @@
+-- Edit counter for optimistic concurrency, exposed as the meeting's ETag
+ALTER TABLE meetings ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
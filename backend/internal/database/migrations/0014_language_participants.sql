@@ -0,0 +1,14 @@
+-- Spoken language of a meeting as an ISO 639 code, passed to Whisper.
+-- Empty lets Whisper detect it.
+ALTER TABLE meetings ADD COLUMN language TEXT DEFAULT '';
+
+-- Who took part in a meeting, in the order they were listed
+CREATE TABLE meeting_participants (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	meeting_id INTEGER NOT NULL,
+	name TEXT NOT NULL,
+	position INTEGER NOT NULL DEFAULT 0,
+	FOREIGN KEY (meeting_id) REFERENCES meetings(id)
+);
+
+CREATE INDEX idx_meeting_participants_meeting ON meeting_participants(meeting_id, position);
@@ -0,0 +1,27 @@
+-- People directory. Aliases are other names a person goes by, used to
+-- recognise them in participant lists and transcripts.
+CREATE TABLE people (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT NOT NULL,
+	email TEXT NOT NULL DEFAULT '',
+	created_at INTEGER NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_people_email ON people(email COLLATE NOCASE) WHERE email != '';
+
+CREATE TABLE person_aliases (
+	person_id INTEGER NOT NULL,
+	alias TEXT NOT NULL COLLATE NOCASE,
+	PRIMARY KEY (person_id, alias),
+	FOREIGN KEY (person_id) REFERENCES people(id)
+);
+
+CREATE INDEX idx_person_aliases_alias ON person_aliases(alias);
+
+-- Participants may link to a person; role is host, attendee or external, and
+-- speaker is the diarized label (e.g. "Speaker 1") mapped to them
+ALTER TABLE meeting_participants ADD COLUMN person_id INTEGER REFERENCES people(id);
+ALTER TABLE meeting_participants ADD COLUMN role TEXT NOT NULL DEFAULT 'attendee';
+ALTER TABLE meeting_participants ADD COLUMN speaker TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX idx_meeting_participants_person ON meeting_participants(person_id);
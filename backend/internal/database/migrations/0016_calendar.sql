@@ -0,0 +1,16 @@
+-- Meetings imported from a calendar are created ahead of time in the
+-- scheduled state. status is scheduled, recording or finished; is_recording
+-- stays in step with it for older clients.
+ALTER TABLE meetings ADD COLUMN status TEXT NOT NULL DEFAULT 'finished';
+UPDATE meetings SET status = 'recording' WHERE is_recording;
+
+ALTER TABLE meetings ADD COLUMN scheduled_start INTEGER;
+ALTER TABLE meetings ADD COLUMN scheduled_end INTEGER;
+
+-- calendar_uid identifies the event occurrence, calendar_source is the feed
+-- URL it came from, empty for uploaded files
+ALTER TABLE meetings ADD COLUMN calendar_uid TEXT NOT NULL DEFAULT '';
+ALTER TABLE meetings ADD COLUMN calendar_source TEXT NOT NULL DEFAULT '';
+
+CREATE UNIQUE INDEX idx_meetings_calendar_uid ON meetings(calendar_uid) WHERE calendar_uid != '';
+CREATE INDEX idx_meetings_status ON meetings(status);
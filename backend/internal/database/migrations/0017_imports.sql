@@ -0,0 +1,7 @@
+-- Meetings imported from other tools' transcript exports. import_key is the
+-- SHA-256 of the imported file, so importing it again is recognized;
+-- import_source is its file name and format, e.g. "standup.vtt (vtt)".
+ALTER TABLE meetings ADD COLUMN import_key TEXT NOT NULL DEFAULT '';
+ALTER TABLE meetings ADD COLUMN import_source TEXT NOT NULL DEFAULT '';
+
+CREATE UNIQUE INDEX idx_meetings_import_key ON meetings(import_key) WHERE import_key != '';
@@ -0,0 +1,17 @@
+-- Meeting templates for recurring formats like standups, 1:1s and retros.
+-- agenda, sections and tags are JSON arrays; sections are {name, hint}
+-- objects naming the notes headings that auto-fill writes.
+CREATE TABLE meeting_templates (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT NOT NULL UNIQUE,
+	title_pattern TEXT NOT NULL DEFAULT '',
+	agenda TEXT NOT NULL DEFAULT '[]',
+	sections TEXT NOT NULL DEFAULT '[]',
+	tags TEXT NOT NULL DEFAULT '[]',
+	prompt TEXT NOT NULL DEFAULT '',
+	created_at INTEGER NOT NULL,
+	updated_at INTEGER NOT NULL
+);
+
+-- The template a meeting was created from, cleared when it is deleted
+ALTER TABLE meetings ADD COLUMN template_id INTEGER REFERENCES meeting_templates(id);
@@ -0,0 +1,7 @@
+-- Action items extracted from notes are stored as tasks, with who should do
+-- them, by when (YYYY-MM-DD) and how urgent they are. Empty when not known.
+ALTER TABLE tasks ADD COLUMN assignee TEXT NOT NULL DEFAULT '';
+ALTER TABLE tasks ADD COLUMN due_date TEXT NOT NULL DEFAULT '';
+ALTER TABLE tasks ADD COLUMN priority TEXT NOT NULL DEFAULT 'medium';
+
+CREATE INDEX idx_tasks_meeting ON tasks(meeting_id);
@@ -0,0 +1,3 @@
+-- The workspace a meeting belongs to, whose redaction policy applies to the
+-- meeting's AI calls
+ALTER TABLE meetings ADD COLUMN workspace TEXT NOT NULL DEFAULT 'default';
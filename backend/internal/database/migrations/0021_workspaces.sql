@@ -0,0 +1,6 @@
+-- Folders can put their new meetings in a workspace; empty inherits the
+-- parent folder's. Digests summarize the meetings of one workspace.
+ALTER TABLE folders ADD COLUMN workspace TEXT NOT NULL DEFAULT '';
+ALTER TABLE digests ADD COLUMN workspace TEXT NOT NULL DEFAULT 'default';
+
+CREATE INDEX idx_digests_workspace ON digests(workspace, period_from, period_to);
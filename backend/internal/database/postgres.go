@@ -0,0 +1,201 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// InitializePostgres connects to the PostgreSQL database at url and applies pending migrations
+func InitializePostgres(url, storagePath string) error {
+	if err := createStorageDirs(storagePath); err != nil {
+		return err
+	}
+
+	if err := OpenPostgres(url); err != nil {
+		return err
+	}
+
+	// Bring the schema up to date
+	if _, err := Migrate(); err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	log.Printf("📦 Database initialized at: %s", describePostgres(url))
+	return nil
+}
+
+// OpenPostgres connects to the PostgreSQL database at url without touching
+// its schema. Sessions run in UTC, so timestamps passed as text mean the
+// same as they do in SQLite.
+func OpenPostgres(url string) error {
+	config, err := pgx.ParseConfig(url)
+	if err != nil {
+		return fmt.Errorf("invalid PostgreSQL URL: %w", err)
+	}
+	config.RuntimeParams["timezone"] = "UTC"
+
+	db := sql.OpenDB(rebindConnector{stdlib.GetConnector(*config)})
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return fmt.Errorf("failed to connect to PostgreSQL: %w", err)
+	}
+
+	DB = db
+	dbPath = ""
+	Current = Postgres
+	return nil
+}
+
+// describePostgres names the database at url without its credentials
+func describePostgres(url string) string {
+	config, err := pgx.ParseConfig(url)
+	if err != nil {
+		return "PostgreSQL"
+	}
+	return fmt.Sprintf("PostgreSQL database %s on %s:%d", config.Database, config.Host, config.Port)
+}
+
+// rebindConnector lets queries written with SQLite's ? placeholders run on
+// PostgreSQL, which numbers its parameters $1, $2, ...
+type rebindConnector struct {
+	driver.Connector
+}
+
+func (c rebindConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return rebindConn{conn.(*stdlib.Conn)}, nil
+}
+
+type rebindConn struct {
+	*stdlib.Conn
+}
+
+func (c rebindConn) Prepare(query string) (driver.Stmt, error) {
+	return c.Conn.Prepare(rebind(query))
+}
+
+func (c rebindConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	return c.Conn.PrepareContext(ctx, rebind(query))
+}
+
+func (c rebindConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return c.Conn.ExecContext(ctx, rebind(query), args)
+}
+
+func (c rebindConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return c.Conn.QueryContext(ctx, rebind(query), args)
+}
+
+// rebind numbers the ? placeholders of query, leaving quoted strings
+// ('...', E'...' with backslash escapes and $tag$...$tag$), quoted
+// identifiers and comments alone. PostgreSQL operators containing a question
+// mark, like jsonb's ? and ?|, are written doubled (??, ??|) and come out as
+// a single ?.
+func rebind(query string) string {
+	if !strings.Contains(query, "?") {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		end := -1 // end of a quoted or commented span starting at i, exclusive
+		switch {
+		case c == '\'' || c == '"':
+			end = closingQuote(query, i+1, c, false)
+		case (c == 'E' || c == 'e') && strings.HasPrefix(query[i+1:], "'") && (i == 0 || !isIdentByte(query[i-1])):
+			end = closingQuote(query, i+2, '\'', true)
+		case c == '$':
+			if tag := dollarTag(query[i:]); tag != "" {
+				if close := strings.Index(query[i+len(tag):], tag); close >= 0 {
+					end = i + len(tag) + close + len(tag)
+				} else {
+					end = len(query)
+				}
+			}
+		case c == '-' && strings.HasPrefix(query[i:], "--"):
+			end = len(query)
+			if newline := strings.IndexByte(query[i:], '\n'); newline >= 0 {
+				end = i + newline + 1
+			}
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			end = len(query)
+			if close := strings.Index(query[i+2:], "*/"); close >= 0 {
+				end = i + 2 + close + 2
+			}
+		case c == '?' && strings.HasPrefix(query[i:], "??"):
+			b.WriteByte('?')
+			i++
+			continue
+		case c == '?':
+			n++
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(n))
+			continue
+		}
+
+		if end < 0 {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteString(query[i:end])
+		i = end - 1
+	}
+	return b.String()
+}
+
+// closingQuote returns the index just past the quote closing a string or
+// identifier whose content starts at from, or len(query) if it isn't closed.
+// A doubled quote is part of the content, as is any character after a
+// backslash when backslashEscapes is set.
+func closingQuote(query string, from int, quote byte, backslashEscapes bool) int {
+	for i := from; i < len(query); i++ {
+		switch query[i] {
+		case '\\':
+			if backslashEscapes {
+				i++
+			}
+		case quote:
+			if i+1 < len(query) && query[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(query)
+}
+
+// dollarTag returns the $tag$ or $$ opening a dollar-quoted string at the
+// start of s, or "" when s starts with something else, like a $1 parameter
+func dollarTag(s string) string {
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '$':
+			return s[:i+1]
+		case c >= '0' && c <= '9':
+			if i == 1 {
+				return ""
+			}
+		case !isIdentByte(c):
+			return ""
+		}
+	}
+	return ""
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
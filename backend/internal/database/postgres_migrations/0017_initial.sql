@@ -0,0 +1,212 @@
+-- The schema of SQLite migrations 0001 to 0017 in one step. Unix timestamps
+-- stay BIGINT as in SQLite, DATETIME columns become TIMESTAMPTZ and the FTS5
+-- search tables become tables with a weighted tsvector.
+
+-- Deferrable, so copies from SQLite can insert folders before their parents
+CREATE TABLE folders (
+	id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	parent_id BIGINT REFERENCES folders(id) DEFERRABLE,
+	name TEXT NOT NULL,
+	glossary TEXT NOT NULL DEFAULT '',
+	prompt_template TEXT NOT NULL DEFAULT '',
+	retention_days INTEGER NOT NULL DEFAULT 0,
+	created_at BIGINT NOT NULL
+);
+
+CREATE INDEX idx_folders_parent ON folders(parent_id);
+
+CREATE TABLE meetings (
+	id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	title TEXT DEFAULT 'Untitled Meeting',
+	created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+	transcript TEXT DEFAULT '',
+	notes TEXT DEFAULT '',
+	audio_path TEXT DEFAULT '',
+	duration_seconds INTEGER DEFAULT 0,
+	is_recording BOOLEAN DEFAULT FALSE,
+	description TEXT DEFAULT '',
+	auto_title BOOLEAN DEFAULT TRUE,
+	meeting_type TEXT DEFAULT '',
+	folder_id BIGINT REFERENCES folders(id),
+	glossary TEXT DEFAULT '',
+	prompt_template TEXT DEFAULT '',
+	retention_days INTEGER DEFAULT 0,
+	deleted_at BIGINT,
+	version INTEGER NOT NULL DEFAULT 1,
+	language TEXT DEFAULT '',
+	status TEXT NOT NULL DEFAULT 'finished',
+	scheduled_start BIGINT,
+	scheduled_end BIGINT,
+	calendar_uid TEXT NOT NULL DEFAULT '',
+	calendar_source TEXT NOT NULL DEFAULT '',
+	import_key TEXT NOT NULL DEFAULT '',
+	import_source TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_meetings_created_at ON meetings(created_at);
+CREATE INDEX idx_meetings_folder ON meetings(folder_id);
+CREATE INDEX idx_meetings_deleted_at ON meetings(deleted_at);
+CREATE INDEX idx_meetings_status ON meetings(status);
+CREATE UNIQUE INDEX idx_meetings_calendar_uid ON meetings(calendar_uid) WHERE calendar_uid != '';
+CREATE UNIQUE INDEX idx_meetings_import_key ON meetings(import_key) WHERE import_key != '';
+
+CREATE TABLE tasks (
+	id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	meeting_id BIGINT NOT NULL REFERENCES meetings(id) ON DELETE CASCADE,
+	content TEXT NOT NULL,
+	completed BOOLEAN DEFAULT FALSE,
+	created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE ai_cache (
+	key TEXT PRIMARY KEY,
+	action TEXT NOT NULL,
+	result TEXT NOT NULL,
+	hit_count INTEGER DEFAULT 0,
+	created_at BIGINT NOT NULL,
+	last_used_at BIGINT NOT NULL,
+	expires_at BIGINT NOT NULL
+);
+
+CREATE TABLE digests (
+	id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	period_from TEXT NOT NULL,
+	period_to TEXT NOT NULL,
+	content TEXT NOT NULL,
+	created_at BIGINT NOT NULL
+);
+
+CREATE TABLE redaction_policies (
+	workspace TEXT PRIMARY KEY,
+	enabled BOOLEAN DEFAULT TRUE,
+	detectors TEXT NOT NULL
+);
+
+CREATE TABLE redaction_terms (
+	id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	workspace TEXT NOT NULL,
+	term TEXT NOT NULL,
+	UNIQUE (workspace, term)
+);
+
+CREATE TABLE redaction_reports (
+	id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	workspace TEXT NOT NULL,
+	action TEXT NOT NULL,
+	counts TEXT NOT NULL,
+	placeholders TEXT NOT NULL,
+	created_at BIGINT NOT NULL
+);
+
+CREATE TABLE ai_settings (
+	action TEXT PRIMARY KEY,
+	model TEXT NOT NULL,
+	temperature DOUBLE PRECISION NOT NULL,
+	max_output_tokens INTEGER DEFAULT 0,
+	safety_settings TEXT NOT NULL DEFAULT '[]'
+);
+
+CREATE TABLE transcript_segments (
+	id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	meeting_id BIGINT NOT NULL REFERENCES meetings(id) ON DELETE CASCADE,
+	speaker TEXT NOT NULL DEFAULT 'Unknown',
+	start_ms BIGINT NOT NULL,
+	end_ms BIGINT NOT NULL,
+	text TEXT NOT NULL
+);
+
+CREATE INDEX idx_transcript_segments_meeting ON transcript_segments(meeting_id, start_ms);
+
+CREATE TABLE meeting_analytics (
+	meeting_id BIGINT PRIMARY KEY REFERENCES meetings(id) ON DELETE CASCADE,
+	data TEXT NOT NULL,
+	computed_at BIGINT NOT NULL
+);
+
+CREATE TABLE tags (
+	id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	name TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE meeting_tags (
+	meeting_id BIGINT NOT NULL REFERENCES meetings(id) ON DELETE CASCADE,
+	tag_id BIGINT NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+	source TEXT NOT NULL DEFAULT 'manual',
+	PRIMARY KEY (meeting_id, tag_id)
+);
+
+CREATE INDEX idx_meeting_tags_tag ON meeting_tags(tag_id);
+
+-- Search index, rowid is the meeting or segment ID as in the FTS5 tables.
+-- Title to transcript are weighted A to D, which ts_rank scores 1.0, 0.4,
+-- 0.2 and 0.1 like the bm25 column weights on SQLite.
+CREATE TABLE meeting_search (
+	rowid BIGINT PRIMARY KEY,
+	title TEXT NOT NULL DEFAULT '',
+	description TEXT NOT NULL DEFAULT '',
+	notes TEXT NOT NULL DEFAULT '',
+	transcript TEXT NOT NULL DEFAULT '',
+	document TSVECTOR GENERATED ALWAYS AS (
+		setweight(to_tsvector('english', title), 'A') ||
+		setweight(to_tsvector('english', description), 'B') ||
+		setweight(to_tsvector('english', notes), 'C') ||
+		setweight(to_tsvector('english', transcript), 'D')
+	) STORED
+);
+
+CREATE INDEX idx_meeting_search_document ON meeting_search USING GIN (document);
+
+CREATE TABLE segment_search (
+	rowid BIGINT PRIMARY KEY,
+	text TEXT NOT NULL,
+	meeting_id BIGINT NOT NULL,
+	document TSVECTOR GENERATED ALWAYS AS (to_tsvector('english', text)) STORED
+);
+
+CREATE INDEX idx_segment_search_document ON segment_search USING GIN (document);
+CREATE INDEX idx_segment_search_meeting ON segment_search(meeting_id);
+
+CREATE TABLE note_revisions (
+	id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	meeting_id BIGINT NOT NULL REFERENCES meetings(id),
+	notes TEXT NOT NULL,
+	author TEXT NOT NULL DEFAULT '',
+	source TEXT NOT NULL DEFAULT 'manual',
+	created_at BIGINT NOT NULL,
+	updated_at BIGINT NOT NULL
+);
+
+CREATE INDEX idx_note_revisions_meeting ON note_revisions(meeting_id, id);
+
+CREATE TABLE people (
+	id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	name TEXT NOT NULL,
+	email TEXT NOT NULL DEFAULT '',
+	created_at BIGINT NOT NULL
+);
+
+-- SQLite compares emails and aliases with COLLATE NOCASE
+CREATE UNIQUE INDEX idx_people_email ON people(LOWER(email)) WHERE email != '';
+CREATE INDEX idx_people_name ON people(LOWER(name));
+
+CREATE TABLE person_aliases (
+	person_id BIGINT NOT NULL REFERENCES people(id),
+	alias TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_person_aliases_person ON person_aliases(person_id, LOWER(alias));
+CREATE INDEX idx_person_aliases_alias ON person_aliases(LOWER(alias));
+
+CREATE TABLE meeting_participants (
+	id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	meeting_id BIGINT NOT NULL REFERENCES meetings(id),
+	name TEXT NOT NULL,
+	position INTEGER NOT NULL DEFAULT 0,
+	person_id BIGINT REFERENCES people(id),
+	role TEXT NOT NULL DEFAULT 'attendee',
+	speaker TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_meeting_participants_meeting ON meeting_participants(meeting_id, position);
+CREATE INDEX idx_meeting_participants_person ON meeting_participants(person_id);
@@ -0,0 +1,14 @@
+-- Meeting templates, see migrations/0018_templates.sql
+CREATE TABLE meeting_templates (
+	id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	name TEXT NOT NULL UNIQUE,
+	title_pattern TEXT NOT NULL DEFAULT '',
+	agenda TEXT NOT NULL DEFAULT '[]',
+	sections TEXT NOT NULL DEFAULT '[]',
+	tags TEXT NOT NULL DEFAULT '[]',
+	prompt TEXT NOT NULL DEFAULT '',
+	created_at BIGINT NOT NULL,
+	updated_at BIGINT NOT NULL
+);
+
+ALTER TABLE meetings ADD COLUMN template_id BIGINT REFERENCES meeting_templates(id);
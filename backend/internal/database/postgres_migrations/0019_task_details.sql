@@ -0,0 +1,6 @@
+-- Task details, see migrations/0019_task_details.sql
+ALTER TABLE tasks ADD COLUMN assignee TEXT NOT NULL DEFAULT '';
+ALTER TABLE tasks ADD COLUMN due_date TEXT NOT NULL DEFAULT '';
+ALTER TABLE tasks ADD COLUMN priority TEXT NOT NULL DEFAULT 'medium';
+
+CREATE INDEX idx_tasks_meeting ON tasks(meeting_id);
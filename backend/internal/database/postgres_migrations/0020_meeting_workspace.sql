@@ -0,0 +1,2 @@
+-- Meeting workspace, see migrations/0020_meeting_workspace.sql
+ALTER TABLE meetings ADD COLUMN workspace TEXT NOT NULL DEFAULT 'default';
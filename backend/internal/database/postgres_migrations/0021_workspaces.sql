@@ -0,0 +1,5 @@
+-- Folder and digest workspaces, see migrations/0021_workspaces.sql
+ALTER TABLE folders ADD COLUMN workspace TEXT NOT NULL DEFAULT '';
+ALTER TABLE digests ADD COLUMN workspace TEXT NOT NULL DEFAULT 'default';
+
+CREATE INDEX idx_digests_workspace ON digests(workspace, period_from, period_to);
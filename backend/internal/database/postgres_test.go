@@ -0,0 +1,34 @@
+package database
+
+import "testing"
+
+func TestRebind(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"no placeholders", "SELECT 1", "SELECT 1"},
+		{"placeholders", "SELECT * FROM t WHERE a = ? AND b IN (?, ?)", "SELECT * FROM t WHERE a = $1 AND b IN ($2, $3)"},
+		{"string", "SELECT '?' = ?", "SELECT '?' = $1"},
+		{"doubled quote", "SELECT 'it''s ?' = ?", "SELECT 'it''s ?' = $1"},
+		{"backslash in a standard string", `SELECT 'a\' = ?`, `SELECT 'a\' = $1`},
+		{"escape string", `SELECT E'it\'s ?' = ?`, `SELECT E'it\'s ?' = $1`},
+		{"identifier ending in e", "SELECT name FROM t WHERE type='?' AND id = ?", "SELECT name FROM t WHERE type='?' AND id = $1"},
+		{"quoted identifier", `SELECT "odd?" FROM t WHERE id = ?`, `SELECT "odd?" FROM t WHERE id = $1`},
+		{"dollar quote", "SELECT $$ it's ? $$ || ?", "SELECT $$ it's ? $$ || $1"},
+		{"tagged dollar quote", "SELECT $fn$ $$ ? $fn$ = ?", "SELECT $fn$ $$ ? $fn$ = $1"},
+		{"unterminated dollar quote", "SELECT $$ ?", "SELECT $$ ?"},
+		{"line comment", "SELECT ? -- why?\nFROM t WHERE id = ?", "SELECT $1 -- why?\nFROM t WHERE id = $2"},
+		{"block comment", "SELECT /* ? */ ?", "SELECT /* ? */ $1"},
+		{"jsonb operators", "SELECT data ?? ? OR data ??| ? OR data ??& ?", "SELECT data ? $1 OR data ?| $2 OR data ?& $3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rebind(tt.query); got != tt.want {
+				t.Errorf("rebind(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
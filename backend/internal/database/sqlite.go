@@ -6,14 +6,44 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"sync/atomic"
 
 	_ "modernc.org/sqlite"
 )
 
+// DB is the SQLite or PostgreSQL database, see Current
 var DB *sql.DB
 
-// Initialize sets up the SQLite database connection and creates tables
+// dbPath is the file behind DB, used to name backups
+var dbPath string
+
+// Initialize sets up the SQLite database connection and applies pending migrations
 func Initialize(dbPath, storagePath string) error {
+	if err := createStorageDirs(storagePath); err != nil {
+		return err
+	}
+
+	// Ensure DB directory exists
+	dbDir := filepath.Dir(dbPath)
+	if err := os.MkdirAll(dbDir, 0755); err != nil {
+		return fmt.Errorf("failed to create database directory: %w", err)
+	}
+
+	if err := Open(dbPath); err != nil {
+		return err
+	}
+
+	// Bring the schema up to date
+	if _, err := Migrate(); err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	log.Printf("📦 Database initialized at: %s", dbPath)
+	return nil
+}
+
+// createStorageDirs creates the storage directory with its audio and temp directories
+func createStorageDirs(storagePath string) error {
 	// Create storage directory if it doesn't exist
 	if err := os.MkdirAll(storagePath, 0755); err != nil {
 		return fmt.Errorf("failed to create storage directory: %w", err)
@@ -31,53 +61,67 @@ func Initialize(dbPath, storagePath string) error {
 		return fmt.Errorf("failed to create temp directory: %w", err)
 	}
 
-	// Ensure DB directory exists
-	dbDir := filepath.Dir(dbPath)
-	if err := os.MkdirAll(dbDir, 0755); err != nil {
-		return fmt.Errorf("failed to create database directory: %w", err)
-	}
+	return nil
+}
 
-	// Open SQLite database
+// fileOptions open SQLite database files. A connection waits up to 5s for a
+// lock held by another, like the trash purger's, instead of failing at once.
+// Transactions take the write lock when they begin, since one upgrading from
+// a read lock fails without waiting.
+const fileOptions = "?cache=shared&mode=rwc&_pragma=busy_timeout(5000)&_txlock=immediate"
+
+// Open connects to the SQLite database without touching its schema
+func Open(path string) error {
 	var err error
-	DB, err = sql.Open("sqlite", dbPath+"?cache=shared&mode=rwc")
+	DB, err = sql.Open("sqlite", path+fileOptions)
 	if err != nil {
 		return fmt.Errorf("failed to open database: %w", err)
 	}
 
-	// Create tables
-	if err := createTables(); err != nil {
-		return fmt.Errorf("failed to create tables: %w", err)
+	dbPath = path
+	Current = SQLite
+	return nil
+}
+
+// OpenSQLite opens the SQLite database at path and brings its schema up to
+// date. Unlike Initialize it leaves DB alone, so several databases can be
+// open at once. A path of ":memory:" opens a private in-memory database,
+// which goes away when the returned handle is closed.
+func OpenSQLite(path string) (*sql.DB, error) {
+	dsn := path + fileOptions
+	if path == ":memory:" {
+		// Every connection to a named shared-cache memory database sees the
+		// same data, and it lives while the pool keeps a connection open
+		dsn = fmt.Sprintf("file:memory-%d?mode=memory&cache=shared", memoryDatabases.Add(1))
+	}
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
+	if _, err := (migrator{db: db, dialect: SQLite, path: path}).migrate(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
+	}
+	return db, nil
+}
 
-	log.Printf("📦 Database initialized at: %s", dbPath)
-	return nil
+// memoryDatabases numbers the in-memory databases opened by OpenSQLite
+var memoryDatabases atomic.Int64
+
+// Snapshot writes a consistent copy of DB to path, which must not exist
+// yet. Writers may keep going while the copy is taken.
+func Snapshot(path string) error {
+	return SnapshotOf(DB, Current, path)
 }
 
-func createTables() error {
-	schema := `
-	CREATE TABLE IF NOT EXISTS meetings (
-		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		title TEXT DEFAULT 'Untitled Meeting',
-		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		transcript TEXT DEFAULT '',
-		notes TEXT DEFAULT '',
-		audio_path TEXT DEFAULT '',
-		duration_seconds INTEGER DEFAULT 0,
-		is_recording BOOLEAN DEFAULT FALSE
-	);
-
-	CREATE TABLE IF NOT EXISTS tasks (
-		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		meeting_id INTEGER NOT NULL,
-		content TEXT NOT NULL,
-		completed BOOLEAN DEFAULT FALSE,
-		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		FOREIGN KEY (meeting_id) REFERENCES meetings(id) ON DELETE CASCADE
-	);
-	`
-
-	_, err := DB.Exec(schema)
+// SnapshotOf writes a consistent copy of db, a database of the given
+// dialect, to path like Snapshot
+func SnapshotOf(db *sql.DB, dialect Dialect, path string) error {
+	if dialect != SQLite {
+		return ErrSnapshotUnsupported
+	}
+	_, err := db.Exec("VACUUM INTO ?", path)
 	return err
 }
 
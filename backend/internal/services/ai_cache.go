@@ -0,0 +1,159 @@
+package services
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+type AICacheStats struct {
+	Enabled    bool    `json:"enabled"`
+	Entries    int     `json:"entries"`
+	MaxEntries int     `json:"max_entries"`
+	TTLSeconds int     `json:"ttl_seconds"`
+	Hits       int64   `json:"hits"`
+	Misses     int64   `json:"misses"`
+	Bypasses   int64   `json:"bypasses"`
+	HitRate    float64 `json:"hit_rate"`
+}
+
+// AICacheService stores AI results keyed by a hash of everything that
+// influences the model output, so identical requests are only paid for once
+type AICacheService struct {
+	db         *sql.DB
+	TTL        time.Duration
+	MaxEntries int
+
+	hits     atomic.Int64
+	misses   atomic.Int64
+	bypasses atomic.Int64
+}
+
+func NewAICacheService(db *sql.DB, ttl time.Duration, maxEntries int) *AICacheService {
+	return &AICacheService{db: db, TTL: ttl, MaxEntries: maxEntries}
+}
+
+// Enabled reports whether results are cached at all
+func (s *AICacheService) Enabled() bool {
+	return s.TTL > 0
+}
+
+// Key builds the content address for an action run over text
+func (s *AICacheService) Key(action string, settings AIActionSettings, text string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%g\x00%d\x00%v\x00",
+		action, settings.PromptVersion, settings.Model, settings.Temperature, settings.MaxOutputTokens, settings.SafetySettings)
+	h.Write([]byte(text))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Get returns the cached JSON result for key, or nil on a miss
+func (s *AICacheService) Get(key string) (json.RawMessage, error) {
+	if !s.Enabled() {
+		return nil, nil
+	}
+
+	now := time.Now().Unix()
+	var result string
+	err := s.db.QueryRow(
+		"SELECT result FROM ai_cache WHERE key = ? AND expires_at > ?",
+		key, now,
+	).Scan(&result)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			s.misses.Add(1)
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	s.hits.Add(1)
+	if _, err := s.db.Exec(
+		"UPDATE ai_cache SET last_used_at = ?, hit_count = hit_count + 1 WHERE key = ?",
+		now, key,
+	); err != nil {
+		return nil, err
+	}
+
+	return json.RawMessage(result), nil
+}
+
+// Set stores a result and evicts expired or least recently used entries
+func (s *AICacheService) Set(key, action string, result interface{}) error {
+	if !s.Enabled() {
+		return nil
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	_, err = s.db.Exec(`
+		INSERT INTO ai_cache (key, action, result, created_at, last_used_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(key) DO UPDATE SET action = excluded.action, result = excluded.result, hit_count = 0,
+			created_at = excluded.created_at, last_used_at = excluded.last_used_at, expires_at = excluded.expires_at
+	`, key, action, string(data), now.Unix(), now.Unix(), now.Add(s.TTL).Unix())
+	if err != nil {
+		return err
+	}
+
+	return s.evict()
+}
+
+// RecordBypass counts a request that explicitly skipped the cache
+func (s *AICacheService) RecordBypass() {
+	s.bypasses.Add(1)
+}
+
+// Stats returns the hit/miss counters since startup and the current size
+func (s *AICacheService) Stats() (*AICacheStats, error) {
+	var entries int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM ai_cache").Scan(&entries); err != nil {
+		return nil, err
+	}
+
+	stats := &AICacheStats{
+		Enabled:    s.Enabled(),
+		Entries:    entries,
+		MaxEntries: s.MaxEntries,
+		TTLSeconds: int(s.TTL.Seconds()),
+		Hits:       s.hits.Load(),
+		Misses:     s.misses.Load(),
+		Bypasses:   s.bypasses.Load(),
+	}
+	if lookups := stats.Hits + stats.Misses; lookups > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(lookups)
+	}
+
+	return stats, nil
+}
+
+// Clear removes every cached result
+func (s *AICacheService) Clear() error {
+	_, err := s.db.Exec("DELETE FROM ai_cache")
+	return err
+}
+
+func (s *AICacheService) evict() error {
+	if _, err := s.db.Exec("DELETE FROM ai_cache WHERE expires_at <= ?", time.Now().Unix()); err != nil {
+		return err
+	}
+
+	if s.MaxEntries <= 0 {
+		return nil
+	}
+
+	_, err := s.db.Exec(`
+		DELETE FROM ai_cache WHERE key NOT IN (
+			SELECT key FROM ai_cache ORDER BY last_used_at DESC, created_at DESC LIMIT ?
+		)
+	`, s.MaxEntries)
+	return err
+}
@@ -0,0 +1,266 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	ProviderGemini = "gemini"
+	ProviderGroq   = "groq"
+)
+
+type SafetySetting struct {
+	Category  string `json:"category"`
+	Threshold string `json:"threshold"`
+}
+
+// AIActionSettings describes the model parameters an AI action runs with.
+// PromptVersion must be bumped whenever a prompt changes so cached results
+// produced by the old prompt are no longer served.
+type AIActionSettings struct {
+	Action          string          `json:"action"`
+	Provider        string          `json:"provider"`
+	Model           string          `json:"model"`
+	Temperature     float32         `json:"temperature"`
+	MaxOutputTokens int32           `json:"max_output_tokens"` // 0 uses the model default
+	SafetySettings  []SafetySetting `json:"safety_settings"`
+	PromptVersion   string          `json:"prompt_version"`
+	Customized      bool            `json:"customized"`
+}
+
+var defaultAISettings = map[string]AIActionSettings{
+	"beautify":          {Provider: ProviderGemini, Model: "gemini-2.5-flash", Temperature: 0.3, PromptVersion: "beautify-v1"},
+	"extract-tasks":     {Provider: ProviderGemini, Model: "gemini-2.5-flash", Temperature: 0.1, PromptVersion: "extract-tasks-v2"},
+	"generate-title":    {Provider: ProviderGemini, Model: "gemini-2.5-flash", Temperature: 0.4, PromptVersion: "generate-title-v1"},
+	"draft-followup":    {Provider: ProviderGemini, Model: "gemini-2.5-flash", Temperature: 0.3, PromptVersion: "draft-followup-v1"},
+	"summarize":         {Provider: ProviderGemini, Model: "gemini-2.5-flash", Temperature: 0.2, PromptVersion: "summarize-v1"},
+	"synthesize-digest": {Provider: ProviderGemini, Model: "gemini-2.5-flash", Temperature: 0.3, PromptVersion: "synthesize-digest-v1"},
+	"analyze-sentiment": {Provider: ProviderGemini, Model: "gemini-2.5-flash", Temperature: 0.1, PromptVersion: "analyze-sentiment-v1"},
+	"classify-meeting":  {Provider: ProviderGemini, Model: "gemini-2.5-flash", Temperature: 0.2, PromptVersion: "classify-meeting-v1"},
+	"fill-template":     {Provider: ProviderGemini, Model: "gemini-2.5-flash", Temperature: 0.2, PromptVersion: "fill-template-v1"},
+	"transcribe":        {Provider: ProviderGroq, Model: "whisper-large-v3", Temperature: 0, PromptVersion: "transcribe-v1"},
+}
+
+// SafetyCategories and SafetyThresholds are the names accepted in safety settings
+var (
+	SafetyCategories = []string{"harassment", "hate_speech", "sexually_explicit", "dangerous_content"}
+	SafetyThresholds = []string{"block_none", "block_only_high", "block_medium_and_above", "block_low_and_above"}
+)
+
+type ModelInfo struct {
+	Name             string `json:"name"`
+	DisplayName      string `json:"display_name,omitempty"`
+	InputTokenLimit  int32  `json:"input_token_limit,omitempty"`
+	OutputTokenLimit int32  `json:"output_token_limit,omitempty"`
+}
+
+type ProviderCatalog struct {
+	Provider string      `json:"provider"`
+	Models   []ModelInfo `json:"models"`
+	Error    string      `json:"error,omitempty"`
+}
+
+// Has reports whether the provider offers a model
+func (p *ProviderCatalog) Has(model string) bool {
+	for _, m := range p.Models {
+		if m.Name == model {
+			return true
+		}
+	}
+	return false
+}
+
+// AISettingsService holds the model settings for every AI action: the
+// built-in defaults overlaid with the overrides persisted in ai_settings
+type AISettingsService struct {
+	db       *sql.DB
+	mu       sync.RWMutex
+	settings map[string]AIActionSettings
+}
+
+func NewAISettingsService(db *sql.DB) (*AISettingsService, error) {
+	s := &AISettingsService{db: db, settings: map[string]AIActionSettings{}}
+	for action, settings := range defaultAISettings {
+		settings.Action = action
+		s.settings[action] = settings
+	}
+
+	rows, err := s.db.Query("SELECT action, model, temperature, max_output_tokens, safety_settings FROM ai_settings")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var action, model, safety string
+		var temperature float32
+		var maxTokens int32
+		if err := rows.Scan(&action, &model, &temperature, &maxTokens, &safety); err != nil {
+			return nil, err
+		}
+
+		settings, ok := s.settings[action]
+		if !ok {
+			continue // Action no longer exists
+		}
+		settings.Model = model
+		settings.Temperature = temperature
+		settings.MaxOutputTokens = maxTokens
+		if err := json.Unmarshal([]byte(safety), &settings.SafetySettings); err != nil {
+			return nil, fmt.Errorf("invalid safety settings for %s: %w", action, err)
+		}
+		settings.Customized = true
+		s.settings[action] = settings
+	}
+
+	return s, rows.Err()
+}
+
+// Get returns the settings of an action
+func (s *AISettingsService) Get(action string) (AIActionSettings, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	settings, ok := s.settings[action]
+	return settings, ok
+}
+
+// All returns the settings of every action, sorted by action name
+func (s *AISettingsService) All() []AIActionSettings {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	all := make([]AIActionSettings, 0, len(s.settings))
+	for _, settings := range s.settings {
+		all = append(all, settings)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Action < all[j].Action })
+	return all
+}
+
+// Update validates and persists new settings for an action. The provider and
+// prompt version are fixed per action and taken from the defaults.
+func (s *AISettingsService) Update(action string, update AIActionSettings) (*AIActionSettings, error) {
+	current, ok := s.Get(action)
+	if !ok {
+		return nil, fmt.Errorf("unknown action %q", action)
+	}
+
+	if strings.TrimSpace(update.Model) == "" {
+		return nil, fmt.Errorf("model is required")
+	}
+	if update.Temperature < 0 || update.Temperature > 2 {
+		return nil, fmt.Errorf("temperature must be between 0 and 2")
+	}
+	if update.MaxOutputTokens < 0 {
+		return nil, fmt.Errorf("max_output_tokens must not be negative")
+	}
+	for _, ss := range update.SafetySettings {
+		if !contains(SafetyCategories, ss.Category) {
+			return nil, fmt.Errorf("unknown safety category %q, use one of %s", ss.Category, strings.Join(SafetyCategories, ", "))
+		}
+		if !contains(SafetyThresholds, ss.Threshold) {
+			return nil, fmt.Errorf("unknown safety threshold %q, use one of %s", ss.Threshold, strings.Join(SafetyThresholds, ", "))
+		}
+	}
+
+	settings := current
+	settings.Model = update.Model
+	settings.Temperature = update.Temperature
+	settings.MaxOutputTokens = update.MaxOutputTokens
+	settings.SafetySettings = update.SafetySettings
+	settings.Customized = true
+
+	safety, err := json.Marshal(settings.SafetySettings)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.db.Exec(`
+		INSERT INTO ai_settings (action, model, temperature, max_output_tokens, safety_settings) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(action) DO UPDATE SET model = excluded.model, temperature = excluded.temperature,
+			max_output_tokens = excluded.max_output_tokens, safety_settings = excluded.safety_settings
+	`, action, settings.Model, settings.Temperature, settings.MaxOutputTokens, string(safety))
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.settings[action] = settings
+	s.mu.Unlock()
+
+	return &settings, nil
+}
+
+// Reset drops the persisted override of an action, restoring the default
+func (s *AISettingsService) Reset(action string) (*AIActionSettings, error) {
+	settings, ok := defaultAISettings[action]
+	if !ok {
+		return nil, fmt.Errorf("unknown action %q", action)
+	}
+	settings.Action = action
+
+	if _, err := s.db.Exec("DELETE FROM ai_settings WHERE action = ?", action); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.settings[action] = settings
+	s.mu.Unlock()
+
+	return &settings, nil
+}
+
+// Validate checks every configured model against the provider catalogs and
+// returns a warning for each one that is not available
+func (s *AISettingsService) Validate(catalogs []ProviderCatalog) []string {
+	byProvider := map[string]*ProviderCatalog{}
+	for i := range catalogs {
+		byProvider[catalogs[i].Provider] = &catalogs[i]
+	}
+
+	var warnings []string
+	for _, settings := range s.All() {
+		catalog, ok := byProvider[settings.Provider]
+		if !ok || catalog.Error != "" {
+			continue // Can't tell, the provider couldn't be listed
+		}
+		if !catalog.Has(settings.Model) {
+			warnings = append(warnings, fmt.Sprintf(
+				"model %q configured for action %q is not offered by %s anymore, calls will fail until it is changed",
+				settings.Model, settings.Action, settings.Provider,
+			))
+		}
+	}
+
+	return warnings
+}
+
+// BuildModelCatalog lists the models of every configured provider. A provider
+// that can't be reached is reported with an error instead of failing the whole catalog.
+func BuildModelCatalog(ctx context.Context, gemini *GeminiService, transcription *TranscriptionService) []ProviderCatalog {
+	catalogs := []ProviderCatalog{
+		{Provider: ProviderGemini, Models: []ModelInfo{}},
+		{Provider: ProviderGroq, Models: []ModelInfo{}},
+	}
+
+	if models, err := gemini.ListModels(ctx); err != nil {
+		catalogs[0].Error = err.Error()
+	} else {
+		catalogs[0].Models = models
+	}
+
+	if models, err := transcription.ListModels(); err != nil {
+		catalogs[1].Error = err.Error()
+	} else {
+		catalogs[1].Models = models
+	}
+
+	return catalogs
+}
@@ -0,0 +1,358 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrNoTranscript is returned when a meeting has nothing to analyze
+var ErrNoTranscript = errors.New("meeting has no transcript")
+
+// sectionLength is the target length of a sentiment section; long meetings
+// use longer sections so there are never more than maxSections LLM inputs
+const (
+	sectionLength = 5 * time.Minute
+	maxSections   = 12
+)
+
+var fillerPatterns = map[string]*regexp.Regexp{}
+
+func init() {
+	for _, filler := range []string{"um", "uh", "erm", "hmm", "like", "you know", "i mean", "basically", "actually", "literally", "sort of", "kind of"} {
+		fillerPatterns[filler] = regexp.MustCompile(`\b` + regexp.QuoteMeta(filler) + `\b`)
+	}
+}
+
+type SpeakerStats struct {
+	Speaker        string  `json:"speaker"`
+	Segments       int     `json:"segments"`
+	TalkTimeMs     int64   `json:"talk_time_ms"`
+	TalkShare      float64 `json:"talk_share"`
+	Words          int     `json:"words"`
+	WordsPerMinute float64 `json:"words_per_minute"`
+	Questions      int     `json:"questions"`
+	FillerWords    int     `json:"filler_words"`
+	Interruptions  int     `json:"interruptions"` // Times this speaker started while someone else was talking
+}
+
+type MeetingAnalytics struct {
+	MeetingID      int                `json:"meeting_id"`
+	DurationMs     int64              `json:"duration_ms"`
+	TotalWords     int                `json:"total_words"`
+	WordsPerMinute float64            `json:"words_per_minute"`
+	Questions      int                `json:"questions"`
+	Interruptions  int                `json:"interruptions"`
+	OverlapMs      int64              `json:"overlap_ms"`
+	FillerWords    map[string]int     `json:"filler_words"`
+	Speakers       []SpeakerStats     `json:"speakers"`
+	Sentiment      []SectionSentiment `json:"sentiment"`
+	ComputedAt     time.Time          `json:"computed_at"`
+}
+
+type TrendPoint struct {
+	MeetingID      int                `json:"meeting_id"`
+	Title          string             `json:"title"`
+	Date           time.Time          `json:"date"`
+	DurationMs     int64              `json:"duration_ms"`
+	WordsPerMinute float64            `json:"words_per_minute"`
+	FillerRate     float64            `json:"filler_rate"` // Filler words per 100 words
+	Questions      int                `json:"questions"`
+	Interruptions  int                `json:"interruptions"`
+	Sentiment      float64            `json:"sentiment"` // Average section score
+	TalkShare      map[string]float64 `json:"talk_share"`
+}
+
+type SpeakerTrend struct {
+	Speaker        string  `json:"speaker"`
+	Meetings       int     `json:"meetings"`
+	TalkTimeMs     int64   `json:"talk_time_ms"`
+	AvgTalkShare   float64 `json:"avg_talk_share"`
+	WordsPerMinute float64 `json:"words_per_minute"`
+	FillerRate     float64 `json:"filler_rate"`
+	Interruptions  int     `json:"interruptions"`
+}
+
+type AnalyticsTrends struct {
+	Meetings []TrendPoint   `json:"meetings"`
+	Speakers []SpeakerTrend `json:"speakers"`
+}
+
+type AnalyticsService struct {
+	db             *sql.DB
+	MeetingService MeetingRepository
+	SegmentService SegmentRepository
+	GeminiService  *GeminiService
+}
+
+func NewAnalyticsService(db *sql.DB, meetingService MeetingRepository, segmentService SegmentRepository, gemini *GeminiService) *AnalyticsService {
+	return &AnalyticsService{
+		db:             db,
+		MeetingService: meetingService,
+		SegmentService: segmentService,
+		GeminiService:  gemini,
+	}
+}
+
+// Get returns the stored analytics of a meeting, or nil if never computed
+func (s *AnalyticsService) Get(meetingID int) (*MeetingAnalytics, error) {
+	var data string
+	err := s.db.QueryRow("SELECT data FROM meeting_analytics WHERE meeting_id = ?", meetingID).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var a MeetingAnalytics
+	if err := json.Unmarshal([]byte(data), &a); err != nil {
+		return nil, fmt.Errorf("corrupt analytics for meeting %d: %w", meetingID, err)
+	}
+	return &a, nil
+}
+
+// Compute analyzes a meeting's segments, asks the LLM for per-section sentiment and stores the result
+func (s *AnalyticsService) Compute(meeting *Meeting) (*MeetingAnalytics, error) {
+	segments, err := s.SegmentService.GetByMeeting(meeting.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Meetings recorded before segments existed only have the flat transcript
+	if len(segments) == 0 && strings.TrimSpace(meeting.Transcript) != "" {
+		segments = []Segment{{
+			MeetingID: meeting.ID,
+			Speaker:   UnknownSpeaker,
+			EndMs:     int64(meeting.DurationSeconds) * 1000,
+			Text:      strings.TrimSpace(meeting.Transcript),
+		}}
+	}
+	if len(segments) == 0 {
+		return nil, ErrNoTranscript
+	}
+
+	a := computeAnalytics(segments)
+	a.MeetingID = meeting.ID
+
+	sentiment, err := s.GeminiService.ForWorkspace(meeting.Workspace).AnalyzeSentiment(sectionTexts(segments))
+	if err != nil {
+		return nil, err
+	}
+	a.Sentiment = sentiment
+	a.ComputedAt = time.Now().UTC().Truncate(time.Second)
+
+	data, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.db.Exec(`
+		INSERT INTO meeting_analytics (meeting_id, data, computed_at) VALUES (?, ?, ?)
+		ON CONFLICT(meeting_id) DO UPDATE SET data = excluded.data, computed_at = excluded.computed_at
+	`, meeting.ID, string(data), a.ComputedAt.Unix())
+	if err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// Trends aggregates stored analytics of meetings created in [from, to)
+func (s *AnalyticsService) Trends(from, to time.Time) (*AnalyticsTrends, error) {
+	meetings, err := s.MeetingService.GetInRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	trends := &AnalyticsTrends{Meetings: []TrendPoint{}, Speakers: []SpeakerTrend{}}
+	type speakerTotals struct {
+		SpeakerTrend
+		shareSum   float64
+		words      int
+		fillers    int
+		talkTimeMs int64
+	}
+	totals := map[string]*speakerTotals{}
+
+	for _, m := range meetings {
+		a, err := s.Get(m.ID)
+		if err != nil {
+			return nil, err
+		}
+		if a == nil {
+			continue
+		}
+
+		point := TrendPoint{
+			MeetingID:      m.ID,
+			Title:          m.Title,
+			Date:           m.CreatedAt,
+			DurationMs:     a.DurationMs,
+			WordsPerMinute: a.WordsPerMinute,
+			Questions:      a.Questions,
+			Interruptions:  a.Interruptions,
+			TalkShare:      map[string]float64{},
+		}
+
+		fillers := 0
+		for _, n := range a.FillerWords {
+			fillers += n
+		}
+		point.FillerRate = per100(fillers, a.TotalWords)
+
+		if len(a.Sentiment) > 0 {
+			sum := 0.0
+			for _, sec := range a.Sentiment {
+				sum += sec.Score
+			}
+			point.Sentiment = sum / float64(len(a.Sentiment))
+		}
+
+		for _, sp := range a.Speakers {
+			point.TalkShare[sp.Speaker] = sp.TalkShare
+
+			t, ok := totals[sp.Speaker]
+			if !ok {
+				t = &speakerTotals{SpeakerTrend: SpeakerTrend{Speaker: sp.Speaker}}
+				totals[sp.Speaker] = t
+			}
+			t.Meetings++
+			t.shareSum += sp.TalkShare
+			t.words += sp.Words
+			t.fillers += sp.FillerWords
+			t.talkTimeMs += sp.TalkTimeMs
+			t.Interruptions += sp.Interruptions
+		}
+
+		trends.Meetings = append(trends.Meetings, point)
+	}
+
+	for _, t := range totals {
+		t.TalkTimeMs = t.talkTimeMs
+		t.AvgTalkShare = t.shareSum / float64(t.Meetings)
+		t.WordsPerMinute = wordsPerMinute(t.words, t.talkTimeMs)
+		t.FillerRate = per100(t.fillers, t.words)
+		trends.Speakers = append(trends.Speakers, t.SpeakerTrend)
+	}
+	sort.Slice(trends.Speakers, func(i, j int) bool { return trends.Speakers[i].TalkTimeMs > trends.Speakers[j].TalkTimeMs })
+
+	return trends, nil
+}
+
+// computeAnalytics derives the deterministic metrics from chronologically ordered segments
+func computeAnalytics(segments []Segment) *MeetingAnalytics {
+	a := &MeetingAnalytics{FillerWords: map[string]int{}, Speakers: []SpeakerStats{}, Sentiment: []SectionSentiment{}}
+	bySpeaker := map[string]*SpeakerStats{}
+	var order []string
+
+	start, end := segments[0].StartMs, segments[0].EndMs
+	var lastEnd int64 = -1
+	var lastSpeaker string
+
+	for _, seg := range segments {
+		sp, ok := bySpeaker[seg.Speaker]
+		if !ok {
+			sp = &SpeakerStats{Speaker: seg.Speaker}
+			bySpeaker[seg.Speaker] = sp
+			order = append(order, seg.Speaker)
+		}
+
+		lower := strings.ToLower(seg.Text)
+		words := len(strings.Fields(seg.Text))
+		questions := strings.Count(seg.Text, "?")
+
+		sp.Segments++
+		sp.TalkTimeMs += seg.EndMs - seg.StartMs
+		sp.Words += words
+		sp.Questions += questions
+		a.TotalWords += words
+		a.Questions += questions
+
+		for filler, pattern := range fillerPatterns {
+			if n := len(pattern.FindAllStringIndex(lower, -1)); n > 0 {
+				a.FillerWords[filler] += n
+				sp.FillerWords += n
+			}
+		}
+
+		// Starting before the previous speaker finished is an interruption
+		if lastEnd > seg.StartMs && lastSpeaker != seg.Speaker {
+			sp.Interruptions++
+			a.Interruptions++
+			a.OverlapMs += min(lastEnd, seg.EndMs) - seg.StartMs
+		}
+		if seg.EndMs > lastEnd {
+			lastEnd, lastSpeaker = seg.EndMs, seg.Speaker
+		}
+
+		start = min(start, seg.StartMs)
+		end = max(end, seg.EndMs)
+	}
+
+	a.DurationMs = end - start
+	a.WordsPerMinute = wordsPerMinute(a.TotalWords, a.DurationMs)
+
+	var totalTalk int64
+	for _, sp := range bySpeaker {
+		totalTalk += sp.TalkTimeMs
+	}
+	for _, name := range order {
+		sp := bySpeaker[name]
+		if totalTalk > 0 {
+			sp.TalkShare = float64(sp.TalkTimeMs) / float64(totalTalk)
+		}
+		sp.WordsPerMinute = wordsPerMinute(sp.Words, sp.TalkTimeMs)
+		a.Speakers = append(a.Speakers, *sp)
+	}
+	sort.SliceStable(a.Speakers, func(i, j int) bool { return a.Speakers[i].TalkTimeMs > a.Speakers[j].TalkTimeMs })
+
+	return a
+}
+
+// sectionTexts groups segments into time windows rendered as "Speaker: text" lines
+func sectionTexts(segments []Segment) []string {
+	first, last := segments[0].StartMs, segments[len(segments)-1].EndMs
+	window := sectionLength.Milliseconds()
+	if span := last - first; span > window*maxSections {
+		window = span/maxSections + 1
+	}
+
+	var sections []string
+	var current strings.Builder
+	sectionEnd := first + window
+	for _, seg := range segments {
+		if seg.StartMs >= sectionEnd && current.Len() > 0 {
+			sections = append(sections, current.String())
+			current.Reset()
+			for seg.StartMs >= sectionEnd {
+				sectionEnd += window
+			}
+		}
+		fmt.Fprintf(&current, "%s: %s\n", seg.Speaker, seg.Text)
+	}
+	if current.Len() > 0 {
+		sections = append(sections, current.String())
+	}
+
+	return sections
+}
+
+func wordsPerMinute(words int, ms int64) float64 {
+	if ms <= 0 {
+		return 0
+	}
+	return float64(words) / (float64(ms) / 60000)
+}
+
+func per100(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) * 100 / float64(total)
+}
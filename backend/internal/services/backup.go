@@ -0,0 +1,605 @@
+package services
+
+import (
+	"archive/tar"
+	"backend/internal/database"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Backup archives are tar files of the database snapshot, the audio files
+// under audio/ and a manifest.json written last with every file's checksum
+const (
+	backupFormatVersion = 1
+	backupPrefix        = "echo-backup-"
+	backupSuffix        = ".tar"
+	backupTimeLayout    = "20060102-150405"
+	backupDatabaseEntry = "echo.db"
+	backupManifestEntry = "manifest.json"
+	backupAudioEntry    = "audio/"
+	maxBackupManifest   = 64 << 20
+)
+
+var (
+	ErrBackupRunning   = errors.New("a backup is already running")
+	ErrInvalidBackup   = errors.New("invalid backup archive")
+	ErrRestoreConflict = errors.New("the instance already has data, restore with force to replace it")
+
+	ErrBackupUnsupported = errors.New("backups cover the SQLite database, back up PostgreSQL with pg_dump")
+)
+
+// BackupManifest describes the contents of a backup archive
+type BackupManifest struct {
+	Version       int          `json:"version"`
+	CreatedAt     time.Time    `json:"created_at"`
+	SchemaVersion int          `json:"schema_version"`
+	AudioDir      string       `json:"audio_dir"` // Audio directory as stored in meetings.audio_path
+	Files         []BackupFile `json:"files"`
+}
+
+// BackupFile is a file in a backup archive with its size and SHA-256
+type BackupFile struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Backup is an archive in the backup directory
+type Backup struct {
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type BackupService struct {
+	db          *sql.DB
+	dialect     database.Dialect
+	StoragePath string
+	Dir         string // Where archives are written
+	Keep        int    // Archives kept by rotation, 0 keeps all
+
+	running sync.Mutex
+}
+
+func NewBackupService(db *sql.DB, dialect database.Dialect, storagePath, dir string, keep int) *BackupService {
+	return &BackupService{
+		db:          db,
+		dialect:     dialect,
+		StoragePath: storagePath,
+		Dir:         dir,
+		Keep:        keep,
+	}
+}
+
+// Create writes a backup archive of the live database and the audio files,
+// then rotates old archives. Only one backup runs at a time.
+func (s *BackupService) Create() (*Backup, error) {
+	if s.dialect != database.SQLite {
+		return nil, ErrBackupUnsupported
+	}
+	if !s.running.TryLock() {
+		return nil, ErrBackupRunning
+	}
+	defer s.running.Unlock()
+
+	if err := os.MkdirAll(s.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	createdAt := time.Now()
+	name := backupPrefix + createdAt.Format(backupTimeLayout) + backupSuffix
+	archivePath := filepath.Join(s.Dir, name)
+	if _, err := os.Stat(archivePath); err == nil {
+		return nil, fmt.Errorf("backup %s already exists", name)
+	}
+
+	// VACUUM INTO gives a consistent copy while recordings keep writing
+	snapshot := filepath.Join(s.Dir, "."+name+".db")
+	os.Remove(snapshot)
+	if err := database.SnapshotOf(s.db, s.dialect, snapshot); err != nil {
+		return nil, fmt.Errorf("failed to snapshot database: %w", err)
+	}
+	defer os.Remove(snapshot)
+
+	schemaVersion, err := s.appliedSchemaVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	partial := archivePath + ".partial"
+	f, err := os.Create(partial)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(partial)
+	defer f.Close()
+
+	audioDir := filepath.Join(s.StoragePath, "audio")
+	manifest := BackupManifest{
+		Version:       backupFormatVersion,
+		CreatedAt:     createdAt.UTC(),
+		SchemaVersion: schemaVersion,
+		AudioDir:      audioDir,
+	}
+	archive := tar.NewWriter(f)
+	add := func(entry, source string) error {
+		file, err := addBackupFile(archive, entry, source)
+		if file != nil {
+			manifest.Files = append(manifest.Files, *file)
+		}
+		return err
+	}
+
+	if err := add(backupDatabaseEntry, snapshot); err != nil {
+		return nil, err
+	}
+	err = filepath.WalkDir(audioDir, func(p string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(audioDir, p)
+		if err != nil {
+			return err
+		}
+		return add(backupAudioEntry+filepath.ToSlash(rel), p)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to archive audio: %w", err)
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	header := &tar.Header{Name: backupManifestEntry, Mode: 0644, Size: int64(len(data)), ModTime: createdAt, Typeflag: tar.TypeReg}
+	if err := archive.WriteHeader(header); err != nil {
+		return nil, err
+	}
+	if _, err := archive.Write(data); err != nil {
+		return nil, err
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	if err := f.Sync(); err != nil {
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(partial, archivePath); err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.Rotate(); err != nil {
+		log.Printf("⚠️  Failed to rotate backups: %v", err)
+	}
+
+	return &Backup{Name: name, Size: info.Size(), CreatedAt: createdAt}, nil
+}
+
+// addBackupFile copies a file into the archive and returns its manifest
+// entry. Files removed in the meantime, e.g. purged audio, are skipped.
+func addBackupFile(archive *tar.Writer, entry, source string) (*BackupFile, error) {
+	f, err := os.Open(source)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	header := &tar.Header{Name: entry, Mode: 0644, Size: info.Size(), ModTime: info.ModTime(), Typeflag: tar.TypeReg}
+	if err := archive.WriteHeader(header); err != nil {
+		return nil, err
+	}
+
+	hash := sha256.New()
+	if _, err := io.CopyN(io.MultiWriter(archive, hash), f, info.Size()); err != nil {
+		return nil, fmt.Errorf("failed to archive %s: %w", source, err)
+	}
+
+	return &BackupFile{Path: entry, Size: info.Size(), SHA256: hex.EncodeToString(hash.Sum(nil))}, nil
+}
+
+// appliedSchemaVersion returns the newest migration applied to the database
+func (s *BackupService) appliedSchemaVersion() (int, error) {
+	statuses, err := database.MigrationStatusesOf(s.db, s.dialect)
+	if err != nil {
+		return 0, err
+	}
+	version := 0
+	for _, status := range statuses {
+		if status.Applied {
+			version = status.Version
+		}
+	}
+	return version, nil
+}
+
+// List returns the archives in the backup directory, newest first
+func (s *BackupService) List() ([]Backup, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []Backup{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	backups := []Backup{}
+	for _, entry := range entries {
+		createdAt, ok := backupTime(entry.Name())
+		if !ok || !entry.Type().IsRegular() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		backups = append(backups, Backup{Name: entry.Name(), Size: info.Size(), CreatedAt: createdAt})
+	}
+
+	sort.Slice(backups, func(i, j int) bool { return backups[i].CreatedAt.After(backups[j].CreatedAt) })
+	return backups, nil
+}
+
+// backupTime parses the creation time from an archive name, and reports
+// whether name is an archive name at all
+func backupTime(name string) (time.Time, bool) {
+	stamp, ok := strings.CutPrefix(name, backupPrefix)
+	if !ok {
+		return time.Time{}, false
+	}
+	if stamp, ok = strings.CutSuffix(stamp, backupSuffix); !ok {
+		return time.Time{}, false
+	}
+	t, err := time.ParseInLocation(backupTimeLayout, stamp, time.Local)
+	return t, err == nil
+}
+
+// Path returns the file of a named archive, or "" if there is none
+func (s *BackupService) Path(name string) (string, error) {
+	if _, ok := backupTime(name); !ok || filepath.Base(name) != name {
+		return "", nil
+	}
+	archivePath := filepath.Join(s.Dir, name)
+	if _, err := os.Stat(archivePath); errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	return archivePath, nil
+}
+
+// Delete removes a named archive
+func (s *BackupService) Delete(name string) (bool, error) {
+	archivePath, err := s.Path(name)
+	if err != nil || archivePath == "" {
+		return false, err
+	}
+	return true, os.Remove(archivePath)
+}
+
+// Rotate deletes all but the newest Keep archives and returns how many it deleted
+func (s *BackupService) Rotate() (int, error) {
+	if s.Keep <= 0 {
+		return 0, nil
+	}
+	backups, err := s.List()
+	if err != nil || len(backups) <= s.Keep {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, backup := range backups[s.Keep:] {
+		if err := os.Remove(filepath.Join(s.Dir, backup.Name)); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// StartSchedule writes a backup every interval, the first one interval after startup
+func (s *BackupService) StartSchedule(interval time.Duration) {
+	go func() {
+		for {
+			time.Sleep(interval)
+
+			backup, err := s.Create()
+			if err != nil {
+				log.Printf("⚠️  Scheduled backup failed: %v", err)
+				continue
+			}
+			log.Printf("💾 Backup written to %s (%d MB)", filepath.Join(s.Dir, backup.Name), backup.Size>>20)
+		}
+	}()
+}
+
+// VerifyBackup reads a whole archive and checks every file against the
+// manifest's sizes and checksums
+func VerifyBackup(archivePath string) (*BackupManifest, error) {
+	return scanBackup(archivePath, nil)
+}
+
+// scanBackup reads an archive, passing each file to visit if set, and checks
+// the files against the manifest once everything has been read
+func scanBackup(archivePath string, visit func(entry string, r io.Reader) error) (*BackupManifest, error) {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var manifest *BackupManifest
+	found := map[string]BackupFile{}
+	archive := tar.NewReader(f)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidBackup, err)
+		}
+		if header.Typeflag == tar.TypeDir {
+			continue
+		}
+		if header.Typeflag != tar.TypeReg {
+			return nil, fmt.Errorf("%w: %s is not a regular file", ErrInvalidBackup, header.Name)
+		}
+
+		if header.Name == backupManifestEntry {
+			if manifest != nil {
+				return nil, fmt.Errorf("%w: more than one manifest", ErrInvalidBackup)
+			}
+			manifest = &BackupManifest{}
+			if err := json.NewDecoder(io.LimitReader(archive, maxBackupManifest)).Decode(manifest); err != nil {
+				return nil, fmt.Errorf("%w: unreadable manifest: %v", ErrInvalidBackup, err)
+			}
+			continue
+		}
+
+		if !validBackupEntry(header.Name) {
+			return nil, fmt.Errorf("%w: unexpected file %s", ErrInvalidBackup, header.Name)
+		}
+		if _, dup := found[header.Name]; dup {
+			return nil, fmt.Errorf("%w: %s appears twice", ErrInvalidBackup, header.Name)
+		}
+
+		hash := sha256.New()
+		content := io.TeeReader(archive, hash)
+		if visit != nil {
+			if err := visit(header.Name, content); err != nil {
+				return nil, err
+			}
+		}
+		if _, err := io.Copy(io.Discard, content); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidBackup, err)
+		}
+		found[header.Name] = BackupFile{Path: header.Name, Size: header.Size, SHA256: hex.EncodeToString(hash.Sum(nil))}
+	}
+
+	if manifest == nil {
+		return nil, fmt.Errorf("%w: no manifest", ErrInvalidBackup)
+	}
+	if manifest.Version > backupFormatVersion {
+		return nil, fmt.Errorf("%w: format version %d is newer than this build supports", ErrInvalidBackup, manifest.Version)
+	}
+
+	listed := map[string]bool{}
+	for _, file := range manifest.Files {
+		listed[file.Path] = true
+		got, ok := found[file.Path]
+		switch {
+		case !ok:
+			return nil, fmt.Errorf("%w: %s is missing", ErrInvalidBackup, file.Path)
+		case got.Size != file.Size || got.SHA256 != file.SHA256:
+			return nil, fmt.Errorf("%w: checksum mismatch for %s", ErrInvalidBackup, file.Path)
+		}
+	}
+	for entry := range found {
+		if !listed[entry] {
+			return nil, fmt.Errorf("%w: %s is not in the manifest", ErrInvalidBackup, entry)
+		}
+	}
+	if !listed[backupDatabaseEntry] {
+		return nil, fmt.Errorf("%w: no database", ErrInvalidBackup)
+	}
+
+	return manifest, nil
+}
+
+// validBackupEntry reports whether an archive entry is the database or a
+// file below audio/, so extracting it can't escape the target directories
+func validBackupEntry(entry string) bool {
+	if entry == backupDatabaseEntry {
+		return true
+	}
+	rel, ok := strings.CutPrefix(entry, backupAudioEntry)
+	return ok && rel != "" && path.Clean(entry) == entry && !strings.HasPrefix(rel, "../") && rel != ".."
+}
+
+// RestoreBackup verifies an archive and rehydrates an instance from it: the
+// database replaces the one at dbPath and the audio files are placed under
+// storagePath. The server must not be running. Unless force is set, it
+// refuses to touch an instance that already has a database or audio; with
+// force, the previous database is kept next to it as
+// <db>.before-restore-<timestamp>, and audio files not in the archive stay.
+func RestoreBackup(archivePath, dbPath, storagePath string, force bool) (*BackupManifest, error) {
+	manifest, err := VerifyBackup(archivePath)
+	if err != nil {
+		return nil, err
+	}
+
+	latest, err := database.LatestVersion()
+	if err != nil {
+		return nil, err
+	}
+	if manifest.SchemaVersion > latest {
+		return nil, fmt.Errorf("backup has schema version %d but this build only knows up to %d, restore with a newer build", manifest.SchemaVersion, latest)
+	}
+
+	audioDir := filepath.Join(storagePath, "audio")
+	if !force {
+		if _, err := os.Stat(dbPath); err == nil {
+			return nil, ErrRestoreConflict
+		}
+		if entries, err := os.ReadDir(audioDir); err == nil && len(entries) > 0 {
+			return nil, ErrRestoreConflict
+		}
+	}
+
+	// Extract next to the targets first, so a failure leaves the instance as it was
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
+		return nil, err
+	}
+	stagedDB := dbPath + ".restoring"
+	defer os.Remove(stagedDB)
+	staging := filepath.Join(storagePath, fmt.Sprintf(".restore-%d", time.Now().UnixNano()))
+	if err := os.MkdirAll(staging, 0755); err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(staging)
+
+	// The archive is checked again while extracting, in case it changed since
+	manifest, err = scanBackup(archivePath, func(entry string, r io.Reader) error {
+		target := stagedDB
+		if entry != backupDatabaseEntry {
+			target = filepath.Join(staging, filepath.FromSlash(strings.TrimPrefix(entry, backupAudioEntry)))
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+		}
+		return extractBackupFile(target, r)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if manifest.AudioDir != audioDir {
+		if err := rewriteAudioPaths(stagedDB, manifest.AudioDir, audioDir); err != nil {
+			return nil, fmt.Errorf("failed to update audio paths: %w", err)
+		}
+	}
+
+	// Swap in the database, moving a stale WAL aside with the old file
+	if _, err := os.Stat(dbPath); err == nil {
+		aside := dbPath + ".before-restore-" + time.Now().Format(backupTimeLayout)
+		for _, suffix := range []string{"", "-wal", "-shm"} {
+			if err := os.Rename(dbPath+suffix, aside+suffix); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return nil, err
+			}
+		}
+		log.Printf("💾 Previous database kept at %s", aside)
+	}
+	if err := os.Rename(stagedDB, dbPath); err != nil {
+		return nil, err
+	}
+
+	err = filepath.WalkDir(staging, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(staging, p)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(audioDir, rel)
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		return os.Rename(p, target)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore audio: %w", err)
+	}
+
+	return manifest, nil
+}
+
+func extractBackupFile(target string, r io.Reader) error {
+	f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// rewriteAudioPaths points meetings' audio at the restored audio directory
+// when the archive came from an instance with a different storage path
+func rewriteAudioPaths(dbFile, from, to string) error {
+	db, err := sql.Open("sqlite", dbFile)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	rows, err := db.Query("SELECT id, audio_path FROM meetings WHERE audio_path != ''")
+	if err != nil {
+		return err
+	}
+	paths := map[int]string{}
+	for rows.Next() {
+		var id int
+		var audioPath string
+		if err := rows.Scan(&id, &audioPath); err != nil {
+			rows.Close()
+			return err
+		}
+		if rel, ok := strings.CutPrefix(audioPath, from); ok && (rel == "" || os.IsPathSeparator(rel[0])) {
+			paths[id] = to + rel
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for id, audioPath := range paths {
+		if _, err := tx.Exec("UPDATE meetings SET audio_path = ? WHERE id = ?", audioPath, id); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,445 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// maxCalendarSize bounds downloaded and uploaded calendar files
+	maxCalendarSize = 10 << 20
+
+	calendarFetchTimeout = 30 * time.Second
+)
+
+// ErrCalendarUnavailable is returned when a calendar feed can't be downloaded
+var ErrCalendarUnavailable = errors.New("could not fetch the calendar")
+
+// CalendarImportResult counts what an import did with the events in its window
+type CalendarImportResult struct {
+	Events    int `json:"events"`    // Occurrences within the import window
+	Created   int `json:"created"`   // New scheduled meetings
+	Updated   int `json:"updated"`   // Scheduled meetings whose event changed
+	Cancelled int `json:"cancelled"` // Scheduled meetings moved to the trash
+	Skipped   int `json:"skipped"`   // Events whose meeting was already started or deleted
+}
+
+type CalendarService struct {
+	db            *sql.DB
+	FolderService FolderRepository
+	HorizonDays   int // How far ahead events become scheduled meetings
+	client        *http.Client
+}
+
+func NewCalendarService(db *sql.DB, folderService FolderRepository, horizonDays int) *CalendarService {
+	return &CalendarService{
+		db:            db,
+		FolderService: folderService,
+		HorizonDays:   horizonDays,
+		client:        &http.Client{Timeout: calendarFetchTimeout},
+	}
+}
+
+// Import creates a scheduled meeting for every event occurrence from now
+// until the horizon, in folderID (nil for the top level). Meetings still
+// scheduled follow changes to their event and move to the trash when it is
+// cancelled; started meetings are left alone. source is the feed URL, or
+// empty for an uploaded file. For feeds, scheduled meetings whose event
+// disappeared from the feed are moved to the trash as well.
+func (s *CalendarService) Import(data []byte, source string, folderID *int) (*CalendarImportResult, error) {
+	events, err := ParseCalendar(data)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	until := now.AddDate(0, 0, s.HorizonDays)
+	occurrences, err := ExpandCalendar(events, now, until)
+	if err != nil {
+		return nil, err
+	}
+
+	var defaults FolderDefaults
+	if folderID != nil {
+		if defaults, err = s.FolderService.EffectiveDefaults(*folderID); err != nil {
+			return nil, err
+		}
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result := &CalendarImportResult{Events: len(occurrences)}
+	var changed []int
+	seen := map[string]bool{}
+	for _, occurrence := range occurrences {
+		seen[occurrence.Key] = true
+
+		var id int
+		var status string
+		var trashed bool
+		err := tx.QueryRow(
+			"SELECT id, status, deleted_at IS NOT NULL FROM meetings WHERE calendar_uid = ?", occurrence.Key,
+		).Scan(&id, &status, &trashed)
+		exists := err == nil
+		if err != nil && err != sql.ErrNoRows {
+			return nil, err
+		}
+
+		switch {
+		case exists && (trashed || status != MeetingStatusScheduled):
+			result.Skipped++
+
+		case occurrence.Cancelled:
+			if exists {
+				if _, err := tx.Exec("UPDATE meetings SET deleted_at = ? WHERE id = ?", now.Unix(), id); err != nil {
+					return nil, err
+				}
+				result.Cancelled++
+			}
+
+		case exists:
+			updated, err := updateScheduledMeeting(tx, id, occurrence)
+			if err != nil {
+				return nil, err
+			}
+			if updated {
+				result.Updated++
+				changed = append(changed, id)
+			}
+
+		default:
+			id, err := createScheduledMeeting(tx, occurrence, source, folderID, defaults)
+			if err != nil {
+				return nil, err
+			}
+			result.Created++
+			changed = append(changed, id)
+		}
+	}
+
+	if source != "" {
+		removed, err := trashVanishedEvents(tx, source, seen, now, until)
+		if err != nil {
+			return nil, err
+		}
+		result.Cancelled += removed
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	for _, id := range changed {
+		if err := indexMeeting(s.db, id); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// Sync downloads the ICS feed at url and imports it
+func (s *CalendarService) Sync(url string, folderID *int) (*CalendarImportResult, error) {
+	data, err := s.Fetch(url)
+	if err != nil {
+		return nil, err
+	}
+	return s.Import(data, url, folderID)
+}
+
+// Fetch downloads an ICS feed. webcal:// links are fetched over HTTPS.
+func (s *CalendarService) Fetch(url string) ([]byte, error) {
+	if rest, ok := strings.CutPrefix(url, "webcal://"); ok {
+		url = "https://" + rest
+	}
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return nil, fmt.Errorf("%w: use an http, https or webcal URL", ErrCalendarUnavailable)
+	}
+
+	resp, err := s.client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCalendarUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s returned %s", ErrCalendarUnavailable, url, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxCalendarSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCalendarUnavailable, err)
+	}
+	if len(data) > maxCalendarSize {
+		return nil, fmt.Errorf("%w: the calendar is larger than %d MB", ErrCalendarUnavailable, maxCalendarSize>>20)
+	}
+	return data, nil
+}
+
+// StartFeedPoller syncs the feed at url every interval, keeping scheduled
+// meetings in step with the calendar
+func (s *CalendarService) StartFeedPoller(url string, interval time.Duration) {
+	go func() {
+		for {
+			result, err := s.Sync(url, nil)
+			if err != nil {
+				log.Printf("⚠️  Calendar sync failed: %v", err)
+			} else if result.Created+result.Updated+result.Cancelled > 0 {
+				log.Printf("📅 Calendar synced: %d created, %d updated, %d cancelled", result.Created, result.Updated, result.Cancelled)
+			}
+
+			time.Sleep(interval)
+		}
+	}()
+}
+
+// createScheduledMeeting inserts a scheduled meeting for an occurrence. Its
+// created_at is the scheduled start until the meeting is started.
+func createScheduledMeeting(tx *sql.Tx, occurrence CalendarOccurrence, source string, folderID *int, defaults FolderDefaults) (int, error) {
+	title := calendarTitle(occurrence)
+	var id int
+	err := tx.QueryRow(`
+		INSERT INTO meetings (title, description, is_recording, status, auto_title, created_at, scheduled_start, scheduled_end,
+			calendar_uid, calendar_source, folder_id, glossary, prompt_template, retention_days, workspace)
+		VALUES (?, ?, FALSE, 'scheduled', ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		RETURNING id
+	`, title, strings.TrimSpace(occurrence.Description), title == DefaultMeetingTitle,
+		occurrence.Start.UTC().Format("2006-01-02 15:04:05"), occurrence.Start.Unix(), occurrence.End.Unix(),
+		occurrence.Key, source, folderID, defaults.Glossary, defaults.PromptTemplate, defaults.RetentionDays, defaults.workspace(),
+	).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := setCalendarParticipants(tx, id, occurrence); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// updateScheduledMeeting applies an event's title, description, times and
+// attendees to its scheduled meeting, reporting whether anything changed
+func updateScheduledMeeting(tx *sql.Tx, id int, occurrence CalendarOccurrence) (bool, error) {
+	title := calendarTitle(occurrence)
+	description := strings.TrimSpace(occurrence.Description)
+	result, err := tx.Exec(`
+		UPDATE meetings SET title = ?, description = ?, created_at = ?, scheduled_start = ?, scheduled_end = ?,
+			updated_at = CURRENT_TIMESTAMP, version = version + 1
+		WHERE id = ? AND NOT (title = ? AND description = ? AND scheduled_start IS NOT DISTINCT FROM ? AND scheduled_end IS NOT DISTINCT FROM ?)
+	`, title, description, occurrence.Start.UTC().Format("2006-01-02 15:04:05"), occurrence.Start.Unix(), occurrence.End.Unix(),
+		id, title, description, occurrence.Start.Unix(), occurrence.End.Unix())
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	participantsChanged, err := setCalendarParticipants(tx, id, occurrence)
+	if err != nil {
+		return false, err
+	}
+	if participantsChanged && affected == 0 {
+		if err := bumpVersion(tx, id); err != nil {
+			return false, err
+		}
+	}
+	return affected > 0 || participantsChanged, nil
+}
+
+// trashVanishedEvents moves scheduled meetings from source that are due in
+// [from, to) but no longer in the feed to the trash
+func trashVanishedEvents(tx *sql.Tx, source string, seen map[string]bool, from, to time.Time) (int, error) {
+	rows, err := tx.Query(`
+		SELECT id, calendar_uid FROM meetings
+		WHERE calendar_source = ? AND status = 'scheduled' AND deleted_at IS NULL
+			AND scheduled_start >= ? AND scheduled_start < ?
+	`, source, from.Unix(), to.Unix())
+	if err != nil {
+		return 0, err
+	}
+	var vanished []interface{}
+	for rows.Next() {
+		var id int
+		var uid string
+		if err := rows.Scan(&id, &uid); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		if !seen[uid] {
+			vanished = append(vanished, id)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil || len(vanished) == 0 {
+		return 0, err
+	}
+
+	_, err = tx.Exec(
+		"UPDATE meetings SET deleted_at = ? WHERE id IN ("+placeholders(len(vanished))+")",
+		append([]interface{}{time.Now().Unix()}, vanished...)...,
+	)
+	return len(vanished), err
+}
+
+// setCalendarParticipants makes an event's organizer (as host) and attendees
+// a scheduled meeting's participants, reporting whether the list changed.
+// Attendees are linked to the person with their email, who is added to the
+// people directory if needed; attendees from another email domain than the
+// organizer's are external.
+func setCalendarParticipants(tx *sql.Tx, meetingID int, occurrence CalendarOccurrence) (bool, error) {
+	type entry struct{ name, email, role string }
+
+	var entries []entry
+	listed := map[string]bool{}
+	addEntry := func(a CalendarAttendee, role string) {
+		key := strings.ToLower(a.Email)
+		if key == "" {
+			key = strings.ToLower(a.Name)
+		}
+		if key == "" || listed[key] {
+			return
+		}
+		listed[key] = true
+		entries = append(entries, entry{name: truncateName(a.Name), email: a.Email, role: role})
+	}
+
+	organizerDomain := ""
+	if occurrence.Organizer != nil {
+		addEntry(*occurrence.Organizer, ParticipantRoleHost)
+		organizerDomain = emailDomain(occurrence.Organizer.Email)
+	}
+	for _, a := range occurrence.Attendees {
+		role := ParticipantRoleAttendee
+		if domain := emailDomain(a.Email); organizerDomain != "" && domain != "" && domain != organizerDomain {
+			role = ParticipantRoleExternal
+		}
+		addEntry(a, role)
+	}
+	if len(entries) > maxParticipants {
+		entries = entries[:maxParticipants]
+	}
+
+	rows, err := tx.Query("SELECT name, role FROM meeting_participants WHERE meeting_id = ? ORDER BY position, id", meetingID)
+	if err != nil {
+		return false, err
+	}
+	var existing []entry
+	for rows.Next() {
+		var e entry
+		if err := rows.Scan(&e.name, &e.role); err != nil {
+			rows.Close()
+			return false, err
+		}
+		existing = append(existing, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return false, err
+	}
+
+	var personIDs []*int
+	for i, e := range entries {
+		personID, name, err := calendarPerson(tx, e.name, e.email)
+		if err != nil {
+			return false, err
+		}
+		entries[i].name = name
+		personIDs = append(personIDs, personID)
+	}
+
+	changed := len(existing) != len(entries)
+	for i := 0; !changed && i < len(entries); i++ {
+		changed = existing[i].name != entries[i].name || existing[i].role != entries[i].role
+	}
+	if !changed {
+		return false, nil
+	}
+
+	if _, err := tx.Exec("DELETE FROM meeting_participants WHERE meeting_id = ?", meetingID); err != nil {
+		return false, err
+	}
+	for i, e := range entries {
+		if _, err := tx.Exec(
+			"INSERT INTO meeting_participants (meeting_id, person_id, name, role, position) VALUES (?, ?, ?, ?, ?)",
+			meetingID, personIDs[i], e.name, e.role, i,
+		); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// calendarPerson finds the person an attendee is, by email or else by name,
+// adding attendees with an email to the people directory. It returns the
+// person's ID, if any, and the name to list them under.
+func calendarPerson(tx *sql.Tx, name, email string) (*int, string, error) {
+	if email != "" {
+		var id int
+		var personName string
+		err := tx.QueryRow("SELECT id, name FROM people WHERE LOWER(email) = LOWER(?)", email).Scan(&id, &personName)
+		if err == nil {
+			return &id, personName, nil
+		}
+		if err != sql.ErrNoRows {
+			return nil, "", err
+		}
+	}
+
+	personID, err := findPersonID(tx, name)
+	if err != nil {
+		return nil, "", err
+	}
+	if personID != nil {
+		var personName string
+		if err := tx.QueryRow("SELECT name FROM people WHERE id = ?", *personID).Scan(&personName); err != nil {
+			return nil, "", err
+		}
+		return personID, personName, nil
+	}
+
+	if email == "" {
+		return nil, name, nil
+	}
+	var created int
+	err = tx.QueryRow("INSERT INTO people (name, email, created_at) VALUES (?, ?, ?) RETURNING id", name, email, time.Now().Unix()).Scan(&created)
+	if err != nil {
+		return nil, "", personError(err)
+	}
+	return &created, name, nil
+}
+
+func calendarTitle(occurrence CalendarOccurrence) string {
+	title := strings.TrimSpace(occurrence.Summary)
+	if title == "" {
+		return DefaultMeetingTitle
+	}
+	if runes := []rune(title); len(runes) > maxTitleLength {
+		title = string(runes[:maxTitleLength])
+	}
+	return title
+}
+
+func truncateName(name string) string {
+	if runes := []rune(strings.TrimSpace(name)); len(runes) > maxParticipantLength {
+		return string(runes[:maxParticipantLength])
+	}
+	return strings.TrimSpace(name)
+}
+
+func emailDomain(email string) string {
+	if _, domain, ok := strings.Cut(email, "@"); ok {
+		return strings.ToLower(domain)
+	}
+	return ""
+}
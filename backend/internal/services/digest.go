@@ -0,0 +1,302 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+type DigestMeeting struct {
+	ID        int       `json:"id"`
+	Title     string    `json:"title"`
+	CreatedAt time.Time `json:"created_at"`
+	Summary   string    `json:"summary"`
+}
+
+type Digest struct {
+	ID        int             `json:"id"`
+	Workspace string          `json:"workspace"`
+	From      string          `json:"from"`
+	To        string          `json:"to"`
+	Meetings  []DigestMeeting `json:"meetings"`
+	Content   DigestContent   `json:"content"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// digestDateFormat is the format of a digest's from/to dates; both are inclusive
+const digestDateFormat = "2006-01-02"
+
+type DigestService struct {
+	db             *sql.DB
+	MeetingService MeetingRepository
+	TaskService    TaskRepository
+	GeminiService  *GeminiService
+	Cache          *AICacheService
+}
+
+func NewDigestService(db *sql.DB, meetingService MeetingRepository, taskService TaskRepository, gemini *GeminiService, cache *AICacheService) *DigestService {
+	return &DigestService{
+		db:             db,
+		MeetingService: meetingService,
+		TaskService:    taskService,
+		GeminiService:  gemini,
+		Cache:          cache,
+	}
+}
+
+// Generate builds a digest for the workspace's meetings created between from
+// and to (inclusive dates) and stores it. Every AI call follows the
+// workspace's redaction policy.
+func (s *DigestService) Generate(workspace string, from, to time.Time) (*Digest, error) {
+	meetings, err := s.MeetingService.GetInRange(from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	digest := &Digest{
+		Workspace: workspace,
+		From:      from.Format(digestDateFormat),
+		To:        to.Format(digestDateFormat),
+		Meetings:  []DigestMeeting{},
+	}
+
+	var summaries []string
+	var ids []int
+	for _, m := range meetings {
+		if m.Workspace != workspace {
+			continue
+		}
+		if strings.TrimSpace(m.Notes) == "" && strings.TrimSpace(m.Transcript) == "" {
+			continue
+		}
+
+		summary, err := s.summarize(&m)
+		if err != nil {
+			return nil, fmt.Errorf("failed to summarize meeting %d: %w", m.ID, err)
+		}
+
+		digest.Meetings = append(digest.Meetings, DigestMeeting{ID: m.ID, Title: m.Title, CreatedAt: m.CreatedAt, Summary: summary})
+		summaries = append(summaries, fmt.Sprintf("## %s (%s)\n%s", m.Title, m.CreatedAt.Format(digestDateFormat), summary))
+		ids = append(ids, m.ID)
+	}
+
+	if len(summaries) > 0 {
+		openTasks, err := s.openTasks(ids)
+		if err != nil {
+			return nil, err
+		}
+
+		content, err := s.GeminiService.ForWorkspace(workspace).SynthesizeDigest(summaries, openTasks)
+		if err != nil {
+			return nil, err
+		}
+		digest.Content = *content
+	} else {
+		digest.Content.Overview = "No meetings with notes or transcripts in this period."
+	}
+
+	if err := s.save(digest); err != nil {
+		return nil, err
+	}
+
+	return digest, nil
+}
+
+// GetForRange returns the most recent stored digest of the workspace for
+// exactly this range, or nil
+func (s *DigestService) GetForRange(workspace string, from, to time.Time) (*Digest, error) {
+	row := s.db.QueryRow(
+		"SELECT "+digestColumns+" FROM digests WHERE workspace = ? AND period_from = ? AND period_to = ? ORDER BY id DESC LIMIT 1",
+		workspace, from.Format(digestDateFormat), to.Format(digestDateFormat),
+	)
+
+	d, err := scanDigest(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return d, err
+}
+
+// GetByID retrieves a stored digest
+func (s *DigestService) GetByID(id int) (*Digest, error) {
+	row := s.db.QueryRow("SELECT "+digestColumns+" FROM digests WHERE id = ?", id)
+
+	d, err := scanDigest(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return d, err
+}
+
+// GetAll lists the workspace's stored digests, newest period first
+func (s *DigestService) GetAll(workspace string) ([]Digest, error) {
+	rows, err := s.db.Query("SELECT "+digestColumns+" FROM digests WHERE workspace = ? ORDER BY period_to DESC, id DESC", workspace)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var digests []Digest
+	for rows.Next() {
+		d, err := scanDigest(rows)
+		if err != nil {
+			return nil, err
+		}
+		digests = append(digests, *d)
+	}
+
+	return digests, rows.Err()
+}
+
+// StartWeeklySchedule generates a digest of the previous 7 days every week
+// on the given weekday and hour (server local time), one per workspace with
+// meetings in that week
+func (s *DigestService) StartWeeklySchedule(weekday time.Weekday, hour int) {
+	go func() {
+		for {
+			next := nextWeeklyRun(time.Now(), weekday, hour)
+			time.Sleep(time.Until(next))
+
+			to, from := weeklyDigestRange(next)
+			workspaces, err := s.workspaces(from, to)
+			if err != nil {
+				log.Printf("⚠️  Weekly digest failed: %v", err)
+				continue
+			}
+			for _, workspace := range workspaces {
+				if _, err := s.Generate(workspace, from, to); err != nil {
+					log.Printf("⚠️  Weekly digest of %s failed: %v", workspace, err)
+					continue
+				}
+				log.Printf("📰 Weekly digest of %s generated for %s to %s", workspace, from.Format(digestDateFormat), to.Format(digestDateFormat))
+			}
+		}
+	}()
+}
+
+// workspaces lists the workspaces of meetings created between from and to
+// (inclusive dates)
+func (s *DigestService) workspaces(from, to time.Time) ([]string, error) {
+	meetings, err := s.MeetingService.GetInRange(from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	var workspaces []string
+	for _, m := range meetings {
+		if !contains(workspaces, m.Workspace) {
+			workspaces = append(workspaces, m.Workspace)
+		}
+	}
+	return workspaces, nil
+}
+
+// summarize returns a meeting summary, reusing the AI cache so regenerating a
+// digest only pays for meetings that changed
+func (s *DigestService) summarize(m *Meeting) (string, error) {
+	settings, _ := s.GeminiService.ActionSettings("summarize")
+	key := s.Cache.Key("summarize", settings, m.Title+"\x00"+m.Notes+"\x00"+m.Transcript)
+
+	if cached, err := s.Cache.Get(key); err == nil && cached != nil {
+		var summary string
+		if err := json.Unmarshal(cached, &summary); err == nil {
+			return summary, nil
+		}
+	}
+
+	summary, err := s.GeminiService.ForWorkspace(m.Workspace).Summarize(m.Title, m.Notes, m.Transcript)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.Cache.Set(key, "summarize", summary); err != nil {
+		log.Printf("AI Cache Error: %v", err)
+	}
+	return summary, nil
+}
+
+// openTasks lists the tasks of the meetings that aren't completed yet,
+// with who should do them and by when
+func (s *DigestService) openTasks(meetingIDs []int) ([]string, error) {
+	tasks, err := s.TaskService.GetOpen(meetingIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	for _, t := range tasks {
+		line := "- " + t.Content
+		if details := taskDetails(t.Assignee, t.DueDate); details != "" {
+			line += " (" + details + ")"
+		}
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
+
+func (s *DigestService) save(d *Digest) error {
+	content, err := json.Marshal(struct {
+		Meetings []DigestMeeting `json:"meetings"`
+		Content  DigestContent   `json:"content"`
+	}{d.Meetings, d.Content})
+	if err != nil {
+		return err
+	}
+
+	d.CreatedAt = time.Now().UTC().Truncate(time.Second)
+	return s.db.QueryRow(
+		"INSERT INTO digests (workspace, period_from, period_to, content, created_at) VALUES (?, ?, ?, ?, ?) RETURNING id",
+		d.Workspace, d.From, d.To, string(content), d.CreatedAt.Unix(),
+	).Scan(&d.ID)
+}
+
+const digestColumns = "id, workspace, period_from, period_to, content, created_at"
+
+func scanDigest(row rowScanner) (*Digest, error) {
+	var d Digest
+	var content string
+	var createdAt int64
+	if err := row.Scan(&d.ID, &d.Workspace, &d.From, &d.To, &content, &createdAt); err != nil {
+		return nil, err
+	}
+
+	var stored struct {
+		Meetings []DigestMeeting `json:"meetings"`
+		Content  DigestContent   `json:"content"`
+	}
+	if err := json.Unmarshal([]byte(content), &stored); err != nil {
+		return nil, fmt.Errorf("corrupt digest %d: %w", d.ID, err)
+	}
+
+	d.Meetings = stored.Meetings
+	d.Content = stored.Content
+	d.CreatedAt = time.Unix(createdAt, 0).UTC()
+	return &d, nil
+}
+
+// ParseDigestDate parses a from/to query value
+func ParseDigestDate(value string) (time.Time, error) {
+	return time.ParseInLocation(digestDateFormat, value, time.Local)
+}
+
+// weeklyDigestRange returns the last day and first day of the 7 days before
+// a run. Dates start at local midnight so Generate covers whole days, not
+// from the run hour onwards.
+func weeklyDigestRange(run time.Time) (to, from time.Time) {
+	day := time.Date(run.Year(), run.Month(), run.Day(), 0, 0, 0, 0, run.Location())
+	to = day.AddDate(0, 0, -1)
+	return to, to.AddDate(0, 0, -6)
+}
+
+func nextWeeklyRun(now time.Time, weekday time.Weekday, hour int) time.Time {
+	next := time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, now.Location())
+	days := (int(weekday) - int(now.Weekday()) + 7) % 7
+	next = next.AddDate(0, 0, days)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 7)
+	}
+	return next
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+type EmailService struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+func NewEmailService(host, port, username, password, from string) *EmailService {
+	return &EmailService{
+		Host:     host,
+		Port:     port,
+		Username: username,
+		Password: password,
+		From:     from,
+	}
+}
+
+// CanSend reports whether an SMTP server is configured
+func (s *EmailService) CanSend() bool {
+	return s.Host != "" && s.From != ""
+}
+
+// RenderText renders a follow-up email as a plain text body
+func (s *EmailService) RenderText(email *FollowUpEmail) string {
+	var b strings.Builder
+
+	if email.Greeting != "" {
+		b.WriteString(email.Greeting + "\n\n")
+	}
+	if email.Summary != "" {
+		b.WriteString(email.Summary + "\n\n")
+	}
+
+	if len(email.Decisions) > 0 {
+		b.WriteString("Decisions:\n")
+		for _, d := range email.Decisions {
+			b.WriteString("- " + d + "\n")
+		}
+		b.WriteString("\n")
+	}
+
+	if len(email.ActionItems) > 0 {
+		b.WriteString("Action items:\n")
+		for _, group := range email.ActionItems {
+			b.WriteString(group.Owner + ":\n")
+			for _, item := range group.Items {
+				b.WriteString("- " + item + "\n")
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	if email.Closing != "" {
+		b.WriteString(email.Closing + "\n")
+	}
+
+	return b.String()
+}
+
+// BuildEML renders a follow-up email as an RFC 5322 message
+func (s *EmailService) BuildEML(email *FollowUpEmail, to []string, date time.Time) ([]byte, error) {
+	recipients, err := parseAddresses(to)
+	if err != nil {
+		return nil, err
+	}
+
+	from := s.From
+	if from == "" {
+		from = "echo@localhost"
+	}
+	sender, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender address %q: %w", from, err)
+	}
+
+	var body bytes.Buffer
+	qp := quotedprintable.NewWriter(&body)
+	if _, err := qp.Write([]byte(strings.ReplaceAll(s.RenderText(email), "\n", "\r\n"))); err != nil {
+		return nil, err
+	}
+	qp.Close()
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", sender.String())
+	if len(recipients) > 0 {
+		fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(recipients, ", "))
+	}
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", email.Subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "Message-ID: %s\r\n", messageID(sender.Address))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	msg.WriteString("Content-Transfer-Encoding: quoted-printable\r\n")
+	msg.WriteString("\r\n")
+	msg.Write(body.Bytes())
+
+	return msg.Bytes(), nil
+}
+
+// Send delivers a rendered message through the configured SMTP server
+func (s *EmailService) Send(to []string, message []byte) error {
+	if !s.CanSend() {
+		return fmt.Errorf("SMTP is not configured")
+	}
+	if len(to) == 0 {
+		return fmt.Errorf("no recipients")
+	}
+
+	sender, err := mail.ParseAddress(s.From)
+	if err != nil {
+		return fmt.Errorf("invalid sender address %q: %w", s.From, err)
+	}
+
+	var addresses []string
+	for _, r := range to {
+		addr, err := mail.ParseAddress(r)
+		if err != nil {
+			return fmt.Errorf("invalid recipient %q: %w", r, err)
+		}
+		addresses = append(addresses, addr.Address)
+	}
+
+	var auth smtp.Auth
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, s.Password, s.Host)
+	}
+
+	return smtp.SendMail(net.JoinHostPort(s.Host, s.Port), auth, sender.Address, addresses, message)
+}
+
+func parseAddresses(list []string) ([]string, error) {
+	var out []string
+	for _, entry := range list {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		addr, err := mail.ParseAddress(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recipient %q: %w", entry, err)
+		}
+		out = append(out, addr.String())
+	}
+	return out, nil
+}
+
+func messageID(from string) string {
+	domain := "localhost"
+	if at := strings.LastIndex(from, "@"); at != -1 {
+		domain = from[at+1:]
+	}
+
+	buf := make([]byte, 12)
+	rand.Read(buf)
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(buf), domain)
+}
@@ -0,0 +1,550 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+// Export formats. ExportBundle is a zip of the Markdown, HTML and JSON
+// exports together with the merged audio.
+const (
+	ExportMarkdown = "md"
+	ExportHTML     = "html"
+	ExportPDF      = "pdf"
+	ExportDOCX     = "docx"
+	ExportJSON     = "json"
+	ExportBundle   = "zip"
+)
+
+// ExportFormats are the values accepted for the export format
+var ExportFormats = []string{ExportMarkdown, ExportHTML, ExportPDF, ExportDOCX, ExportJSON, ExportBundle}
+
+// exportContentTypes maps formats to the Content-Type of their files
+var exportContentTypes = map[string]string{
+	ExportMarkdown: "text/markdown; charset=utf-8",
+	ExportHTML:     "text/html; charset=utf-8",
+	ExportPDF:      "application/pdf",
+	ExportDOCX:     "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	ExportJSON:     "application/json",
+	ExportBundle:   "application/zip",
+}
+
+//go:embed export_templates/*.tmpl
+var exportTemplates embed.FS
+
+var (
+	// notesTag matches a tag of the editor's HTML, capturing the closing slash, name and attributes
+	notesTag     = regexp.MustCompile(`<(/?)([a-zA-Z][a-zA-Z0-9]*)([^>]*)>`)
+	notesAttr    = regexp.MustCompile(`([a-zA-Z-]+)\s*=\s*"([^"]*)"`)
+	notesScripts = regexp.MustCompile(`(?is)<script\b.*?</script\s*>|<style\b.*?</style\s*>`)
+	blankLines   = regexp.MustCompile(`\n{3,}`)
+	nonSlugChars = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// safeNotesTags are the tags kept when notes are embedded in HTML exports
+var safeNotesTags = map[string]bool{
+	"p": true, "br": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"ul": true, "ol": true, "li": true, "strong": true, "b": true, "em": true, "i": true, "u": true,
+	"s": true, "code": true, "pre": true, "blockquote": true, "hr": true, "a": true, "mark": true,
+}
+
+// MeetingExport is everything an export contains. The export templates are
+// executed with it, and it is the document of JSON exports.
+type MeetingExport struct {
+	ID              int             `json:"id"`
+	Title           string          `json:"title"`
+	Date            time.Time       `json:"date"`
+	DurationSeconds int             `json:"duration_seconds"`
+	MeetingType     string          `json:"meeting_type"`
+	Tags            []string        `json:"tags"`
+	Folder          string          `json:"folder"`
+	Language        string          `json:"language"`
+	Summary         string          `json:"summary"`
+	Participants    []Participant   `json:"participants"`
+	Notes           string          `json:"notes"` // As edited, in the editor's HTML
+	NotesMarkdown   string          `json:"notes_markdown"`
+	Tasks           []ExportTask    `json:"tasks"`
+	Transcript      []ExportSegment `json:"transcript"`      // Timestamped, when segments were stored
+	TranscriptText  string          `json:"transcript_text"` // The full transcript as text
+	ExportedAt      time.Time       `json:"exported_at"`
+
+	audioPath string
+}
+
+type ExportTask struct {
+	Content   string `json:"content"`
+	Assignee  string `json:"assignee"`
+	DueDate   string `json:"due_date"`
+	Priority  string `json:"priority"`
+	Completed bool   `json:"completed"`
+}
+
+// Details describes who should do the task and by when, for templates
+func (t ExportTask) Details() string {
+	return taskDetails(t.Assignee, t.DueDate)
+}
+
+type ExportSegment struct {
+	StartMs int64  `json:"start_ms"`
+	EndMs   int64  `json:"end_ms"`
+	Speaker string `json:"speaker"`
+	Text    string `json:"text"`
+}
+
+// Timestamp formats the segment's start as MM:SS, or H:MM:SS past an hour
+func (s ExportSegment) Timestamp() string {
+	return formatOffset(time.Duration(s.StartMs) * time.Millisecond)
+}
+
+// Duration formats the meeting's length, e.g. 1h 05m or 12m 30s
+func (e *MeetingExport) Duration() string {
+	d := time.Duration(e.DurationSeconds) * time.Second
+	if d >= time.Hour {
+		return fmt.Sprintf("%dh %02dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return fmt.Sprintf("%dm %02ds", int(d.Minutes()), int(d.Seconds())%60)
+}
+
+// NotesHTML returns the notes reduced to plain formatting tags, safe to
+// embed in the HTML export
+func (e *MeetingExport) NotesHTML() htmltemplate.HTML {
+	return htmltemplate.HTML(sanitizeNotes(e.Notes))
+}
+
+type ExportService struct {
+	MeetingService MeetingRepository
+	SegmentService SegmentRepository
+	TaskService    TaskRepository
+	PeopleService  *PeopleService
+	FolderService  FolderRepository
+	TemplateDir    string // Optional directory with meeting.md.tmpl and meeting.html.tmpl overriding the built-in templates
+}
+
+func NewExportService(meetingService MeetingRepository, segmentService SegmentRepository, taskService TaskRepository, peopleService *PeopleService, folderService FolderRepository, templateDir string) *ExportService {
+	return &ExportService{
+		MeetingService: meetingService,
+		SegmentService: segmentService,
+		TaskService:    taskService,
+		PeopleService:  peopleService,
+		FolderService:  folderService,
+		TemplateDir:    templateDir,
+	}
+}
+
+// ValidExportFormat reports whether format is one of ExportFormats
+func ValidExportFormat(format string) bool {
+	return contains(ExportFormats, format)
+}
+
+// Build gathers a meeting's export, or returns nil if there is no such meeting
+func (s *ExportService) Build(id int) (*MeetingExport, error) {
+	meeting, err := s.MeetingService.GetByID(id)
+	if err != nil || meeting == nil {
+		return nil, err
+	}
+
+	export := &MeetingExport{
+		ID:              meeting.ID,
+		Title:           meeting.Title,
+		Date:            meeting.CreatedAt,
+		DurationSeconds: meeting.DurationSeconds,
+		MeetingType:     meeting.MeetingType,
+		Tags:            meeting.Tags,
+		Language:        meeting.Language,
+		Summary:         strings.TrimSpace(meeting.Description),
+		Notes:           meeting.Notes,
+		NotesMarkdown:   notesMarkdown(meeting.Notes),
+		Tasks:           []ExportTask{},
+		Transcript:      []ExportSegment{},
+		TranscriptText:  strings.TrimSpace(meeting.Transcript),
+		ExportedAt:      time.Now(),
+		audioPath:       meeting.AudioPath,
+	}
+
+	if meeting.FolderID != nil {
+		folder, err := s.FolderService.GetByID(*meeting.FolderID)
+		if err != nil {
+			return nil, err
+		}
+		if folder != nil {
+			export.Folder = folder.Name
+		}
+	}
+
+	if export.Participants, err = s.PeopleService.GetParticipants(id); err != nil {
+		return nil, err
+	}
+
+	tasks, err := s.TaskService.GetForMeeting(id)
+	if err != nil {
+		return nil, err
+	}
+	export.Tasks = []ExportTask{}
+	for _, t := range tasks {
+		export.Tasks = append(export.Tasks, ExportTask{
+			Content:   t.Content,
+			Assignee:  t.Assignee,
+			DueDate:   t.DueDate,
+			Priority:  t.Priority,
+			Completed: t.Completed,
+		})
+	}
+
+	segments, err := s.SegmentService.GetByMeeting(id)
+	if err != nil {
+		return nil, err
+	}
+	for _, seg := range segments {
+		export.Transcript = append(export.Transcript, ExportSegment{StartMs: seg.StartMs, EndMs: seg.EndMs, Speaker: seg.Speaker, Text: seg.Text})
+	}
+
+	return export, nil
+}
+
+// Render produces a single-file export in format, which must not be ExportBundle
+func (s *ExportService) Render(export *MeetingExport, format string) ([]byte, error) {
+	switch format {
+	case ExportMarkdown:
+		return s.renderMarkdown(export)
+	case ExportHTML:
+		return s.renderHTML(export)
+	case ExportJSON:
+		return json.MarshalIndent(export, "", "  ")
+	case ExportPDF:
+		md, err := s.renderMarkdown(export)
+		if err != nil {
+			return nil, err
+		}
+		return renderPDF(export.Title, markdownBlocks(string(md))), nil
+	case ExportDOCX:
+		md, err := s.renderMarkdown(export)
+		if err != nil {
+			return nil, err
+		}
+		return renderDOCX(export.Title, markdownBlocks(string(md)))
+	}
+	return nil, fmt.Errorf("unknown export format %q", format)
+}
+
+// WriteBundle writes a zip of the Markdown, HTML and JSON exports and the
+// meeting's merged audio, if it has any
+func (s *ExportService) WriteBundle(w io.Writer, export *MeetingExport) error {
+	archive := zip.NewWriter(w)
+
+	base := ExportFilename(export, "")
+	for _, format := range []string{ExportMarkdown, ExportHTML, ExportJSON} {
+		data, err := s.Render(export, format)
+		if err != nil {
+			return err
+		}
+		f, err := archive.Create(base + "." + format)
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(data); err != nil {
+			return err
+		}
+	}
+
+	if export.audioPath != "" {
+		if err := addFileToZip(archive, export.audioPath, "audio/"+filepath.Base(export.audioPath)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+// ExportContentType returns the Content-Type of an export format
+func ExportContentType(format string) string {
+	return exportContentTypes[format]
+}
+
+// ExportFilename names an export after the meeting's date and title, e.g.
+// 2026-03-02-weekly-sync.md. An empty format gives the name without extension.
+func ExportFilename(export *MeetingExport, format string) string {
+	slug := strings.Trim(nonSlugChars.ReplaceAllString(strings.ToLower(export.Title), "-"), "-")
+	if len(slug) > 60 {
+		slug = strings.TrimRight(slug[:60], "-")
+	}
+	if slug == "" {
+		slug = fmt.Sprintf("meeting-%d", export.ID)
+	}
+
+	name := export.Date.Local().Format("2006-01-02") + "-" + slug
+	if format != "" {
+		name += "." + format
+	}
+	return name
+}
+
+func (s *ExportService) renderMarkdown(export *MeetingExport) ([]byte, error) {
+	source, err := s.template("meeting.md.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := texttemplate.New("meeting.md.tmpl").Funcs(exportFuncs).Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("markdown export template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, export); err != nil {
+		return nil, fmt.Errorf("markdown export template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (s *ExportService) renderHTML(export *MeetingExport) ([]byte, error) {
+	source, err := s.template("meeting.html.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := htmltemplate.New("meeting.html.tmpl").Funcs(exportFuncs).Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("HTML export template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, export); err != nil {
+		return nil, fmt.Errorf("HTML export template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// template reads a template from TemplateDir, falling back to the built-in
+// one. Templates are read on every export, so edits apply without a restart.
+func (s *ExportService) template(name string) (string, error) {
+	if s.TemplateDir != "" {
+		data, err := os.ReadFile(filepath.Join(s.TemplateDir, name))
+		if err == nil {
+			return string(data), nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+	}
+
+	data, err := exportTemplates.ReadFile("export_templates/" + name)
+	return string(data), err
+}
+
+// exportFuncs are the functions available to export templates
+var exportFuncs = map[string]interface{}{
+	"date": func(t time.Time) string { return t.Local().Format("Monday, January 2, 2006 15:04") },
+	"join": strings.Join,
+}
+
+func addFileToZip(archive *zip.Writer, path, name string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// Audio is already compressed
+	w, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store, Modified: time.Now()})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, f)
+	return err
+}
+
+func formatOffset(d time.Duration) string {
+	seconds := int(d.Seconds())
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
+	return fmt.Sprintf("%02d:%02d", seconds/60, seconds%60)
+}
+
+// sanitizeNotes keeps the formatting tags of the editor's HTML and drops
+// every other tag and all attributes except link targets
+func sanitizeNotes(notes string) string {
+	return notesTag.ReplaceAllStringFunc(notesScripts.ReplaceAllString(notes, ""), func(tag string) string {
+		m := notesTag.FindStringSubmatch(tag)
+		closing, name := m[1], strings.ToLower(m[2])
+		if !safeNotesTags[name] {
+			return ""
+		}
+		if name == "a" && closing == "" {
+			href := notesAttrValue(m[3], "href")
+			if strings.HasPrefix(href, "http://") || strings.HasPrefix(href, "https://") || strings.HasPrefix(href, "mailto:") {
+				return `<a href="` + html.EscapeString(html.UnescapeString(href)) + `">`
+			}
+		}
+		return "<" + closing + name + ">"
+	})
+}
+
+func notesAttrValue(attrs, name string) string {
+	for _, m := range notesAttr.FindAllStringSubmatch(attrs, -1) {
+		if strings.EqualFold(m[1], name) {
+			return m[2]
+		}
+	}
+	return ""
+}
+
+// notesMarkdown converts the editor's HTML to Markdown: headings, lists
+// (including task lists), emphasis, code, quotes and links
+func notesMarkdown(notes string) string {
+	notes = notesScripts.ReplaceAllString(notes, "")
+
+	var out strings.Builder
+	type list struct {
+		ordered bool
+		index   int
+	}
+	var lists []list
+	var quotes []*strings.Builder
+	var links []string
+	inPre := false
+
+	w := func() *strings.Builder {
+		if len(quotes) > 0 {
+			return quotes[len(quotes)-1]
+		}
+		return &out
+	}
+	newline := func() {
+		if s := w().String(); s != "" && !strings.HasSuffix(s, "\n") {
+			w().WriteString("\n")
+		}
+	}
+	blockEnd := func() {
+		if len(lists) == 0 {
+			w().WriteString("\n\n")
+		}
+	}
+
+	last := 0
+	for _, loc := range notesTag.FindAllStringSubmatchIndex(notes, -1) {
+		text := notes[last:loc[0]]
+		last = loc[1]
+		if inPre {
+			w().WriteString(html.UnescapeString(text))
+		} else if text = whitespace.ReplaceAllString(html.UnescapeString(text), " "); strings.TrimSpace(text) != "" {
+			w().WriteString(text)
+		}
+
+		closing := notes[loc[2]:loc[3]] == "/"
+		name := strings.ToLower(notes[loc[4]:loc[5]])
+		attrs := notes[loc[6]:loc[7]]
+
+		switch name {
+		case "h1", "h2", "h3", "h4", "h5", "h6":
+			if closing {
+				w().WriteString("\n\n")
+			} else {
+				w().WriteString(strings.Repeat("#", int(name[1]-'0')) + " ")
+			}
+		case "p", "div":
+			if closing {
+				blockEnd()
+			}
+		case "br":
+			w().WriteString("\n")
+		case "strong", "b":
+			w().WriteString("**")
+		case "em", "i":
+			w().WriteString("*")
+		case "s", "del":
+			w().WriteString("~~")
+		case "code":
+			if !inPre {
+				w().WriteString("`")
+			}
+		case "pre":
+			inPre = !closing
+			if closing {
+				w().WriteString("\n```\n\n")
+			} else {
+				w().WriteString("```\n")
+			}
+		case "hr":
+			w().WriteString("---\n\n")
+		case "a":
+			if closing && len(links) > 0 {
+				if href := links[len(links)-1]; href != "" {
+					w().WriteString("](" + href + ")")
+				}
+				links = links[:len(links)-1]
+			} else if !closing {
+				href := html.UnescapeString(notesAttrValue(attrs, "href"))
+				if href != "" {
+					w().WriteString("[")
+				}
+				links = append(links, href)
+			}
+		case "ul", "ol":
+			if closing {
+				if len(lists) > 0 {
+					lists = lists[:len(lists)-1]
+				}
+				if len(lists) == 0 {
+					w().WriteString("\n")
+				}
+			} else {
+				if len(lists) > 0 {
+					newline()
+				}
+				lists = append(lists, list{ordered: name == "ol"})
+			}
+		case "li":
+			if closing {
+				newline()
+				continue
+			}
+			indent, marker := "", "- "
+			if len(lists) > 0 {
+				indent = strings.Repeat("  ", len(lists)-1)
+				current := &lists[len(lists)-1]
+				current.index++
+				if current.ordered {
+					marker = fmt.Sprintf("%d. ", current.index)
+				}
+			}
+			switch notesAttrValue(attrs, "data-checked") {
+			case "true":
+				marker += "[x] "
+			case "false":
+				marker += "[ ] "
+			}
+			w().WriteString(indent + marker)
+		case "blockquote":
+			if closing && len(quotes) > 0 {
+				inner := strings.TrimSpace(quotes[len(quotes)-1].String())
+				quotes = quotes[:len(quotes)-1]
+				for _, line := range strings.Split(inner, "\n") {
+					w().WriteString(strings.TrimRight("> "+line, " ") + "\n")
+				}
+				w().WriteString("\n")
+			} else if !closing {
+				quotes = append(quotes, &strings.Builder{})
+			}
+		}
+	}
+	w().WriteString(html.UnescapeString(notes[last:]))
+
+	md := out.String()
+	lines := strings.Split(md, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
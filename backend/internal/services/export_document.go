@@ -0,0 +1,137 @@
+package services
+
+import (
+	"regexp"
+	"strings"
+)
+
+// docBlock is a paragraph of a PDF or DOCX export, parsed from the Markdown export
+type docBlock struct {
+	Kind   string // h1, h2, h3, p, li, quote or pre
+	Indent int    // Nesting level of list items
+	Marker string // List item marker: a bullet, number or checkbox
+	Runs   []docRun
+}
+
+// docRun is a piece of text with one style
+type docRun struct {
+	Text   string
+	Bold   bool
+	Italic bool
+	Code   bool
+}
+
+var (
+	headingLine = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	listLine    = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(\[[ xX]\]\s+)?(.*)$`)
+	inlineStyle = regexp.MustCompile("\\*\\*(.+?)\\*\\*|__(.+?)__|\\*([^*\\s](?:[^*]*[^*\\s])?)\\*|`([^`]+)`|~~(.+?)~~|\\[([^\\]]+)\\]\\(([^)\\s]+)\\)")
+)
+
+// markdownBlocks parses the Markdown export into blocks. It covers what the
+// export templates and notesMarkdown produce, not all of Markdown.
+func markdownBlocks(md string) []docBlock {
+	var blocks []docBlock
+	var paragraph []string
+	flush := func() {
+		if len(paragraph) > 0 {
+			blocks = append(blocks, docBlock{Kind: "p", Runs: inlineRuns(strings.Join(paragraph, " "))})
+			paragraph = nil
+		}
+	}
+
+	lines := strings.Split(strings.ReplaceAll(md, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t")
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") {
+			flush()
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				code = append(code, lines[i])
+			}
+			blocks = append(blocks, docBlock{Kind: "pre", Runs: []docRun{{Text: strings.Join(code, "\n"), Code: true}}})
+			continue
+		}
+
+		if trimmed == "" || trimmed == "---" {
+			flush()
+			continue
+		}
+
+		if m := headingLine.FindStringSubmatch(trimmed); m != nil {
+			flush()
+			kind := "h3"
+			if len(m[1]) < 3 {
+				kind = "h" + string(rune('0'+len(m[1])))
+			}
+			blocks = append(blocks, docBlock{Kind: kind, Runs: inlineRuns(m[2])})
+			continue
+		}
+
+		if m := listLine.FindStringSubmatch(line); m != nil {
+			flush()
+			marker := "•"
+			if m[2] != "-" && m[2] != "*" && m[2] != "+" {
+				marker = m[2]
+			}
+			switch strings.ToLower(strings.TrimSpace(m[3])) {
+			case "[ ]":
+				marker = "[ ]"
+			case "[x]":
+				marker = "[x]"
+			}
+			blocks = append(blocks, docBlock{Kind: "li", Indent: len(m[1]) / 2, Marker: marker, Runs: inlineRuns(m[4])})
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, ">") {
+			flush()
+			blocks = append(blocks, docBlock{Kind: "quote", Runs: inlineRuns(strings.TrimSpace(strings.TrimPrefix(trimmed, ">")))})
+			continue
+		}
+
+		paragraph = append(paragraph, trimmed)
+	}
+	flush()
+
+	return blocks
+}
+
+// inlineRuns splits text into runs by its bold, italic, code, strikethrough
+// and link markup. Links keep their text followed by the URL.
+func inlineRuns(text string) []docRun {
+	var runs []docRun
+	last := 0
+	for _, m := range inlineStyle.FindAllStringSubmatchIndex(text, -1) {
+		if m[0] > last {
+			runs = append(runs, docRun{Text: text[last:m[0]]})
+		}
+		last = m[1]
+
+		group := func(n int) string {
+			if m[2*n] < 0 {
+				return ""
+			}
+			return text[m[2*n]:m[2*n+1]]
+		}
+		switch {
+		case group(1) != "":
+			runs = append(runs, docRun{Text: group(1), Bold: true})
+		case group(2) != "":
+			runs = append(runs, docRun{Text: group(2), Bold: true})
+		case group(3) != "":
+			runs = append(runs, docRun{Text: group(3), Italic: true})
+		case group(4) != "":
+			runs = append(runs, docRun{Text: group(4), Code: true})
+		case group(5) != "":
+			runs = append(runs, docRun{Text: group(5)})
+		default:
+			runs = append(runs, docRun{Text: group(6) + " (" + group(7) + ")"})
+		}
+	}
+	if last < len(text) {
+		runs = append(runs, docRun{Text: text[last:]})
+	}
+	return runs
+}
@@ -0,0 +1,289 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PDF page geometry in points (A4)
+const (
+	pdfPageWidth  = 595.0
+	pdfPageHeight = 842.0
+	pdfMargin     = 56.0
+	pdfListIndent = 16.0
+)
+
+// pdfFonts are the standard PDF fonts the export uses, so nothing is embedded
+var pdfFonts = []string{"Helvetica", "Helvetica-Bold", "Helvetica-Oblique", "Courier"}
+
+// pdfStyle is the font and spacing of a block kind
+type pdfStyle struct {
+	size, before, after float64
+	bold, italic        bool
+}
+
+var pdfStyles = map[string]pdfStyle{
+	"h1":    {size: 20, before: 0, after: 10, bold: true},
+	"h2":    {size: 14, before: 14, after: 6, bold: true},
+	"h3":    {size: 12, before: 10, after: 4, bold: true},
+	"p":     {size: 10.5, after: 6},
+	"li":    {size: 10.5, after: 3},
+	"quote": {size: 10.5, after: 6, italic: true},
+	"pre":   {size: 9, after: 6},
+}
+
+// Glyph widths of ASCII 32-126 in thousandths of the font size
+var (
+	helveticaWidths = [95]int{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	}
+	helveticaBoldWidths = [95]int{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	}
+)
+
+// winAnsiExtras maps the characters of Windows-1252's 0x80-0x9F range
+var winAnsiExtras = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87, 'ˆ': 0x88,
+	'‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E, '‘': 0x91, '’': 0x92, '“': 0x93,
+	'”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B,
+	'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// pdfWord is a word placed on a line, with its font and width
+type pdfWord struct {
+	text  []byte
+	font  int
+	size  float64
+	width float64
+}
+
+// pdfWriter lays out blocks onto pages of content stream operators
+type pdfWriter struct {
+	pages []*bytes.Buffer
+	page  *bytes.Buffer
+	y     float64
+}
+
+// renderPDF lays blocks out as an A4 PDF using the standard fonts. Text
+// outside Windows-1252 is replaced with '?'.
+func renderPDF(title string, blocks []docBlock) []byte {
+	w := &pdfWriter{}
+	w.newPage()
+
+	for i, block := range blocks {
+		style := pdfStyles[block.Kind]
+		if i > 0 {
+			w.y -= style.before
+		}
+
+		left := pdfMargin
+		var marker *pdfWord
+		if block.Kind == "li" {
+			left += float64(block.Indent) * pdfListIndent
+			m := newPDFWord(block.Marker, 0, style.size)
+			marker = &m
+			left += pdfListIndent
+		}
+		if block.Kind == "quote" {
+			left += pdfListIndent
+		}
+
+		lineHeight := style.size * 1.35
+		for n, line := range layoutPDFLines(block, style, pdfPageWidth-pdfMargin-left) {
+			if w.y-lineHeight < pdfMargin {
+				w.newPage()
+			}
+			w.y -= lineHeight
+
+			fmt.Fprintf(w.page, "BT %.2f %.2f Td ", left, w.y+lineHeight-style.size)
+			if n == 0 && marker != nil {
+				fmt.Fprintf(w.page, "/F1 %.1f Tf %.2f 0 Td (%s) Tj %.2f 0 Td ", marker.size, -pdfListIndent, pdfEscape(marker.text), pdfListIndent-marker.width)
+			}
+			for _, word := range line {
+				fmt.Fprintf(w.page, "/F%d %.1f Tf (%s) Tj ", word.font+1, word.size, pdfEscape(word.text))
+			}
+			w.page.WriteString("ET\n")
+		}
+		w.y -= style.after
+	}
+
+	return w.document(title)
+}
+
+func (w *pdfWriter) newPage() {
+	w.page = &bytes.Buffer{}
+	w.pages = append(w.pages, w.page)
+	w.y = pdfPageHeight - pdfMargin
+}
+
+// layoutPDFLines wraps a block's runs into lines of words no wider than width.
+// Preformatted blocks keep their line breaks.
+func layoutPDFLines(block docBlock, style pdfStyle, width float64) [][]pdfWord {
+	var lines [][]pdfWord
+	var line []pdfWord
+	lineWidth := 0.0
+	breakLine := func() {
+		lines = append(lines, line)
+		line, lineWidth = nil, 0
+	}
+
+	for _, run := range block.Runs {
+		font := 0
+		switch {
+		case run.Code || block.Kind == "pre":
+			font = 3
+		case run.Bold || style.bold:
+			font = 1
+		case run.Italic || style.italic:
+			font = 2
+		}
+
+		if block.Kind == "pre" {
+			for i, text := range strings.Split(run.Text, "\n") {
+				if i > 0 {
+					breakLine()
+				}
+				line = append(line, newPDFWord(text, font, style.size))
+			}
+			continue
+		}
+
+		// Keep the spaces around words, so runs join up as written
+		for _, text := range splitKeepingSpaces(run.Text) {
+			word := newPDFWord(text, font, style.size)
+			if lineWidth+word.width > width && len(line) > 0 && strings.TrimSpace(text) != "" {
+				breakLine()
+				word = newPDFWord(strings.TrimLeft(text, " "), font, style.size)
+			}
+			if len(line) == 0 && strings.TrimSpace(text) == "" {
+				continue
+			}
+			line = append(line, word)
+			lineWidth += word.width
+		}
+	}
+	if len(line) > 0 || len(lines) == 0 {
+		breakLine()
+	}
+	return lines
+}
+
+// splitKeepingSpaces splits text before every space, e.g. "a b" into "a", " b"
+func splitKeepingSpaces(text string) []string {
+	var parts []string
+	start := 0
+	for i := 1; i < len(text); i++ {
+		if text[i] == ' ' && text[i-1] != ' ' {
+			parts = append(parts, text[start:i])
+			start = i
+		}
+	}
+	return append(parts, text[start:])
+}
+
+func newPDFWord(text string, font int, size float64) pdfWord {
+	encoded := winAnsi(text)
+	units := 0
+	for _, b := range encoded {
+		switch {
+		case font == 3:
+			units += 600
+		case b >= 32 && b <= 126 && font == 1:
+			units += helveticaBoldWidths[b-32]
+		case b >= 32 && b <= 126:
+			units += helveticaWidths[b-32]
+		default:
+			units += 556
+		}
+	}
+	return pdfWord{text: encoded, font: font, size: size, width: float64(units) * size / 1000}
+}
+
+// winAnsi encodes text as Windows-1252, the encoding of the standard fonts
+func winAnsi(text string) []byte {
+	var out []byte
+	for _, r := range text {
+		switch {
+		case r == '\t':
+			out = append(out, ' ', ' ', ' ', ' ')
+		case r >= 32 && r <= 126, r >= 0xA0 && r <= 0xFF:
+			out = append(out, byte(r))
+		case winAnsiExtras[r] != 0:
+			out = append(out, winAnsiExtras[r])
+		case r < 32:
+		default:
+			out = append(out, '?')
+		}
+	}
+	return out
+}
+
+func pdfEscape(text []byte) string {
+	var b strings.Builder
+	for _, c := range text {
+		if c == '\\' || c == '(' || c == ')' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// document assembles the pages into a PDF file with its cross-reference table
+func (w *pdfWriter) document(title string) []byte {
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// 1 catalog, 2 page tree, 3 info, then fonts, then a page and its content per page
+	fontsStart := 4
+	pagesStart := fontsStart + len(pdfFonts)
+
+	kids := make([]string, len(w.pages))
+	for i := range w.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", pagesStart+2*i)
+	}
+	fonts := make([]string, len(pdfFonts))
+	for i := range pdfFonts {
+		fonts[i] = fmt.Sprintf("/F%d %d 0 R", i+1, fontsStart+i)
+	}
+
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(w.pages)))
+	object(fmt.Sprintf("<< /Title (%s) /Producer (Echo) /CreationDate (D:%s) >>", pdfEscape(winAnsi(title)), time.Now().UTC().Format("20060102150405Z")))
+	for _, font := range pdfFonts {
+		object(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", font))
+	}
+	for i, page := range w.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << %s >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, strings.Join(fonts, " "), pagesStart+2*i+1))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info 3 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return out.Bytes()
+}
@@ -0,0 +1,307 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrFolderCycle is returned when a folder would be moved into itself or one of its subfolders
+var ErrFolderCycle = errors.New("a folder cannot be moved into itself or one of its subfolders")
+
+// maxGlossaryLength keeps glossaries within what fits in a Whisper prompt
+const maxGlossaryLength = 800
+
+// FolderDefaults are settings new meetings copy from their folder. Empty
+// values fall back to the parent folder's.
+type FolderDefaults struct {
+	Glossary       string `json:"glossary"`        // Names and terms to help transcription, comma separated
+	PromptTemplate string `json:"prompt_template"` // Extra instructions for AI formatting
+	RetentionDays  int    `json:"retention_days"`  // 0 keeps meetings forever
+	Workspace      string `json:"workspace"`       // Whose redaction policy applies, DefaultWorkspace when empty
+}
+
+// workspace returns the workspace new meetings with these defaults belong to
+func (d FolderDefaults) workspace() string {
+	if d.Workspace == "" {
+		return DefaultWorkspace
+	}
+	return d.Workspace
+}
+
+type Folder struct {
+	ID       int    `json:"id"`
+	ParentID *int   `json:"parent_id"`
+	Name     string `json:"name"`
+	FolderDefaults
+	MeetingCount int       `json:"meeting_count"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// FolderService is the FolderRepository stored in SQLite or PostgreSQL
+type FolderService struct {
+	db *sql.DB
+}
+
+func NewFolderService(db *sql.DB) *FolderService {
+	return &FolderService{db: db}
+}
+
+const folderColumns = `f.id, f.parent_id, f.name, f.glossary, f.prompt_template, f.retention_days, f.workspace, f.created_at,
+	(SELECT COUNT(*) FROM meetings m WHERE m.folder_id = f.id AND m.deleted_at IS NULL)`
+
+func scanFolder(row rowScanner) (*Folder, error) {
+	var f Folder
+	var parentID sql.NullInt64
+	var createdAt int64
+	if err := row.Scan(&f.ID, &parentID, &f.Name, &f.Glossary, &f.PromptTemplate, &f.RetentionDays, &f.Workspace, &createdAt, &f.MeetingCount); err != nil {
+		return nil, err
+	}
+
+	f.ParentID = nullableID(parentID)
+	f.CreatedAt = time.Unix(createdAt, 0)
+	return &f, nil
+}
+
+// nullableID converts a nullable ID column to a pointer
+func nullableID(v sql.NullInt64) *int {
+	if !v.Valid {
+		return nil
+	}
+	id := int(v.Int64)
+	return &id
+}
+
+// GetAll lists every folder; clients build the tree from parent_id
+func (s *FolderService) GetAll() ([]Folder, error) {
+	rows, err := s.db.Query("SELECT " + folderColumns + " FROM folders f ORDER BY LOWER(f.name)")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	folders := []Folder{}
+	for rows.Next() {
+		f, err := scanFolder(rows)
+		if err != nil {
+			return nil, err
+		}
+		folders = append(folders, *f)
+	}
+
+	return folders, rows.Err()
+}
+
+// GetByID returns a folder, or nil if it doesn't exist
+func (s *FolderService) GetByID(id int) (*Folder, error) {
+	f, err := scanFolder(s.db.QueryRow("SELECT "+folderColumns+" FROM folders f WHERE f.id = ?", id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return f, err
+}
+
+// Create adds a folder under parentID, or at the top level when nil
+func (s *FolderService) Create(name string, parentID *int, defaults FolderDefaults) (*Folder, error) {
+	name = strings.TrimSpace(name)
+	if err := validateFolder(name, defaults); err != nil {
+		return nil, err
+	}
+	if err := checkParent(s, 0, parentID); err != nil {
+		return nil, err
+	}
+
+	var id int
+	err := s.db.QueryRow(
+		"INSERT INTO folders (parent_id, name, glossary, prompt_template, retention_days, workspace, created_at) VALUES (?, ?, ?, ?, ?, ?, ?) RETURNING id",
+		parentID, name, defaults.Glossary, defaults.PromptTemplate, defaults.RetentionDays, defaults.Workspace, time.Now().Unix(),
+	).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetByID(id)
+}
+
+// Update renames a folder, moves it under parentID and replaces its defaults
+func (s *FolderService) Update(id int, name string, parentID *int, defaults FolderDefaults) (*Folder, error) {
+	name = strings.TrimSpace(name)
+	if err := validateFolder(name, defaults); err != nil {
+		return nil, err
+	}
+	if err := checkParent(s, id, parentID); err != nil {
+		return nil, err
+	}
+
+	result, err := s.db.Exec(
+		"UPDATE folders SET parent_id = ?, name = ?, glossary = ?, prompt_template = ?, retention_days = ?, workspace = ? WHERE id = ?",
+		parentID, name, defaults.Glossary, defaults.PromptTemplate, defaults.RetentionDays, defaults.Workspace, id,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return nil, nil
+	}
+
+	return s.GetByID(id)
+}
+
+// Delete removes a folder. Its meetings and subfolders move up to its parent.
+func (s *FolderService) Delete(id int) error {
+	folder, err := s.GetByID(id)
+	if err != nil || folder == nil {
+		return err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("UPDATE meetings SET folder_id = ?, version = version + 1 WHERE folder_id = ?", folder.ParentID, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE folders SET parent_id = ? WHERE parent_id = ?", folder.ParentID, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM folders WHERE id = ?", id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Subtree returns the IDs of a folder and all folders nested below it
+func (s *FolderService) Subtree(id int) ([]int, error) {
+	rows, err := s.db.Query(`
+		WITH RECURSIVE subtree(id) AS (
+			SELECT id FROM folders WHERE id = ?
+			UNION
+			SELECT f.id FROM folders f JOIN subtree ON f.parent_id = subtree.id
+		)
+		SELECT id FROM subtree
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var folderID int
+		if err := rows.Scan(&folderID); err != nil {
+			return nil, err
+		}
+		ids = append(ids, folderID)
+	}
+
+	return ids, rows.Err()
+}
+
+// EffectiveDefaults resolves the defaults a new meeting in this folder gets,
+// taking each empty setting from the nearest ancestor that sets it
+func (s *FolderService) EffectiveDefaults(id int) (FolderDefaults, error) {
+	return resolveDefaults(s, id)
+}
+
+// resolveDefaults implements EffectiveDefaults for any FolderRepository
+func resolveDefaults(folders FolderRepository, id int) (FolderDefaults, error) {
+	var defaults FolderDefaults
+	current := &id
+
+	// The depth guard protects against cycles written outside the service
+	for depth := 0; current != nil && depth < 64; depth++ {
+		folder, err := folders.GetByID(*current)
+		if err != nil {
+			return defaults, err
+		}
+		if folder == nil {
+			break
+		}
+
+		if defaults.Glossary == "" {
+			defaults.Glossary = folder.Glossary
+		}
+		if defaults.PromptTemplate == "" {
+			defaults.PromptTemplate = folder.PromptTemplate
+		}
+		if defaults.RetentionDays == 0 {
+			defaults.RetentionDays = folder.RetentionDays
+		}
+		if defaults.Workspace == "" {
+			defaults.Workspace = folder.Workspace
+		}
+		current = folder.ParentID
+	}
+
+	return defaults, nil
+}
+
+// MoveMeeting puts a meeting into a folder, or back to the top level when folderID is nil
+func (s *FolderService) MoveMeeting(meetingID int, folderID *int) (bool, error) {
+	result, err := s.db.Exec(
+		"UPDATE meetings SET folder_id = ?, updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = ? AND deleted_at IS NULL",
+		folderID, meetingID,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// checkParent verifies parentID exists and isn't folder id or one of its descendants
+func checkParent(folders FolderRepository, id int, parentID *int) error {
+	if parentID == nil {
+		return nil
+	}
+
+	parent, err := folders.GetByID(*parentID)
+	if err != nil {
+		return err
+	}
+	if parent == nil {
+		return fmt.Errorf("parent folder %d does not exist", *parentID)
+	}
+	if id == 0 {
+		return nil
+	}
+
+	subtree, err := folders.Subtree(id)
+	if err != nil {
+		return err
+	}
+	for _, descendant := range subtree {
+		if descendant == *parentID {
+			return ErrFolderCycle
+		}
+	}
+	return nil
+}
+
+func validateFolder(name string, defaults FolderDefaults) error {
+	if name == "" {
+		return fmt.Errorf("folder name must not be empty")
+	}
+	if len(name) > 100 {
+		return fmt.Errorf("folder name must be at most 100 characters")
+	}
+	return validateDefaults(defaults)
+}
+
+func validateDefaults(defaults FolderDefaults) error {
+	if len(defaults.Glossary) > maxGlossaryLength {
+		return fmt.Errorf("glossary must be at most %d characters", maxGlossaryLength)
+	}
+	if defaults.RetentionDays < 0 {
+		return fmt.Errorf("retention_days must not be negative")
+	}
+	if defaults.Workspace != "" && !ValidWorkspace(defaults.Workspace) {
+		return fmt.Errorf("invalid workspace %q, %s", defaults.Workspace, workspaceRule)
+	}
+	return nil
+}
@@ -2,26 +2,63 @@ package services
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/google/generative-ai-go/genai"
+	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 )
 
 type GeminiService struct {
-	client *genai.Client
+	client   *genai.Client
+	redactor *RedactionService
+	settings *AISettingsService
+	// workspace picks the redaction policy, DefaultWorkspace when empty
+	workspace string
 }
 
-// NewGeminiService initializes the client ONCE to save connection time
-func NewGeminiService(apiKey string) (*GeminiService, error) {
+// maxTitleInputChars bounds how much of a transcript is sent for title generation;
+// the opening minutes are usually enough to tell what a meeting was about
+const maxTitleInputChars = 20000
+
+// truncateRunes cuts text to at most max characters without splitting one
+func truncateRunes(text string, max int) string {
+	if utf8.RuneCountInString(text) <= max {
+		return text
+	}
+	return string([]rune(text)[:max])
+}
+
+type MeetingTitle struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// NewGeminiService initializes the client ONCE to save connection time.
+// Every prompt is passed through the redactor before it leaves the server.
+func NewGeminiService(apiKey string, redactor *RedactionService, settings *AISettingsService) (*GeminiService, error) {
 	ctx := context.Background()
 	client, err := genai.NewClient(ctx, option.WithAPIKey(apiKey))
 	if err != nil {
 		return nil, err
 	}
-	
-	return &GeminiService{client: client}, nil
+
+	return &GeminiService{client: client, redactor: redactor, settings: settings}, nil
+}
+
+// ForWorkspace returns a service sharing this client whose calls follow the
+// redaction policy of workspace, e.g. the workspace of the meeting at hand
+func (s *GeminiService) ForWorkspace(workspace string) *GeminiService {
+	scoped := *s
+	scoped.workspace = workspace
+	return &scoped
 }
 
 // Close ensures the client connection is cleaned up when the app stops
@@ -29,15 +66,77 @@ func (s *GeminiService) Close() {
 	s.client.Close()
 }
 
-// Beautify uses Gemini to format and improve text quality
-func (s *GeminiService) Beautify(text string) (string, error) {
+// ActionSettings returns the model settings used for an AI action
+func (s *GeminiService) ActionSettings(action string) (AIActionSettings, bool) {
+	return s.settings.Get(action)
+}
+
+// ListModels returns the Gemini models that support content generation
+func (s *GeminiService) ListModels(ctx context.Context) ([]ModelInfo, error) {
+	models := []ModelInfo{}
+	iter := s.client.ListModels(ctx)
+	for {
+		m, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !contains(m.SupportedGenerationMethods, "generateContent") {
+			continue
+		}
+
+		models = append(models, ModelInfo{
+			Name:             strings.TrimPrefix(m.Name, "models/"),
+			DisplayName:      m.DisplayName,
+			InputTokenLimit:  m.InputTokenLimit,
+			OutputTokenLimit: m.OutputTokenLimit,
+		})
+	}
+
+	return models, nil
+}
+
+var harmCategories = map[string]genai.HarmCategory{
+	"harassment":        genai.HarmCategoryHarassment,
+	"hate_speech":       genai.HarmCategoryHateSpeech,
+	"sexually_explicit": genai.HarmCategorySexuallyExplicit,
+	"dangerous_content": genai.HarmCategoryDangerousContent,
+}
+
+var harmThresholds = map[string]genai.HarmBlockThreshold{
+	"block_none":             genai.HarmBlockNone,
+	"block_only_high":        genai.HarmBlockOnlyHigh,
+	"block_medium_and_above": genai.HarmBlockMediumAndAbove,
+	"block_low_and_above":    genai.HarmBlockLowAndAbove,
+}
+
+// model returns a generative model configured with the action's settings
+func (s *GeminiService) model(action string) *genai.GenerativeModel {
+	settings, _ := s.settings.Get(action)
+
+	model := s.client.GenerativeModel(settings.Model)
+	model.SetTemperature(settings.Temperature)
+	if settings.MaxOutputTokens > 0 {
+		model.SetMaxOutputTokens(settings.MaxOutputTokens)
+	}
+	for _, ss := range settings.SafetySettings {
+		model.SafetySettings = append(model.SafetySettings, &genai.SafetySetting{
+			Category:  harmCategories[ss.Category],
+			Threshold: harmThresholds[ss.Threshold],
+		})
+	}
+
+	return model
+}
+
+// Beautify uses Gemini to format and improve text quality. instructions are
+// optional extra rules, e.g. from the meeting's folder prompt template.
+func (s *GeminiService) Beautify(text, instructions string) (string, error) {
 	ctx := context.Background()
 
-    // UPDATED: Using the model found in your list
-	model := s.client.GenerativeModel("gemini-2.5-flash")
-	
-    // Optimization: Lower temperature for more deterministic formatting
-    model.SetTemperature(0.3) 
+	model := s.model("beautify")
 
 	prompt := fmt.Sprintf(`You are a professional meeting notes formatter. Clean up and improve the following text while preserving all important information:
 
@@ -47,74 +146,581 @@ Rules:
 - Keep the same tone and meaning
 - Don't add information that wasn't there
 - Return ONLY the improved text, no explanations
-
+%s
 Text to improve:
-%s`, text)
+%s`, extraInstructions(instructions), text)
+
+	return s.generate(ctx, "beautify", model, prompt)
+}
+
+// extraInstructions renders user supplied instructions as a prompt section
+func extraInstructions(instructions string) string {
+	if instructions = strings.TrimSpace(instructions); instructions == "" {
+		return ""
+	}
+	return "\nAdditional instructions:\n" + instructions + "\n"
+}
+
+// ExtractedTask is an action item found in meeting notes. DueDate is
+// YYYY-MM-DD or empty, Priority is one of low, medium or high.
+type ExtractedTask struct {
+	Content    string  `json:"content"`
+	Assignee   string  `json:"assignee"`
+	DueDate    string  `json:"due_date"`
+	Priority   string  `json:"priority"`
+	Confidence float64 `json:"confidence"`
+	Quote      string  `json:"quote"`
+}
 
-	resp, err := model.GenerateContent(ctx, genai.Text(prompt))
+// ErrInvalidModelOutput is returned when Gemini's response doesn't match the requested schema
+var ErrInvalidModelOutput = errors.New("AI response did not match the expected format")
+
+var extractedTaskSchema = &genai.Schema{
+	Type: genai.TypeArray,
+	Items: &genai.Schema{
+		Type: genai.TypeObject,
+		Properties: map[string]*genai.Schema{
+			"content":    {Type: genai.TypeString, Description: "The action item, phrased as an instruction"},
+			"assignee":   {Type: genai.TypeString, Description: "Who should do it, empty if not mentioned"},
+			"due_date":   {Type: genai.TypeString, Description: "Due date as YYYY-MM-DD, empty if not mentioned"},
+			"priority":   {Type: genai.TypeString, Format: "enum", Enum: []string{"low", "medium", "high"}},
+			"confidence": {Type: genai.TypeNumber, Description: "How sure you are this is a real task, 0 to 1"},
+			"quote":      {Type: genai.TypeString, Description: "The sentence from the notes the task is based on"},
+		},
+		Required: []string{"content", "assignee", "due_date", "priority", "confidence", "quote"},
+	},
+}
+
+// ExtractTasks uses Gemini to extract structured action items from text.
+// Relative due dates ("next Friday") are resolved against meetingDate.
+func (s *GeminiService) ExtractTasks(text string, meetingDate time.Time) ([]ExtractedTask, error) {
+	ctx := context.Background()
+
+	model := s.model("extract-tasks")
+	model.ResponseMIMEType = "application/json"
+	model.ResponseSchema = extractedTaskSchema
+
+	prompt := fmt.Sprintf(`Extract all action items and tasks from the following meeting notes.
+
+Rules:
+- Be specific and actionable
+- Set the assignee only if the notes say who should do it
+- The meeting took place on %s (%s); resolve relative dates like "tomorrow" or "next Friday" against it
+- Priority is high for blockers or explicit urgency, low for nice-to-haves, medium otherwise
+- Quote the sentence each task is based on verbatim
+- If no tasks are found, return an empty array
+
+Meeting notes:
+%s`, meetingDate.Format("2006-01-02"), meetingDate.Weekday(), text)
+
+	raw, err := s.generate(ctx, "extract-tasks", model, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseExtractedTasks(raw)
+}
+
+// parseExtractedTasks decodes Gemini's task list, normalizing fields the
+// model commonly gets slightly wrong. Each task and field is decoded on its
+// own, so a wrong-typed field only drops that field and a malformed task
+// doesn't lose the others.
+func parseExtractedTasks(raw string) ([]ExtractedTask, error) {
+	var items []json.RawMessage
+	if err := json.Unmarshal([]byte(raw), &items); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidModelOutput, err)
+	}
+
+	valid := []ExtractedTask{}
+	for _, item := range items {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(item, &fields); err != nil {
+			continue
+		}
+
+		t := ExtractedTask{
+			Content:    strings.TrimSpace(jsonString(fields["content"])),
+			Assignee:   strings.TrimSpace(jsonString(fields["assignee"])),
+			DueDate:    strings.TrimSpace(jsonString(fields["due_date"])),
+			Priority:   strings.ToLower(strings.TrimSpace(jsonString(fields["priority"]))),
+			Confidence: jsonNumber(fields["confidence"]),
+			Quote:      strings.TrimSpace(jsonString(fields["quote"])),
+		}
+		if t.Content == "" {
+			continue
+		}
+
+		switch t.Priority {
+		case "low", "medium", "high":
+		default:
+			t.Priority = "medium"
+		}
+
+		t.Confidence = math.Max(0, math.Min(1, t.Confidence))
+
+		if _, err := time.Parse("2006-01-02", t.DueDate); err != nil {
+			t.DueDate = ""
+		}
+
+		valid = append(valid, t)
+	}
+
+	return valid, nil
+}
+
+// jsonString returns a JSON string value, or "" when the value is missing or
+// of another type
+func jsonString(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return ""
+	}
+	return s
+}
+
+// jsonNumber returns a JSON number, also accepting one quoted as a string,
+// or 0 when the value is missing or not a number
+func jsonNumber(raw json.RawMessage) float64 {
+	var n float64
+	if err := json.Unmarshal(raw, &n); err == nil {
+		return n
+	}
+	n, err := strconv.ParseFloat(strings.TrimSpace(jsonString(raw)), 64)
+	if err != nil || math.IsNaN(n) {
+		return 0
+	}
+	return n
+}
+
+// GenerateTitle uses Gemini to produce a concise title and one-sentence description for a transcript
+func (s *GeminiService) GenerateTitle(transcript string) (*MeetingTitle, error) {
+	ctx := context.Background()
+
+	model := s.model("generate-title")
+	model.ResponseMIMEType = "application/json"
+
+	transcript = truncateRunes(transcript, maxTitleInputChars)
+
+	prompt := fmt.Sprintf(`Write a title and a short description for the meeting transcript below.
+
+Rules:
+- The title is at most 8 words, in the language of the transcript, with no quotes or trailing period
+- The description is a single sentence of at most 25 words
+- Describe the subject of the meeting, not the fact that it is a meeting
+- Return JSON: {"title": "...", "description": "..."}
+
+Transcript:
+%s`, transcript)
+
+	text, err := s.generate(ctx, "generate-title", model, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	var title MeetingTitle
+	if err := json.Unmarshal([]byte(text), &title); err != nil {
+		return nil, fmt.Errorf("invalid title response from Gemini: %w", err)
+	}
+
+	title.Title = strings.Trim(strings.TrimSpace(title.Title), `"'.`)
+	title.Description = strings.TrimSpace(title.Description)
+	if title.Title == "" {
+		return nil, fmt.Errorf("Gemini returned an empty title")
+	}
+
+	return &title, nil
+}
+
+// generate redacts PII from the prompt, calls Gemini and re-hydrates the
+// placeholders in the response. Redaction failures abort the call rather
+// than sending the text unredacted.
+func (s *GeminiService) generate(ctx context.Context, action string, model *genai.GenerativeModel, prompt string) (string, error) {
+	workspace := s.workspace
+	if workspace == "" {
+		workspace = DefaultWorkspace
+	}
+	redaction, err := s.redactor.Redact(workspace, action, prompt)
+	if err != nil {
+		return "", fmt.Errorf("redaction failed: %w", err)
+	}
+
+	resp, err := model.GenerateContent(ctx, genai.Text(redaction.Text))
 	if err != nil {
 		return "", err
 	}
 
-	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
+	text, err := responseText(resp)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.redactor.SaveReport(&redaction.Report); err != nil {
+		fmt.Printf("⚠️  Failed to save redaction report: %v\n", err)
+	}
+
+	return redaction.Restore(text, model.ResponseMIMEType == "application/json"), nil
+}
+
+// responseText concatenates the text parts of the first candidate
+func responseText(resp *genai.GenerateContentResponse) (string, error) {
+	if len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil || len(resp.Candidates[0].Content.Parts) == 0 {
 		return "", fmt.Errorf("no response from Gemini")
 	}
 
-    // Safer text extraction
-    var resultBuilder strings.Builder
-    for _, part := range resp.Candidates[0].Content.Parts {
-        if txt, ok := part.(genai.Text); ok {
-            resultBuilder.WriteString(string(txt))
-        }
-    }
-    
+	var resultBuilder strings.Builder
+	for _, part := range resp.Candidates[0].Content.Parts {
+		if txt, ok := part.(genai.Text); ok {
+			resultBuilder.WriteString(string(txt))
+		}
+	}
+
 	return strings.TrimSpace(resultBuilder.String()), nil
 }
 
-// ExtractTasks uses Gemini to extract action items from text
-func (s *GeminiService) ExtractTasks(text string) ([]string, error) {
+type FollowUpActionItems struct {
+	Owner string   `json:"owner"`
+	Items []string `json:"items"`
+}
+
+type FollowUpEmail struct {
+	Subject     string                `json:"subject"`
+	Greeting    string                `json:"greeting"`
+	Summary     string                `json:"summary"`
+	Decisions   []string              `json:"decisions"`
+	ActionItems []FollowUpActionItems `json:"action_items"`
+	Closing     string                `json:"closing"`
+}
+
+// DraftFollowUp uses Gemini to write a recap email from a meeting's notes and transcript
+func (s *GeminiService) DraftFollowUp(title, notes, transcript string) (*FollowUpEmail, error) {
 	ctx := context.Background()
 
-    // UPDATED: Using the model found in your list
-	model := s.client.GenerativeModel("gemini-2.5-flash")
-    model.SetTemperature(0.1) // Low temp for factual extraction
+	model := s.model("draft-followup")
+	model.ResponseMIMEType = "application/json"
 
-	prompt := fmt.Sprintf(`Extract all action items and tasks from the following meeting notes.
+	prompt := fmt.Sprintf(`Write a recap email to send to the attendees of the meeting below.
 
 Rules:
-- Return each task on a new line starting with "- "
-- Be specific and actionable
-- Include who should do it if mentioned
-- If no tasks found, return "No tasks found"
+- Base the email on the notes first and use the transcript only to fill gaps
+- Keep the summary to one short paragraph
+- List only decisions that were actually made
+- Group action items by owner, use "Unassigned" when no owner was mentioned
+- Don't add information that wasn't there
+- Return JSON: {"subject": "...", "greeting": "...", "summary": "...", "decisions": ["..."], "action_items": [{"owner": "...", "items": ["..."]}], "closing": "..."}
 
-Meeting notes:
-%s`, text)
+Meeting title: %s
+
+Notes:
+%s
+
+Transcript:
+%s`, title, notes, transcript)
+
+	text, err := s.generate(ctx, "draft-followup", model, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	var email FollowUpEmail
+	if err := json.Unmarshal([]byte(text), &email); err != nil {
+		return nil, fmt.Errorf("invalid follow-up response from Gemini: %w", err)
+	}
+	if strings.TrimSpace(email.Subject) == "" {
+		email.Subject = "Recap: " + title
+	}
+
+	return &email, nil
+}
+
+// Summarize uses Gemini to condense a single meeting into a short summary
+func (s *GeminiService) Summarize(title, notes, transcript string) (string, error) {
+	ctx := context.Background()
+
+	model := s.model("summarize")
+
+	prompt := fmt.Sprintf(`Summarize the meeting below in at most 5 sentences.
+
+Rules:
+- Cover the topics discussed, decisions made and open questions
+- Mention who owns follow-ups when that is stated
+- Don't add information that wasn't there
+- Return ONLY the summary, no explanations
+
+Meeting title: %s
+
+Notes:
+%s
+
+Transcript:
+%s`, title, notes, transcript)
+
+	return s.generate(ctx, "summarize", model, prompt)
+}
+
+type DigestContent struct {
+	Overview  string   `json:"overview"`
+	Themes    []string `json:"themes"`
+	Decisions []string `json:"decisions"`
+	OpenTasks []string `json:"open_tasks"`
+}
+
+// SynthesizeDigest uses Gemini to find themes, decisions and open tasks across several meeting summaries
+func (s *GeminiService) SynthesizeDigest(summaries []string, openTasks []string) (*DigestContent, error) {
+	ctx := context.Background()
+
+	model := s.model("synthesize-digest")
+	model.ResponseMIMEType = "application/json"
+
+	prompt := fmt.Sprintf(`You are writing a digest for a manager who did not attend the meetings below.
+
+Rules:
+- The overview is one paragraph describing the period as a whole
+- Themes are topics that came up in more than one meeting, or dominated a single one
+- Decisions are concrete outcomes, each mentioning the meeting it came from
+- Open tasks are follow-ups that are not reported as done, including the tracked open tasks listed below
+- Don't add information that wasn't there
+- Return JSON: {"overview": "...", "themes": ["..."], "decisions": ["..."], "open_tasks": ["..."]}
+
+Meeting summaries:
+%s
+
+Tracked open tasks:
+%s`, strings.Join(summaries, "\n\n"), strings.Join(openTasks, "\n"))
+
+	text, err := s.generate(ctx, "synthesize-digest", model, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	var digest DigestContent
+	if err := json.Unmarshal([]byte(text), &digest); err != nil {
+		return nil, fmt.Errorf("invalid digest response from Gemini: %w", err)
+	}
+
+	return &digest, nil
+}
+
+type SectionSentiment struct {
+	Section int     `json:"section"`
+	Label   string  `json:"label"` // positive, neutral, negative or mixed
+	Score   float64 `json:"score"` // -1 (negative) to 1 (positive)
+	Summary string  `json:"summary"`
+}
+
+var sectionSentimentSchema = &genai.Schema{
+	Type: genai.TypeArray,
+	Items: &genai.Schema{
+		Type: genai.TypeObject,
+		Properties: map[string]*genai.Schema{
+			"section": {Type: genai.TypeInteger, Description: "The section number as given in the input"},
+			"label":   {Type: genai.TypeString, Format: "enum", Enum: []string{"positive", "neutral", "negative", "mixed"}},
+			"score":   {Type: genai.TypeNumber, Description: "Overall tone from -1 (negative) to 1 (positive)"},
+			"summary": {Type: genai.TypeString, Description: "One short sentence explaining the tone"},
+		},
+		Required: []string{"section", "label", "score", "summary"},
+	},
+}
+
+// AnalyzeSentiment uses Gemini to rate the tone of each numbered section of a conversation
+func (s *GeminiService) AnalyzeSentiment(sections []string) ([]SectionSentiment, error) {
+	ctx := context.Background()
+
+	model := s.model("analyze-sentiment")
+	model.ResponseMIMEType = "application/json"
+	model.ResponseSchema = sectionSentimentSchema
+
+	var input strings.Builder
+	for i, section := range sections {
+		fmt.Fprintf(&input, "### Section %d\n%s\n\n", i+1, section)
+	}
+
+	prompt := fmt.Sprintf(`Rate the tone of each section of the meeting conversation below.
 
-	resp, err := model.GenerateContent(ctx, genai.Text(prompt))
+Rules:
+- Return exactly one entry per section, numbered as in the input
+- Judge the tone of the discussion, not the topic (a calm discussion of an outage is neutral)
+- Use mixed when speakers clearly disagree in tone
+
+Conversation:
+%s`, input.String())
+
+	raw, err := s.generate(ctx, "analyze-sentiment", model, prompt)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
-		return nil, fmt.Errorf("no response from Gemini")
+	var sentiments []SectionSentiment
+	if err := json.Unmarshal([]byte(raw), &sentiments); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidModelOutput, err)
+	}
+
+	for i := range sentiments {
+		if sentiments[i].Score < -1 || sentiments[i].Score > 1 {
+			return nil, fmt.Errorf("%w: section %d has score %v outside -1 to 1", ErrInvalidModelOutput, sentiments[i].Section, sentiments[i].Score)
+		}
 	}
 
-    var resultBuilder strings.Builder
-    for _, part := range resp.Candidates[0].Content.Parts {
-        if txt, ok := part.(genai.Text); ok {
-            resultBuilder.WriteString(string(txt))
-        }
-    }
+	return sentiments, nil
+}
+
+// MeetingClassification is a suggested meeting type and set of tags
+type MeetingClassification struct {
+	MeetingType string   `json:"meeting_type"`
+	Tags        []string `json:"tags"`
+}
+
+// maxSuggestedTags caps how many tags are suggested for one meeting
+const maxSuggestedTags = 5
+
+var meetingClassificationSchema = &genai.Schema{
+	Type: genai.TypeObject,
+	Properties: map[string]*genai.Schema{
+		"meeting_type": {Type: genai.TypeString, Format: "enum", Enum: MeetingTypes},
+		"tags": {
+			Type:        genai.TypeArray,
+			Items:       &genai.Schema{Type: genai.TypeString},
+			Description: "Short lowercase topic tags",
+		},
+	},
+	Required: []string{"meeting_type", "tags"},
+}
+
+// ClassifyMeeting uses Gemini to suggest a meeting type and topic tags for a
+// transcript, preferring tags that are already in use
+func (s *GeminiService) ClassifyMeeting(transcript string, existingTags []string) (*MeetingClassification, error) {
+	ctx := context.Background()
+
+	model := s.model("classify-meeting")
+	model.ResponseMIMEType = "application/json"
+	model.ResponseSchema = meetingClassificationSchema
+
+	transcript = truncateRunes(transcript, maxTitleInputChars)
+
+	existing := "(none yet)"
+	if len(existingTags) > 0 {
+		existing = strings.Join(existingTags, ", ")
+	}
+
+	prompt := fmt.Sprintf(`Classify the meeting transcript below.
+
+Rules:
+- meeting_type is the kind of meeting: %s
+- Suggest at most %d tags naming the topics, projects or customers discussed
+- Reuse an existing tag whenever one fits instead of inventing a near-duplicate
+- Tags are lowercase, one to three words, without the # sign
+- Do not use the meeting type as a tag
+
+Existing tags: %s
+
+Transcript:
+%s`, strings.Join(MeetingTypes, ", "), maxSuggestedTags, existing, transcript)
+
+	raw, err := s.generate(ctx, "classify-meeting", model, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	var classification MeetingClassification
+	if err := json.Unmarshal([]byte(raw), &classification); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidModelOutput, err)
+	}
 
-	// Parse tasks into array
-	tasks := []string{}
-	lines := strings.Split(resultBuilder.String(), "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "- ") {
-			tasks = append(tasks, strings.TrimPrefix(line, "- "))
+	if !contains(MeetingTypes, classification.MeetingType) {
+		return nil, fmt.Errorf("%w: unknown meeting type %q", ErrInvalidModelOutput, classification.MeetingType)
+	}
+	if len(classification.Tags) > maxSuggestedTags {
+		classification.Tags = classification.Tags[:maxSuggestedTags]
+	}
+
+	return &classification, nil
+}
+
+// FilledSection is what auto-fill wrote for a template section
+type FilledSection struct {
+	Name  string   `json:"name"`
+	Items []string `json:"items"`
+}
+
+var filledSectionsSchema = &genai.Schema{
+	Type: genai.TypeArray,
+	Items: &genai.Schema{
+		Type: genai.TypeObject,
+		Properties: map[string]*genai.Schema{
+			"name": {Type: genai.TypeString, Description: "The section name, exactly as given"},
+			"items": {
+				Type:        genai.TypeArray,
+				Items:       &genai.Schema{Type: genai.TypeString},
+				Description: "Bullet points for the section, empty if the transcript doesn't cover it",
+			},
+		},
+		Required: []string{"name", "items"},
+	},
+}
+
+// FillTemplateSections uses Gemini to write bullet points for the sections
+// of a templated meeting's notes from its transcript. instructions are
+// optional extra rules, e.g. the template's prompt.
+func (s *GeminiService) FillTemplateSections(title, transcript string, agenda []string, sections []TemplateSection, instructions string) ([]FilledSection, error) {
+	ctx := context.Background()
+
+	model := s.model("fill-template")
+	model.ResponseMIMEType = "application/json"
+	model.ResponseSchema = filledSectionsSchema
+
+	var list strings.Builder
+	for _, section := range sections {
+		list.WriteString("- " + section.Name)
+		if section.Hint != "" {
+			list.WriteString(": " + section.Hint)
 		}
+		list.WriteString("\n")
+	}
+
+	agendaText := "(none)"
+	if len(agenda) > 0 {
+		agendaText = "- " + strings.Join(agenda, "\n- ")
 	}
 
-	return tasks, nil
-}
\ No newline at end of file
+	prompt := fmt.Sprintf(`Fill in the sections of the meeting notes below from the transcript.
+
+Rules:
+- Return one entry per section, using the section name exactly as given
+- Write short bullet points in the language of the transcript, without bullet characters
+- Only use what was said in the transcript; leave items empty when a section isn't covered
+- Name who said or owns something when the transcript makes it clear
+%s
+Meeting title: %s
+
+Agenda:
+%s
+
+Sections:
+%s
+Transcript:
+%s`, extraInstructions(instructions), title, agendaText, list.String(), transcript)
+
+	raw, err := s.generate(ctx, "fill-template", model, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	var filled []FilledSection
+	if err := json.Unmarshal([]byte(raw), &filled); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidModelOutput, err)
+	}
+
+	for i := range filled {
+		items := []string{}
+		for _, item := range filled[i].Items {
+			item = strings.TrimSpace(item)
+			for _, bullet := range []string{"- ", "* ", "• "} {
+				item = strings.TrimPrefix(item, bullet)
+			}
+			if item != "" {
+				items = append(items, item)
+			}
+		}
+		filled[i].Items = items
+	}
+	return filled, nil
+}
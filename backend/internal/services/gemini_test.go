@@ -0,0 +1,70 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseExtractedTasks(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want []ExtractedTask
+	}{
+		{
+			name: "valid",
+			raw:  `[{"content":" Send the deck ","assignee":"Ana","due_date":"2024-03-01","priority":"High","confidence":0.9,"quote":"Ana sends the deck"}]`,
+			want: []ExtractedTask{{Content: "Send the deck", Assignee: "Ana", DueDate: "2024-03-01", Priority: "high", Confidence: 0.9, Quote: "Ana sends the deck"}},
+		},
+		{
+			name: "confidence as a string",
+			raw:  `[{"content":"Book the room","confidence":"0.8"}]`,
+			want: []ExtractedTask{{Content: "Book the room", Priority: "medium", Confidence: 0.8}},
+		},
+		{
+			name: "wrong-typed fields are dropped",
+			raw:  `[{"content":"Book the room","assignee":["Ana"],"due_date":20240301,"priority":3,"confidence":"sure","quote":null}]`,
+			want: []ExtractedTask{{Content: "Book the room", Priority: "medium"}},
+		},
+		{
+			name: "invalid due date",
+			raw:  `[{"content":"Book the room","due_date":"next Friday"}]`,
+			want: []ExtractedTask{{Content: "Book the room", Priority: "medium"}},
+		},
+		{
+			name: "confidence is clamped",
+			raw:  `[{"content":"A","confidence":1.7},{"content":"B","confidence":-0.2}]`,
+			want: []ExtractedTask{{Content: "A", Priority: "medium", Confidence: 1}, {Content: "B", Priority: "medium"}},
+		},
+		{
+			name: "empty and malformed tasks are skipped",
+			raw:  `[{"content":"  "},{"assignee":"Ana"},"Call Bob",null,{"content":42},{"content":"Call Bob"}]`,
+			want: []ExtractedTask{{Content: "Call Bob", Priority: "medium"}},
+		},
+		{
+			name: "no tasks",
+			raw:  `[]`,
+			want: []ExtractedTask{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseExtractedTasks(tt.raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseExtractedTasksInvalid(t *testing.T) {
+	for _, raw := range []string{``, `{"content":"A"}`, `[{"content":"A"}`, `Here are the tasks: []`} {
+		if _, err := parseExtractedTasks(raw); !errors.Is(err, ErrInvalidModelOutput) {
+			t.Errorf("%q: expected ErrInvalidModelOutput, got %v", raw, err)
+		}
+	}
+}
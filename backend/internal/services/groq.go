@@ -8,27 +8,59 @@ import (
 	"mime/multipart"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 )
 
 type TranscriptionService struct {
-	APIKey string
+	APIKey   string
+	redactor *RedactionService
+	settings *AISettingsService
+
+	// workspace picks the redaction policy, DefaultWorkspace when empty
+	workspace string
 }
 
 type groqResponse struct {
 	Text string `json:"text"`
 }
 
-func NewTranscriptionService(apiKey string) *TranscriptionService {
-	return &TranscriptionService{APIKey: apiKey}
+type groqModelsResponse struct {
+	Data []struct {
+		ID            string `json:"id"`
+		OwnedBy       string `json:"owned_by"`
+		Active        bool   `json:"active"`
+		ContextWindow int32  `json:"context_window"`
+	} `json:"data"`
+}
+
+// NewTranscriptionService creates the Groq client. The glossary is passed
+// through the redactor before it leaves the server.
+func NewTranscriptionService(apiKey string, redactor *RedactionService, settings *AISettingsService) *TranscriptionService {
+	return &TranscriptionService{APIKey: apiKey, redactor: redactor, settings: settings}
+}
+
+// ForWorkspace returns a service whose glossaries follow the redaction policy
+// of workspace, e.g. the workspace of the meeting being recorded
+func (s *TranscriptionService) ForWorkspace(workspace string) *TranscriptionService {
+	scoped := *s
+	scoped.workspace = workspace
+	return &scoped
 }
 
 // --------------------
 // PUBLIC ENTRY POINT
 // --------------------
-func (s *TranscriptionService) TranscribeFile(filePath string) (string, error) {
-	text, err := s.callWhisper(filePath)
+// glossary lists names and terms that Whisper should spell as given, and
+// language is the ISO 639 code of the spoken language; either may be empty
+func (s *TranscriptionService) TranscribeFile(filePath, glossary, language string) (string, error) {
+	glossary, err := s.redactGlossary(glossary)
+	if err != nil {
+		return "", fmt.Errorf("redaction failed: %w", err)
+	}
+
+	text, err := s.callWhisper(filePath, glossary, language)
 	if err != nil {
 		return "", err
 	}
@@ -43,10 +75,49 @@ func (s *TranscriptionService) TranscribeFile(filePath string) (string, error) {
 	return text, nil
 }
 
+// redactGlossary drops the glossary entries the workspace's redaction policy
+// would redact. A placeholder can't teach Whisper a spelling, so the entry is
+// left out rather than sent as [NAME_1].
+func (s *TranscriptionService) redactGlossary(glossary string) (string, error) {
+	if strings.TrimSpace(glossary) == "" {
+		return "", nil
+	}
+
+	workspace := s.workspace
+	if workspace == "" {
+		workspace = DefaultWorkspace
+	}
+	redaction, err := s.redactor.Redact(workspace, "transcribe", glossary)
+	if err != nil {
+		return "", err
+	}
+	if err := s.redactor.SaveReport(&redaction.Report); err != nil {
+		fmt.Printf("⚠️  Failed to save redaction report: %v\n", err)
+	}
+
+	var kept []string
+	for _, entry := range strings.Split(redaction.Text, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry != "" && !containsPlaceholder(entry, redaction.Report.Placeholders) {
+			kept = append(kept, entry)
+		}
+	}
+	return strings.Join(kept, ", "), nil
+}
+
+func containsPlaceholder(text string, placeholders []string) bool {
+	for _, p := range placeholders {
+		if strings.Contains(text, p) {
+			return true
+		}
+	}
+	return false
+}
+
 // --------------------
 // WHISPER CALL (GROQ)
 // --------------------
-func (s *TranscriptionService) callWhisper(filePath string) (string, error) {
+func (s *TranscriptionService) callWhisper(filePath, glossary, language string) (string, error) {
 	url := "https://api.groq.com/openai/v1/audio/transcriptions"
 
 	body := &bytes.Buffer{}
@@ -67,8 +138,16 @@ func (s *TranscriptionService) callWhisper(filePath string) (string, error) {
 	}
 
 	// Groq-supported params ONLY
-	writer.WriteField("model", "whisper-large-v3")
-	writer.WriteField("temperature", "0")
+	settings, _ := s.settings.Get("transcribe")
+	writer.WriteField("model", settings.Model)
+	writer.WriteField("temperature", strconv.FormatFloat(float64(settings.Temperature), 'f', -1, 32))
+	if glossary = strings.TrimSpace(glossary); glossary != "" {
+		// Whisper treats the prompt as preceding text, so listed terms bias its spelling
+		writer.WriteField("prompt", "Glossary: "+glossary+".")
+	}
+	if language != "" {
+		writer.WriteField("language", language)
+	}
 
 	writer.Close()
 
@@ -110,6 +189,43 @@ func (s *TranscriptionService) callWhisper(filePath string) (string, error) {
 	return result.Text, nil
 }
 
+// --------------------
+// MODEL CATALOG
+// --------------------
+func (s *TranscriptionService) ListModels() ([]ModelInfo, error) {
+	req, err := http.NewRequest("GET", "https://api.groq.com/openai/v1/models", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.APIKey)
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("groq models request failed: %s", resp.Status)
+	}
+
+	var result groqModelsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	models := []ModelInfo{}
+	for _, m := range result.Data {
+		if !m.Active {
+			continue
+		}
+		models = append(models, ModelInfo{Name: m.ID, InputTokenLimit: m.ContextWindow})
+	}
+
+	return models, nil
+}
+
 // --------------------
 // HALLUCINATION FILTER
 // --------------------
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"backend/internal/database"
+	"path/filepath"
+	"testing"
+)
+
+func TestRedactGlossary(t *testing.T) {
+	db, err := database.OpenSQLite(filepath.Join(t.TempDir(), "echo.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	redactor := NewRedactionService(db)
+	if _, err := redactor.AddTerm("acme", "Ana Lima"); err != nil {
+		t.Fatal(err)
+	}
+	if err := redactor.SetPolicy(&RedactionPolicy{Workspace: "open", Enabled: false}); err != nil {
+		t.Fatal(err)
+	}
+	s := NewTranscriptionService("", redactor, nil)
+
+	tests := []struct {
+		workspace string
+		glossary  string
+		want      string
+	}{
+		{"acme", "Kubernetes, Ana Lima, ana@acme.com, OKR", "Kubernetes, OKR"},
+		{"acme", "Ana Lima", ""},
+		{"acme", "  ", ""},
+		{"other", "Kubernetes, Ana Lima, ana@acme.com", "Kubernetes, Ana Lima"},
+		{"open", "Kubernetes, Ana Lima, ana@acme.com", "Kubernetes, Ana Lima, ana@acme.com"},
+	}
+	for _, tt := range tests {
+		got, err := s.ForWorkspace(tt.workspace).redactGlossary(tt.glossary)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("%s %q: expected %q, got %q", tt.workspace, tt.glossary, tt.want, got)
+		}
+	}
+
+	reports, err := redactor.GetReports("acme", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 2 || reports[0].Action != "transcribe" {
+		t.Errorf("expected a transcribe report per redacted glossary, got %+v", reports)
+	}
+}
@@ -0,0 +1,703 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCalendar is returned for data that isn't an iCalendar (RFC 5545) file
+var ErrInvalidCalendar = errors.New("invalid iCalendar data")
+
+const (
+	icalDateFormat     = "20060102"
+	icalDateTimeFormat = "20060102T150405"
+
+	// maxRecurrencePeriods bounds how many periods of a rule are walked, so
+	// open ended rules starting long ago stay cheap
+	maxRecurrencePeriods = 5000
+)
+
+// CalendarAttendee is an attendee or the organizer of a calendar event
+type CalendarAttendee struct {
+	Name  string
+	Email string
+}
+
+// CalendarEvent is one VEVENT. Recurring events carry their rule and
+// exceptions; overrides of single occurrences have RecurrenceID set.
+type CalendarEvent struct {
+	UID          string
+	Summary      string
+	Description  string
+	Start        time.Time
+	End          time.Time
+	AllDay       bool
+	Cancelled    bool
+	RRule        string
+	ExDates      []time.Time
+	RecurrenceID *time.Time
+	Organizer    *CalendarAttendee
+	Attendees    []CalendarAttendee
+
+	duration *time.Duration // DURATION, resolved into End against DTSTART
+}
+
+// CalendarOccurrence is a single occurrence of an event. Key is the event's
+// UID, followed by the original start for occurrences of recurring events.
+type CalendarOccurrence struct {
+	Key string
+	CalendarEvent
+}
+
+// icalProperty is one content line: NAME;PARAM=value:VALUE
+type icalProperty struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// ParseCalendar reads the VEVENTs of an iCalendar file. Alarms and other
+// nested components are ignored.
+func ParseCalendar(data []byte) ([]CalendarEvent, error) {
+	lines := unfoldICalLines(data)
+	if len(lines) == 0 || !strings.EqualFold(strings.TrimSpace(lines[0]), "BEGIN:VCALENDAR") {
+		return nil, fmt.Errorf("%w: missing BEGIN:VCALENDAR", ErrInvalidCalendar)
+	}
+
+	var events []CalendarEvent
+	var stack []string
+	var current *CalendarEvent
+	for n, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		prop, err := parseICalLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidCalendar, n+1, err)
+		}
+
+		switch prop.Name {
+		case "BEGIN":
+			stack = append(stack, strings.ToUpper(prop.Value))
+			if strings.EqualFold(prop.Value, "VEVENT") {
+				current = &CalendarEvent{}
+			}
+			continue
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1] != strings.ToUpper(prop.Value) {
+				return nil, fmt.Errorf("%w: line %d: unexpected END:%s", ErrInvalidCalendar, n+1, prop.Value)
+			}
+			stack = stack[:len(stack)-1]
+			if strings.EqualFold(prop.Value, "VEVENT") && current != nil {
+				if err := finishEvent(current); err != nil {
+					return nil, fmt.Errorf("%w: event ending on line %d: %v", ErrInvalidCalendar, n+1, err)
+				}
+				events = append(events, *current)
+				current = nil
+			}
+			continue
+		}
+
+		// Only properties of the event itself, not of its alarms
+		if current == nil || stack[len(stack)-1] != "VEVENT" {
+			continue
+		}
+		if err := applyEventProperty(current, prop); err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidCalendar, n+1, err)
+		}
+	}
+	if len(stack) != 0 {
+		return nil, fmt.Errorf("%w: missing END:%s", ErrInvalidCalendar, stack[len(stack)-1])
+	}
+
+	return events, nil
+}
+
+// unfoldICalLines splits data into content lines, joining folded continuations
+func unfoldICalLines(data []byte) []string {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// parseICalLine splits a content line into its name, parameters and value.
+// Parameter values may be quoted to contain ';' and ':'.
+func parseICalLine(line string) (icalProperty, error) {
+	prop := icalProperty{Params: map[string]string{}}
+
+	i := strings.IndexAny(line, ";:")
+	if i <= 0 {
+		return prop, fmt.Errorf("malformed line %q", line)
+	}
+	prop.Name = strings.ToUpper(line[:i])
+
+	for line[i] == ';' {
+		rest := line[i+1:]
+		eq := strings.IndexByte(rest, '=')
+		if eq <= 0 {
+			return prop, fmt.Errorf("malformed parameter in %s", prop.Name)
+		}
+		name := strings.ToUpper(rest[:eq])
+		rest = rest[eq+1:]
+		i += 1 + eq + 1
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.IndexByte(rest[1:], '"')
+			if end < 0 {
+				return prop, fmt.Errorf("unterminated quote in %s", prop.Name)
+			}
+			value = rest[1 : end+1]
+			i += end + 2
+		} else {
+			end := strings.IndexAny(rest, ";:")
+			if end < 0 {
+				return prop, fmt.Errorf("missing value in %s", prop.Name)
+			}
+			value = rest[:end]
+			i += end
+		}
+		prop.Params[name] = value
+
+		if i >= len(line) {
+			return prop, fmt.Errorf("missing value in %s", prop.Name)
+		}
+	}
+
+	prop.Value = line[i+1:]
+	return prop, nil
+}
+
+func applyEventProperty(event *CalendarEvent, prop icalProperty) error {
+	var err error
+	switch prop.Name {
+	case "UID":
+		event.UID = prop.Value
+	case "SUMMARY":
+		event.Summary = unescapeICalText(prop.Value)
+	case "DESCRIPTION":
+		event.Description = unescapeICalText(prop.Value)
+	case "STATUS":
+		event.Cancelled = strings.EqualFold(prop.Value, "CANCELLED")
+	case "DTSTART":
+		event.Start, event.AllDay, err = parseICalTime(prop)
+	case "DTEND":
+		event.End, _, err = parseICalTime(prop)
+	case "DURATION":
+		var d time.Duration
+		if d, err = parseICalDuration(prop.Value); err == nil {
+			event.duration = &d
+		}
+	case "RRULE":
+		event.RRule = prop.Value
+	case "EXDATE":
+		for _, value := range strings.Split(prop.Value, ",") {
+			t, _, err := parseICalTime(icalProperty{Params: prop.Params, Value: value})
+			if err != nil {
+				return err
+			}
+			event.ExDates = append(event.ExDates, t)
+		}
+	case "RECURRENCE-ID":
+		var t time.Time
+		if t, _, err = parseICalTime(prop); err == nil {
+			event.RecurrenceID = &t
+		}
+	case "ORGANIZER":
+		organizer := parseICalAttendee(prop)
+		event.Organizer = &organizer
+	case "ATTENDEE":
+		// Rooms, resources and declined invitations aren't people in the meeting
+		if cuType := strings.ToUpper(prop.Params["CUTYPE"]); cuType == "ROOM" || cuType == "RESOURCE" {
+			return nil
+		}
+		if strings.EqualFold(prop.Params["PARTSTAT"], "DECLINED") || strings.EqualFold(prop.Params["ROLE"], "NON-PARTICIPANT") {
+			return nil
+		}
+		event.Attendees = append(event.Attendees, parseICalAttendee(prop))
+	}
+	return err
+}
+
+// finishEvent checks an event's required fields and resolves its end
+func finishEvent(event *CalendarEvent) error {
+	if event.UID == "" {
+		return fmt.Errorf("missing UID")
+	}
+	if event.Start.IsZero() {
+		return fmt.Errorf("missing DTSTART")
+	}
+
+	switch {
+	case event.End.IsZero() && event.duration != nil:
+		event.End = event.Start.Add(*event.duration)
+	case event.End.IsZero() && event.AllDay:
+		event.End = event.Start.AddDate(0, 0, 1)
+	case event.End.IsZero() || event.End.Before(event.Start):
+		event.End = event.Start
+	}
+	return nil
+}
+
+// parseICalTime parses a DATE or DATE-TIME value. UTC times end in Z, others
+// use their TZID, and floating times the server's local time zone. It also
+// reports whether the value was a plain date.
+func parseICalTime(prop icalProperty) (time.Time, bool, error) {
+	value := strings.TrimSpace(prop.Value)
+
+	loc := time.Local
+	if tzid := prop.Params["TZID"]; tzid != "" {
+		// Unknown zones, such as Windows zone names, fall back to local time
+		if l, err := time.LoadLocation(strings.Trim(tzid, "/")); err == nil {
+			loc = l
+		}
+	}
+
+	if strings.EqualFold(prop.Params["VALUE"], "DATE") || len(value) == len(icalDateFormat) {
+		t, err := time.ParseInLocation(icalDateFormat, value, time.Local)
+		if err != nil {
+			return t, false, fmt.Errorf("invalid date %q", value)
+		}
+		return t, true, nil
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		loc = time.UTC
+		value = strings.TrimSuffix(value, "Z")
+	}
+	t, err := time.ParseInLocation(icalDateTimeFormat, value, loc)
+	if err != nil {
+		return t, false, fmt.Errorf("invalid date-time %q", prop.Value)
+	}
+	return t, false, nil
+}
+
+// parseICalDuration parses durations such as PT1H30M, P1D or -P1W
+func parseICalDuration(value string) (time.Duration, error) {
+	invalid := fmt.Errorf("invalid duration %q", value)
+
+	sign := time.Duration(1)
+	switch {
+	case strings.HasPrefix(value, "-"):
+		sign = -1
+		value = value[1:]
+	case strings.HasPrefix(value, "+"):
+		value = value[1:]
+	}
+	if !strings.HasPrefix(value, "P") || len(value) < 3 {
+		return 0, invalid
+	}
+
+	var total time.Duration
+	inTime := false
+	number := ""
+	for _, r := range value[1:] {
+		switch {
+		case r >= '0' && r <= '9':
+			number += string(r)
+			continue
+		case r == 'T':
+			inTime = true
+			continue
+		}
+
+		n, err := strconv.Atoi(number)
+		if err != nil {
+			return 0, invalid
+		}
+		number = ""
+
+		unit := map[rune]time.Duration{'W': 7 * 24 * time.Hour, 'D': 24 * time.Hour}
+		if inTime {
+			unit = map[rune]time.Duration{'H': time.Hour, 'M': time.Minute, 'S': time.Second}
+		}
+		u, ok := unit[r]
+		if !ok {
+			return 0, invalid
+		}
+		total += time.Duration(n) * u
+	}
+	if number != "" {
+		return 0, invalid
+	}
+
+	return sign * total, nil
+}
+
+// parseICalAttendee reads the name and email of an ATTENDEE or ORGANIZER
+func parseICalAttendee(prop icalProperty) CalendarAttendee {
+	attendee := CalendarAttendee{Name: strings.TrimSpace(prop.Params["CN"])}
+	if address := strings.TrimSpace(prop.Value); len(address) > len("mailto:") && strings.EqualFold(address[:len("mailto:")], "mailto:") {
+		attendee.Email = address[len("mailto:"):]
+	}
+	if attendee.Name == "" || strings.EqualFold(attendee.Name, attendee.Email) {
+		attendee.Name = attendee.Email
+	}
+	return attendee
+}
+
+func unescapeICalText(value string) string {
+	return strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(value)
+}
+
+// ExpandCalendar returns the occurrences of events that overlap [from, to),
+// ordered by start. Recurring events are expanded with their exceptions and
+// overridden occurrences applied; cancelled occurrences are included with
+// Cancelled set, so callers can remove meetings created for them.
+func ExpandCalendar(events []CalendarEvent, from, to time.Time) ([]CalendarOccurrence, error) {
+	overrides := map[string]CalendarEvent{}
+	for _, event := range events {
+		if event.RecurrenceID != nil {
+			overrides[occurrenceKey(event.UID, *event.RecurrenceID)] = event
+		}
+	}
+
+	var occurrences []CalendarOccurrence
+	add := func(key string, event CalendarEvent) {
+		if event.Start.Before(to) && (event.End.After(from) || (event.End.Equal(event.Start) && !event.Start.Before(from))) {
+			occurrences = append(occurrences, CalendarOccurrence{Key: key, CalendarEvent: event})
+		}
+	}
+
+	for _, event := range events {
+		if event.RecurrenceID != nil {
+			continue
+		}
+		if event.RRule == "" {
+			add(event.UID, event)
+			continue
+		}
+
+		starts, err := expandRRule(event.RRule, event.Start, to)
+		if err != nil {
+			return nil, fmt.Errorf("%w: event %s: %v", ErrInvalidCalendar, event.UID, err)
+		}
+		duration := event.End.Sub(event.Start)
+		for _, start := range starts {
+			if excluded(event.ExDates, start) {
+				continue
+			}
+			key := occurrenceKey(event.UID, start)
+			occurrence, overridden := overrides[key]
+			if !overridden {
+				occurrence = event
+				occurrence.Start = start
+				occurrence.End = start.Add(duration)
+			}
+			occurrence.RRule = ""
+			occurrence.ExDates = nil
+			add(key, occurrence)
+			delete(overrides, key)
+		}
+	}
+
+	// Overrides that moved an occurrence from outside the window into it
+	for key, event := range overrides {
+		add(key, event)
+	}
+
+	sort.SliceStable(occurrences, func(i, j int) bool { return occurrences[i].Start.Before(occurrences[j].Start) })
+	return occurrences, nil
+}
+
+func occurrenceKey(uid string, start time.Time) string {
+	return uid + "/" + start.UTC().Format(icalDateTimeFormat) + "Z"
+}
+
+func excluded(dates []time.Time, start time.Time) bool {
+	for _, d := range dates {
+		if d.Equal(start) {
+			return true
+		}
+	}
+	return false
+}
+
+// recurrenceRule is the subset of RRULE (RFC 5545 3.3.10) that calendar
+// apps produce for meetings
+type recurrenceRule struct {
+	freq       string
+	interval   int
+	count      int
+	until      *time.Time
+	byDay      []weekdayNum
+	byMonthDay []int
+	byMonth    []int
+	bySetPos   []int
+}
+
+// weekdayNum is a BYDAY entry like MO, 2TU or -1FR. N is 0 for every such weekday.
+type weekdayNum struct {
+	N       int
+	Weekday time.Weekday
+}
+
+var icalWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+func parseRRule(value string) (*recurrenceRule, error) {
+	rule := &recurrenceRule{interval: 1}
+	for _, part := range strings.Split(value, ";") {
+		name, v, ok := strings.Cut(part, "=")
+		if !ok {
+			continue
+		}
+		var err error
+		switch strings.ToUpper(name) {
+		case "FREQ":
+			rule.freq = strings.ToUpper(v)
+		case "INTERVAL":
+			if rule.interval, err = strconv.Atoi(v); err == nil && rule.interval < 1 {
+				err = fmt.Errorf("interval must be positive")
+			}
+		case "COUNT":
+			rule.count, err = strconv.Atoi(v)
+		case "UNTIL":
+			var until time.Time
+			if until, _, err = parseICalTime(icalProperty{Value: v}); err == nil {
+				if len(v) == len(icalDateFormat) {
+					// A date includes the whole day
+					until = until.AddDate(0, 0, 1).Add(-time.Second)
+				}
+				rule.until = &until
+			}
+		case "BYDAY":
+			for _, day := range strings.Split(v, ",") {
+				day = strings.ToUpper(strings.TrimSpace(day))
+				if len(day) < 2 {
+					return nil, fmt.Errorf("invalid BYDAY %q", v)
+				}
+				weekday, ok := icalWeekdays[day[len(day)-2:]]
+				if !ok {
+					return nil, fmt.Errorf("invalid BYDAY %q", v)
+				}
+				n := 0
+				if prefix := day[:len(day)-2]; prefix != "" {
+					if n, err = strconv.Atoi(prefix); err != nil {
+						return nil, fmt.Errorf("invalid BYDAY %q", v)
+					}
+				}
+				rule.byDay = append(rule.byDay, weekdayNum{N: n, Weekday: weekday})
+			}
+		case "BYMONTHDAY":
+			rule.byMonthDay, err = parseRRuleInts(v)
+		case "BYMONTH":
+			rule.byMonth, err = parseRRuleInts(v)
+		case "BYSETPOS":
+			rule.bySetPos, err = parseRRuleInts(v)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s in RRULE", strings.ToUpper(name))
+		}
+	}
+
+	switch rule.freq {
+	case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+	default:
+		return nil, fmt.Errorf("unsupported RRULE frequency %q", rule.freq)
+	}
+	return rule, nil
+}
+
+func parseRRuleInts(value string) ([]int, error) {
+	var values []int
+	for _, v := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, n)
+	}
+	return values, nil
+}
+
+// expandRRule returns the starts of a recurring event's occurrences from
+// start up to before limit, honoring COUNT and UNTIL
+func expandRRule(value string, start, limit time.Time) ([]time.Time, error) {
+	rule, err := parseRRule(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var starts []time.Time
+	emitted := 0
+	for period := 0; period < maxRecurrencePeriods; period++ {
+		candidates := rule.candidates(start, period)
+		if len(rule.bySetPos) > 0 {
+			candidates = selectPositions(candidates, rule.bySetPos)
+		}
+
+		for _, t := range candidates {
+			if t.Before(start) {
+				continue
+			}
+			if (rule.until != nil && t.After(*rule.until)) || !t.Before(limit) {
+				return starts, nil
+			}
+			starts = append(starts, t)
+			emitted++
+			if rule.count > 0 && emitted >= rule.count {
+				return starts, nil
+			}
+		}
+	}
+	return starts, nil
+}
+
+// candidates lists the occurrences in the nth period of the rule, in order
+func (r *recurrenceRule) candidates(start time.Time, n int) []time.Time {
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+	}
+
+	var days []time.Time
+	switch r.freq {
+	case "DAILY":
+		day := at(start.Year(), start.Month(), start.Day()+n*r.interval)
+		if r.matchesWeekday(day) && r.matchesMonth(day.Month()) {
+			days = append(days, day)
+		}
+
+	case "WEEKLY":
+		// Weeks start on Monday
+		offset := (int(start.Weekday()) + 6) % 7
+		monday := at(start.Year(), start.Month(), start.Day()-offset+7*n*r.interval)
+		for i := 0; i < 7; i++ {
+			day := at(monday.Year(), monday.Month(), monday.Day()+i)
+			if len(r.byDay) == 0 && day.Weekday() != start.Weekday() {
+				continue
+			}
+			if r.matchesWeekday(day) && r.matchesMonth(day.Month()) {
+				days = append(days, day)
+			}
+		}
+
+	case "MONTHLY":
+		first := at(start.Year(), start.Month()+time.Month(n*r.interval), 1)
+		if r.matchesMonth(first.Month()) {
+			days = r.daysInMonth(first, start.Day())
+		}
+
+	case "YEARLY":
+		year := start.Year() + n*r.interval
+		months := r.byMonth
+		if len(months) == 0 {
+			months = []int{int(start.Month())}
+		}
+		sort.Ints(months)
+		for _, month := range months {
+			days = append(days, r.daysInMonth(at(year, time.Month(month), 1), start.Day())...)
+		}
+	}
+	return days
+}
+
+// daysInMonth selects the days of first's month by BYMONTHDAY and BYDAY,
+// defaulting to the start's day of the month
+func (r *recurrenceRule) daysInMonth(first time.Time, startDay int) []time.Time {
+	length := first.AddDate(0, 1, -1).Day()
+
+	var days []time.Time
+	for d := 1; d <= length; d++ {
+		day := first.AddDate(0, 0, d-1)
+
+		if len(r.byMonthDay) > 0 {
+			if !containsMonthDay(r.byMonthDay, d, length) {
+				continue
+			}
+		} else if len(r.byDay) == 0 && d != startDay {
+			continue
+		}
+
+		if len(r.byDay) > 0 && !r.matchesWeekdayInMonth(day, length) {
+			continue
+		}
+		days = append(days, day)
+	}
+	return days
+}
+
+func containsMonthDay(monthDays []int, day, length int) bool {
+	for _, md := range monthDays {
+		if md == day || (md < 0 && length+md+1 == day) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *recurrenceRule) matchesWeekday(day time.Time) bool {
+	if len(r.byDay) == 0 {
+		return true
+	}
+	for _, wd := range r.byDay {
+		if wd.Weekday == day.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesWeekdayInMonth checks BYDAY entries with ordinals, e.g. 2TU is the
+// second Tuesday and -1FR the last Friday of the month
+func (r *recurrenceRule) matchesWeekdayInMonth(day time.Time, length int) bool {
+	for _, wd := range r.byDay {
+		if wd.Weekday != day.Weekday() {
+			continue
+		}
+		switch {
+		case wd.N == 0:
+			return true
+		case wd.N > 0 && (day.Day()-1)/7+1 == wd.N:
+			return true
+		case wd.N < 0 && (length-day.Day())/7+1 == -wd.N:
+			return true
+		}
+	}
+	return false
+}
+
+func (r *recurrenceRule) matchesMonth(month time.Month) bool {
+	if len(r.byMonth) == 0 {
+		return true
+	}
+	for _, m := range r.byMonth {
+		if time.Month(m) == month {
+			return true
+		}
+	}
+	return false
+}
+
+// selectPositions applies BYSETPOS, e.g. -1 keeps the last candidate of the period
+func selectPositions(candidates []time.Time, positions []int) []time.Time {
+	var selected []time.Time
+	for i, t := range candidates {
+		for _, pos := range positions {
+			if pos == i+1 || (pos < 0 && len(candidates)+pos == i) {
+				selected = append(selected, t)
+				break
+			}
+		}
+	}
+	return selected
+}
@@ -0,0 +1,128 @@
+package services_test
+
+import (
+	"backend/internal/services"
+	"strings"
+	"testing"
+	"time"
+)
+
+// calendar wraps VEVENT property lines, one event per slice, into an iCalendar file
+func calendar(events ...[]string) []byte {
+	lines := []string{"BEGIN:VCALENDAR", "VERSION:2.0"}
+	for _, event := range events {
+		lines = append(lines, "BEGIN:VEVENT")
+		lines = append(lines, event...)
+		lines = append(lines, "END:VEVENT")
+	}
+	lines = append(lines, "END:VCALENDAR")
+	return []byte(strings.Join(lines, "\r\n") + "\r\n")
+}
+
+func utc(month time.Month, day, hour, minute int) time.Time {
+	return time.Date(2024, month, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestExpandCalendar(t *testing.T) {
+	local := func(day int) time.Time { return time.Date(2024, time.January, day, 9, 0, 0, 0, time.Local) }
+
+	tests := []struct {
+		name     string
+		events   [][]string
+		from, to time.Time
+		want     []time.Time
+	}{
+		{
+			name:   "last weekday of the month",
+			events: [][]string{{"UID:close", "DTSTART:20240131T100000Z", "DURATION:PT1H", "RRULE:FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1"}},
+			from:   utc(time.January, 1, 0, 0), to: utc(time.May, 1, 0, 0),
+			want: []time.Time{utc(time.January, 31, 10, 0), utc(time.February, 29, 10, 0), utc(time.March, 29, 10, 0), utc(time.April, 30, 10, 0)},
+		},
+		{
+			name:   "first monday of the month",
+			events: [][]string{{"UID:plan", "DTSTART:20240101T090000Z", "RRULE:FREQ=MONTHLY;BYDAY=MO;BYSETPOS=1"}},
+			from:   utc(time.January, 1, 0, 0), to: utc(time.April, 2, 0, 0),
+			want: []time.Time{utc(time.January, 1, 9, 0), utc(time.February, 5, 9, 0), utc(time.March, 4, 9, 0), utc(time.April, 1, 9, 0)},
+		},
+		{
+			name:   "until a date includes that day",
+			events: [][]string{{"UID:sprint", "DTSTART:20240101T090000", "RRULE:FREQ=DAILY;UNTIL=20240104"}},
+			from:   local(1), to: local(31),
+			want: []time.Time{local(1), local(2), local(3), local(4)},
+		},
+		{
+			name:   "until a time is inclusive",
+			events: [][]string{{"UID:sync", "DTSTART:20240101T090000Z", "RRULE:FREQ=WEEKLY;UNTIL=20240115T090000Z"}},
+			from:   utc(time.January, 1, 0, 0), to: utc(time.March, 1, 0, 0),
+			want: []time.Time{utc(time.January, 1, 9, 0), utc(time.January, 8, 9, 0), utc(time.January, 15, 9, 0)},
+		},
+		{
+			name:   "exdate",
+			events: [][]string{{"UID:standup", "DTSTART:20240101T090000Z", "RRULE:FREQ=DAILY;COUNT=5", "EXDATE:20240103T090000Z,20240104T090000Z"}},
+			from:   utc(time.January, 1, 0, 0), to: utc(time.February, 1, 0, 0),
+			want: []time.Time{utc(time.January, 1, 9, 0), utc(time.January, 2, 9, 0), utc(time.January, 5, 9, 0)},
+		},
+		{
+			name: "overrides moved into and out of the window",
+			events: [][]string{
+				{"UID:1on1", "DTSTART:20240101T090000Z", "DTEND:20240101T093000Z", "RRULE:FREQ=WEEKLY"},
+				{"UID:1on1", "RECURRENCE-ID:20240108T090000Z", "DTSTART:20240120T090000Z", "DTEND:20240120T093000Z"},
+				{"UID:1on1", "RECURRENCE-ID:20240115T090000Z", "DTSTART:20240112T150000Z", "DTEND:20240112T153000Z"},
+			},
+			from: utc(time.January, 8, 0, 0), to: utc(time.January, 14, 0, 0),
+			want: []time.Time{utc(time.January, 12, 15, 0)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := services.ParseCalendar(calendar(tt.events...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			occurrences, err := services.ExpandCalendar(events, tt.from, tt.to)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(occurrences) != len(tt.want) {
+				t.Fatalf("expected %d occurrences, got %d: %+v", len(tt.want), len(occurrences), occurrences)
+			}
+			for i, want := range tt.want {
+				if !occurrences[i].Start.Equal(want) {
+					t.Errorf("occurrence %d: expected %s, got %s", i, want, occurrences[i].Start)
+				}
+			}
+		})
+	}
+}
+
+func TestExpandCalendarOverrideKeys(t *testing.T) {
+	events, err := services.ParseCalendar(calendar(
+		[]string{"UID:review", "DTSTART:20240101T090000Z", "DURATION:PT1H", "RRULE:FREQ=WEEKLY;COUNT=3", "SUMMARY:Review"},
+		[]string{"UID:review", "RECURRENCE-ID:20240108T090000Z", "DTSTART:20240109T100000Z", "DURATION:PT1H", "SUMMARY:Review (moved)"},
+		[]string{"UID:review", "RECURRENCE-ID:20240115T090000Z", "DTSTART:20240115T090000Z", "STATUS:CANCELLED"},
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	occurrences, err := services.ExpandCalendar(events, utc(time.January, 1, 0, 0), utc(time.February, 1, 0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(occurrences) != 3 {
+		t.Fatalf("expected 3 occurrences, got %+v", occurrences)
+	}
+
+	// Keys follow the original start, so a moved meeting keeps its identity
+	moved := occurrences[1]
+	if moved.Key != "review/20240108T090000Z" || moved.Summary != "Review (moved)" || !moved.End.Equal(utc(time.January, 9, 11, 0)) {
+		t.Errorf("expected the moved occurrence under its original key, got %+v", moved)
+	}
+	if cancelled := occurrences[2]; cancelled.Key != "review/20240115T090000Z" || !cancelled.Cancelled {
+		t.Errorf("expected the last occurrence cancelled, got %+v", cancelled)
+	}
+	if occurrences[0].RRule != "" {
+		t.Errorf("expected occurrences without the rule, got %q", occurrences[0].RRule)
+	}
+}
@@ -0,0 +1,347 @@
+package services
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ImportAudioExtensions are the recording formats that can be attached to an import
+var ImportAudioExtensions = []string{".mp3", ".m4a", ".mp4", ".wav", ".webm", ".ogg", ".aac", ".flac"}
+
+// ErrUnsupportedAudio is returned for attached recordings of an unknown format
+var ErrUnsupportedAudio = errors.New("unsupported audio format")
+
+// ImportOptions adjust an import. Empty fields use what the file says.
+type ImportOptions struct {
+	Format   string     // One of ImportFormats, detected from the file when empty
+	Title    string     // Overrides the title found in the file
+	Date     *time.Time // Overrides the date found in the file
+	FolderID *int
+	Audio    io.Reader // Optional recording to attach
+	AudioExt string    // Extension of Audio, one of ImportAudioExtensions
+}
+
+// ImportResult describes an imported file. Duplicate is set, and nothing is
+// created, when the same file was imported before. Restored is also set when
+// that meeting was in the trash and has been restored.
+type ImportResult struct {
+	File         string    `json:"file"`
+	Format       string    `json:"format"`
+	MeetingID    int       `json:"meeting_id"`
+	Title        string    `json:"title"`
+	Date         time.Time `json:"date"`
+	Segments     int       `json:"segments"`
+	Participants []string  `json:"participants"`
+	HasAudio     bool      `json:"has_audio"`
+	Duplicate    bool      `json:"duplicate"`
+	Restored     bool      `json:"restored"`
+}
+
+type ImportService struct {
+	MeetingService MeetingRepository
+	FolderService  FolderRepository
+	StoragePath    string
+}
+
+func NewImportService(meetingService MeetingRepository, folderService FolderRepository, storagePath string) *ImportService {
+	return &ImportService{
+		MeetingService: meetingService,
+		FolderService:  folderService,
+		StoragePath:    storagePath,
+	}
+}
+
+// Import creates a finished meeting from another tool's transcript export,
+// with its timestamped segments and the named speakers as participants.
+func (s *ImportService) Import(filename string, data []byte, opts ImportOptions) (*ImportResult, error) {
+	format := opts.Format
+	if format == "" {
+		if format = DetectImportFormat(filename, data); format == "" {
+			return nil, fmt.Errorf("%w: unrecognized file type %s", ErrInvalidTranscript, filepath.Ext(filename))
+		}
+	}
+	if opts.Audio != nil && !contains(ImportAudioExtensions, strings.ToLower(opts.AudioExt)) {
+		return nil, fmt.Errorf("%w %q", ErrUnsupportedAudio, opts.AudioExt)
+	}
+
+	transcript, err := ParseTranscript(format, data)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(data)
+	key := "sha256:" + hex.EncodeToString(sum[:])
+	result := &ImportResult{File: filepath.Base(filename), Format: format, Segments: len(transcript.Segments), Participants: []string{}}
+
+	existing, err := s.MeetingService.GetByImportKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		// Importing a file again brings its meeting back from the trash
+		if existing, err = s.MeetingService.RestoreImported(key); err != nil {
+			return nil, err
+		}
+		result.Restored = existing != nil
+	}
+	if existing != nil {
+		result.MeetingID, result.Title, result.Date = existing.ID, existing.Title, existing.CreatedAt
+		result.Participants, result.HasAudio, result.Duplicate = existing.Participants, existing.AudioPath != "", true
+		return result, nil
+	}
+
+	nameTitle, nameDate := importNameDetails(filename)
+	result.Title = firstNonEmpty(strings.TrimSpace(opts.Title), transcript.Title, nameTitle, DefaultMeetingTitle)
+	result.Date = time.Now()
+	for _, date := range []*time.Time{opts.Date, transcript.Date, nameDate} {
+		if date != nil {
+			result.Date = *date
+			break
+		}
+	}
+
+	var defaults FolderDefaults
+	if opts.FolderID != nil {
+		if defaults, err = s.FolderService.EffectiveDefaults(*opts.FolderID); err != nil {
+			return nil, err
+		}
+	}
+
+	var endMs int64
+	var lines []string
+	for _, seg := range transcript.Segments {
+		endMs = max(endMs, seg.EndMs)
+		if realSpeaker(seg.Speaker) {
+			lines = append(lines, seg.Speaker+": "+seg.Text)
+		} else {
+			lines = append(lines, seg.Text)
+		}
+	}
+
+	meeting := Meeting{
+		Title:           result.Title,
+		Transcript:      strings.Join(lines, "\n"),
+		AutoTitle:       result.Title == DefaultMeetingTitle,
+		CreatedAt:       result.Date,
+		DurationSeconds: int((endMs + 999) / 1000),
+		FolderID:        opts.FolderID,
+		Glossary:        defaults.Glossary,
+		PromptTemplate:  defaults.PromptTemplate,
+		RetentionDays:   defaults.RetentionDays,
+		Workspace:       defaults.workspace(),
+	}
+
+	// The recording is named after the file rather than the meeting, so it
+	// can be saved before the meeting exists and removed if it isn't created
+	if opts.Audio != nil {
+		meeting.AudioPath = filepath.Join(s.StoragePath, "audio", "import_"+hex.EncodeToString(sum[:8])+strings.ToLower(opts.AudioExt))
+		if err := saveImportedAudio(meeting.AudioPath, opts.Audio); err != nil {
+			return nil, err
+		}
+	}
+
+	created, err := s.MeetingService.CreateImported(meeting, key, fmt.Sprintf("%s (%s)", result.File, format), transcript.Segments)
+	if err != nil {
+		if meeting.AudioPath != "" {
+			os.Remove(meeting.AudioPath)
+		}
+		return nil, err
+	}
+
+	result.MeetingID, result.Participants, result.HasAudio = created.ID, created.Participants, created.AudioPath != ""
+	return result, nil
+}
+
+// GetByImportKey returns the meeting imported from the file with this key,
+// or nil if there is none outside the trash
+func (s *MeetingService) GetByImportKey(key string) (*Meeting, error) {
+	var id int
+	err := s.db.QueryRow("SELECT id FROM meetings WHERE import_key = ? AND deleted_at IS NULL", key).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.GetByID(id)
+}
+
+// RestoreImported moves the meeting imported from the file with this key out
+// of the trash and returns it, or nil if there is none in the trash
+func (s *MeetingService) RestoreImported(key string) (*Meeting, error) {
+	var id int
+	err := s.db.QueryRow("UPDATE meetings SET deleted_at = NULL WHERE import_key = ? AND deleted_at IS NOT NULL RETURNING id", key).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.GetByID(id)
+}
+
+// CreateImported stores a finished meeting imported from the file with this
+// key, and its transcript segments, in one transaction. Named speakers become
+// participants.
+func (s *MeetingService) CreateImported(meeting Meeting, key, source string, segments []Segment) (*Meeting, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var id int
+	err = tx.QueryRow(`
+		INSERT INTO meetings (title, transcript, is_recording, status, auto_title, created_at, duration_seconds, audio_path,
+			import_key, import_source, folder_id, glossary, prompt_template, retention_days, workspace)
+		VALUES (?, ?, FALSE, 'finished', ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		RETURNING id
+	`, meeting.Title, meeting.Transcript, meeting.AutoTitle,
+		meeting.CreatedAt.UTC().Format("2006-01-02 15:04:05"), meeting.DurationSeconds, meeting.AudioPath,
+		key, source, meeting.FolderID, meeting.Glossary, meeting.PromptTemplate, meeting.RetentionDays, FolderDefaults{Workspace: meeting.Workspace}.workspace(),
+	).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := make([]Segment, len(segments))
+	for i, seg := range segments {
+		seg.MeetingID = id
+		if strings.TrimSpace(seg.Speaker) == "" {
+			seg.Speaker = UnknownSpeaker
+		}
+		err := tx.QueryRow(
+			"INSERT INTO transcript_segments (meeting_id, speaker, start_ms, end_ms, text) VALUES (?, ?, ?, ?, ?) RETURNING id",
+			seg.MeetingID, seg.Speaker, seg.StartMs, seg.EndMs, seg.Text,
+		).Scan(&seg.ID)
+		if err != nil {
+			return nil, err
+		}
+		stored[i] = seg
+	}
+
+	if err := addImportedParticipants(tx, id, stored); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	if err := indexMeeting(s.db, id); err != nil {
+		return nil, err
+	}
+	for i := range stored {
+		if err := indexSegment(s.db, &stored[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.GetByID(id)
+}
+
+// addImportedParticipants adds the speakers who are named, rather than
+// labelled like "Speaker 1", as participants mapped to their speaker label.
+// Speakers matching someone in the people directory are linked to them.
+func addImportedParticipants(tx *sql.Tx, meetingID int, segments []Segment) error {
+	names := []string{}
+	added := map[string]bool{}
+	for _, seg := range segments {
+		speaker := strings.TrimSpace(seg.Speaker)
+		if !realSpeaker(speaker) || added[strings.ToLower(speaker)] {
+			continue
+		}
+		added[strings.ToLower(speaker)] = true
+
+		name := speaker
+		personID, err := findPersonID(tx, speaker)
+		if err != nil {
+			return err
+		}
+		if personID != nil {
+			if err := tx.QueryRow("SELECT name FROM people WHERE id = ?", *personID).Scan(&name); err != nil {
+				return err
+			}
+			if added["person:"+name] {
+				continue
+			}
+			added["person:"+name] = true
+		}
+
+		if _, err := tx.Exec(
+			"INSERT INTO meeting_participants (meeting_id, person_id, name, speaker, position) VALUES (?, ?, ?, ?, ?)",
+			meetingID, personID, name, speaker, len(names),
+		); err != nil {
+			return err
+		}
+		names = append(names, name)
+	}
+	return nil
+}
+
+func saveImportedAudio(path string, audio io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, audio); err != nil {
+		f.Close()
+		os.Remove(path)
+		return fmt.Errorf("failed to save audio: %w", err)
+	}
+	return f.Close()
+}
+
+// firstNonEmpty returns the first of values that isn't empty
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// importTranscriptExtensions are the file types a batch import picks up
+var importTranscriptExtensions = []string{".vtt", ".srt", ".docx", ".txt"}
+
+// ImportableFile reports whether a batch import picks up the file at path
+func ImportableFile(path string) bool {
+	return contains(importTranscriptExtensions, strings.ToLower(filepath.Ext(path)))
+}
+
+// ImportFile imports the transcript at path. A recording next to it with the
+// same name, e.g. standup.m4a for standup.vtt or standup.transcript.vtt, is
+// attached unless opts already has audio.
+func (s *ImportService) ImportFile(path string, opts ImportOptions) (*ImportResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Audio == nil {
+		base := strings.TrimSuffix(strings.TrimSuffix(path, filepath.Ext(path)), ".transcript")
+		for _, ext := range ImportAudioExtensions {
+			f, err := os.Open(base + ext)
+			if err != nil {
+				continue
+			}
+			defer f.Close()
+			opts.Audio, opts.AudioExt = f, ext
+			break
+		}
+	}
+
+	return s.Import(path, data, opts)
+}
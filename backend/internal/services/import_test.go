@@ -0,0 +1,86 @@
+package services_test
+
+import (
+	"backend/internal/services"
+	"os"
+	"strings"
+	"testing"
+)
+
+const importVTT = "WEBVTT\n\n00:00:01.000 --> 00:00:04.500\nAna Souza: Let's start with the budget.\n\n" +
+	"00:00:05.000 --> 00:00:07.250\nBen: Agenda: budget first.\n\n00:00:08.000 --> 00:00:09.000\nAna Souza: Agreed.\n"
+
+func TestImport(t *testing.T) {
+	forEachRepository(t, func(t *testing.T, repos services.Repositories) {
+		s := services.NewImportService(repos.Meetings, repos.Folders, t.TempDir())
+
+		result, err := s.Import("Budget review.vtt", []byte(importVTT), services.ImportOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Duplicate || result.Title != "Budget review" || result.Segments != 3 || strings.Join(result.Participants, ",") != "Ana Souza,Ben" {
+			t.Errorf("unexpected import %+v", result)
+		}
+
+		m, err := repos.Meetings.GetByID(result.MeetingID)
+		if err != nil || m == nil {
+			t.Fatalf("expected the imported meeting, got %v, %v", m, err)
+		}
+		if m.Status != services.MeetingStatusFinished || m.IsRecording || m.DurationSeconds != 9 || !strings.HasPrefix(m.Transcript, "Ana Souza: Let's start") {
+			t.Errorf("unexpected imported meeting %+v", m)
+		}
+		if segments, err := repos.Segments.GetByMeeting(m.ID); err != nil || len(segments) != 3 || segments[1].Speaker != "Ben" {
+			t.Errorf("expected 3 stored segments, got %+v, %v", segments, err)
+		}
+
+		again, err := s.Import("copy.vtt", []byte(importVTT), services.ImportOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !again.Duplicate || again.Restored || again.MeetingID != m.ID {
+			t.Errorf("expected a duplicate of meeting %d, got %+v", m.ID, again)
+		}
+
+		// A file whose meeting is in the trash brings it back rather than
+		// pointing at a meeting that is about to be purged
+		if _, err := repos.Meetings.Trash(m.ID); err != nil {
+			t.Fatal(err)
+		}
+		restored, err := s.Import("copy.vtt", []byte(importVTT), services.ImportOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !restored.Duplicate || !restored.Restored || restored.MeetingID != m.ID || restored.Title != "Budget review" {
+			t.Errorf("expected meeting %d restored, got %+v", m.ID, restored)
+		}
+		if m, err := repos.Meetings.GetByID(m.ID); err != nil || m == nil {
+			t.Errorf("expected the meeting out of the trash, got %v, %v", m, err)
+		}
+	})
+}
+
+func TestImportAudio(t *testing.T) {
+	forEachRepository(t, func(t *testing.T, repos services.Repositories) {
+		s := services.NewImportService(repos.Meetings, repos.Folders, t.TempDir())
+
+		opts := services.ImportOptions{Audio: strings.NewReader("audio"), AudioExt: ".M4A"}
+		result, err := s.Import("call.vtt", []byte(importVTT), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m, err := repos.Meetings.GetByID(result.MeetingID)
+		if err != nil || m == nil {
+			t.Fatalf("expected the imported meeting, got %v, %v", m, err)
+		}
+		if !result.HasAudio || !strings.HasSuffix(m.AudioPath, ".m4a") {
+			t.Errorf("expected an m4a recording, got %+v and path %q", result, m.AudioPath)
+		}
+		if data, err := os.ReadFile(m.AudioPath); err != nil || string(data) != "audio" {
+			t.Errorf("expected the recording saved, got %q, %v", data, err)
+		}
+
+		if _, err := s.Import("other.vtt", []byte("WEBVTT\n\n00:01.000 --> 00:02.000\nhi\n"), services.ImportOptions{Audio: strings.NewReader(""), AudioExt: ".exe"}); err == nil {
+			t.Error("expected an unsupported audio format to fail")
+		}
+	})
+}
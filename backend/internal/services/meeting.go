@@ -3,55 +3,226 @@ package services
 import (
 	"backend/internal/database"
 	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 )
 
 type Meeting struct {
-	ID              int       `json:"id"`
-	Title           string    `json:"title"`
-	CreatedAt       time.Time `json:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at"`
-	Transcript      string    `json:"transcript"`
-	Notes           string    `json:"notes"`
-	AudioPath       string    `json:"audio_path"`
-	DurationSeconds int       `json:"duration_seconds"`
-	IsRecording     bool      `json:"is_recording"`
+	ID              int        `json:"id"`
+	Title           string     `json:"title"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	Transcript      string     `json:"transcript"`
+	Notes           string     `json:"notes"`
+	AudioPath       string     `json:"audio_path"`
+	DurationSeconds int        `json:"duration_seconds"`
+	IsRecording     bool       `json:"is_recording"`
+	Status          string     `json:"status"` // scheduled, recording or finished
+	ScheduledStart  *time.Time `json:"scheduled_start"`
+	ScheduledEnd    *time.Time `json:"scheduled_end"`
+	Description     string     `json:"description"`
+	AutoTitle       bool       `json:"auto_title"`
+	MeetingType     string     `json:"meeting_type"`
+	Tags            []string   `json:"tags"`
+	FolderID        *int       `json:"folder_id"`
+	TemplateID      *int       `json:"template_id"` // Template the notes were laid out from
+	Glossary        string     `json:"glossary"`
+	PromptTemplate  string     `json:"prompt_template"`
+	RetentionDays   int        `json:"retention_days"`
+	Language        string     `json:"language"`  // ISO 639 code, empty to auto-detect
+	Workspace       string     `json:"workspace"` // Whose redaction policy applies to AI calls
+	Participants    []string   `json:"participants"`
+	Version         int        `json:"version"` // Bumped by every edit, sent as the ETag
 }
 
-type MeetingService struct{}
+// MeetingSummary is the lightweight form of a meeting used by list views
+type MeetingSummary struct {
+	ID              int        `json:"id"`
+	Title           string     `json:"title"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	DurationSeconds int        `json:"duration_seconds"`
+	IsRecording     bool       `json:"is_recording"`
+	Status          string     `json:"status"`
+	ScheduledStart  *time.Time `json:"scheduled_start"`
+	HasAudio        bool       `json:"has_audio"`
+	MeetingType     string     `json:"meeting_type"`
+	Tags            []string   `json:"tags"`
+	FolderID        *int       `json:"folder_id"`
+	Snippet         string     `json:"snippet"`
+}
 
-func NewMeetingService() *MeetingService {
-	return &MeetingService{}
+// MeetingFilter narrows List; zero fields match every meeting
+type MeetingFilter struct {
+	Tag         string
+	Type        string
+	FolderIDs   []int      // Meetings in any of these folders
+	Unfiled     bool       // Only meetings outside any folder
+	From        *time.Time // Created at or after
+	To          *time.Time // Created before
+	IsRecording *bool
+	Status      string // One of MeetingStatuses
+	HasAudio    *bool
+	PersonID    int    // Meetings this person took part in
+	Sort        string // One of MeetingSortFields, defaults to created_at
+	Ascending   bool
+	Limit       int    // 0 lists every meeting
+	Cursor      string // NextCursor of the previous page
 }
 
-// Create creates a new meeting and returns its ID
-func (s *MeetingService) Create(title string) (*Meeting, error) {
-	result, err := database.DB.Exec(
-		"INSERT INTO meetings (title, is_recording) VALUES (?, TRUE)",
-		title,
-	)
+// MeetingPage is one page of List results. NextCursor is empty on the last page.
+type MeetingPage struct {
+	Meetings   []MeetingSummary `json:"meetings"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
+// Meeting statuses. Scheduled meetings come from a calendar and haven't been started yet.
+const (
+	MeetingStatusScheduled = "scheduled"
+	MeetingStatusRecording = "recording"
+	MeetingStatusFinished  = "finished"
+)
+
+// MeetingStatuses are the values accepted for MeetingFilter.Status
+var MeetingStatuses = []string{MeetingStatusScheduled, MeetingStatusRecording, MeetingStatusFinished}
+
+// ValidMeetingStatus reports whether status is one of MeetingStatuses
+func ValidMeetingStatus(status string) bool {
+	return contains(MeetingStatuses, status)
+}
+
+// ErrStillRecording is returned for transcript edits while live transcription is still appending to it
+var ErrStillRecording = errors.New("the meeting is still recording")
+
+// ErrVersionConflict is returned when an edit expected a version of the meeting that is no longer current
+var ErrVersionConflict = errors.New("the meeting was changed since it was loaded")
+
+// ErrInvalidCursor is returned for cursors that weren't issued by List with the same sort
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// MeetingSortFields maps the accepted sort names to their columns
+var MeetingSortFields = map[string]string{
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+	"title":      "title",
+	"duration":   "duration_seconds",
+}
+
+// snippetLength is the length of list snippets in characters
+const snippetLength = 160
+
+// meetingCursor is the keyset position after the last meeting of a page
+type meetingCursor struct {
+	Sort  string      `json:"s"`
+	Value interface{} `json:"v"`
+	ID    int         `json:"id"`
+}
+
+// DefaultMeetingTitle is the title given to meetings created without one
+const DefaultMeetingTitle = "Untitled Meeting"
+
+const meetingColumns = "id, title, created_at, updated_at, transcript, notes, audio_path, duration_seconds, is_recording, status, scheduled_start, scheduled_end, description, auto_title, meeting_type, folder_id, template_id, glossary, prompt_template, retention_days, language, workspace, version"
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanMeeting(row rowScanner) (*Meeting, error) {
+	var m Meeting
+	var createdAt, updatedAt timestamp
+	var folderID, templateID, scheduledStart, scheduledEnd sql.NullInt64
+	err := row.Scan(&m.ID, &m.Title, &createdAt, &updatedAt, &m.Transcript, &m.Notes, &m.AudioPath, &m.DurationSeconds, &m.IsRecording,
+		&m.Status, &scheduledStart, &scheduledEnd, &m.Description, &m.AutoTitle, &m.MeetingType,
+		&folderID, &templateID, &m.Glossary, &m.PromptTemplate, &m.RetentionDays, &m.Language, &m.Workspace, &m.Version)
 	if err != nil {
 		return nil, err
 	}
 
-	id, err := result.LastInsertId()
+	m.FolderID = nullableID(folderID)
+	m.TemplateID = nullableID(templateID)
+	m.ScheduledStart = nullableTime(scheduledStart)
+	m.ScheduledEnd = nullableTime(scheduledEnd)
+	m.CreatedAt = createdAt.Time
+	m.UpdatedAt = updatedAt.Time
+	return &m, nil
+}
+
+// timestamp scans a DATETIME or TIMESTAMPTZ column. Both drivers return
+// time.Time, SQLite values the driver can't convert come back as text in
+// SQLite's own format.
+type timestamp struct {
+	time.Time
+}
+
+func (t *timestamp) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case time.Time:
+		t.Time = v.UTC()
+		return nil
+	case string:
+		return t.parse(v)
+	case []byte:
+		return t.parse(string(v))
+	}
+	return fmt.Errorf("cannot scan %T into a timestamp", value)
+}
+
+func (t *timestamp) parse(value string) error {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05"} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			t.Time = parsed
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid timestamp %q", value)
+}
+
+// nullableTime converts a nullable unix timestamp column
+func nullableTime(value sql.NullInt64) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+	t := time.Unix(value.Int64, 0)
+	return &t
+}
+
+// MeetingService is the MeetingRepository stored in SQLite or PostgreSQL
+type MeetingService struct {
+	db      *sql.DB
+	dialect database.Dialect
+}
+
+func NewMeetingService(db *sql.DB, dialect database.Dialect) *MeetingService {
+	return &MeetingService{db: db, dialect: dialect}
+}
+
+// Create creates a new meeting in folderID (nil for the top level) with the given defaults
+func (s *MeetingService) Create(title string, autoTitle bool, folderID *int, defaults FolderDefaults) (*Meeting, error) {
+	var id int
+	err := s.db.QueryRow(
+		"INSERT INTO meetings (title, is_recording, status, auto_title, folder_id, glossary, prompt_template, retention_days, workspace) VALUES (?, TRUE, 'recording', ?, ?, ?, ?, ?, ?) RETURNING id",
+		title, autoTitle, folderID, defaults.Glossary, defaults.PromptTemplate, defaults.RetentionDays, defaults.workspace(),
+	).Scan(&id)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.GetByID(int(id))
+	if err := indexMeeting(s.db, id); err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(id)
 }
 
-// GetByID retrieves a meeting by ID
+// GetByID retrieves a meeting by ID. Meetings in the trash are not returned.
 func (s *MeetingService) GetByID(id int) (*Meeting, error) {
-	row := database.DB.QueryRow(`
-		SELECT id, title, created_at, updated_at, transcript, notes, audio_path, duration_seconds, is_recording
-		FROM meetings WHERE id = ?
-	`, id)
+	row := s.db.QueryRow("SELECT "+meetingColumns+" FROM meetings WHERE id = ? AND deleted_at IS NULL", id)
 
-	var m Meeting
-	var createdAt, updatedAt string
-	err := row.Scan(&m.ID, &m.Title, &createdAt, &updatedAt, &m.Transcript, &m.Notes, &m.AudioPath, &m.DurationSeconds, &m.IsRecording)
+	m, err := scanMeeting(row)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -59,17 +230,203 @@ func (s *MeetingService) GetByID(id int) (*Meeting, error) {
 		return nil, err
 	}
 
-	m.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
-	m.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAt)
-	return &m, nil
+	names, err := tagNamesForMeetings(s.db, []int{m.ID})
+	if err != nil {
+		return nil, err
+	}
+	m.Tags = names[m.ID]
+	if m.Tags == nil {
+		m.Tags = []string{}
+	}
+
+	if m.Participants, err = participantNames(s.db, m.ID); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// List returns one page of meeting summaries matching filter. Pages are
+// keyed on the sort column and ID, so inserts don't shift later pages.
+func (s *MeetingService) List(filter MeetingFilter) (*MeetingPage, error) {
+	sortField := filter.Sort
+	if sortField == "" {
+		sortField = "created_at"
+	}
+	column, ok := MeetingSortFields[sortField]
+	if !ok {
+		return nil, fmt.Errorf("unknown sort field %q", sortField)
+	}
+
+	direction, comparison := "DESC", "<"
+	if filter.Ascending {
+		direction, comparison = "ASC", ">"
+	}
+
+	// DATETIME columns are read back as text so the cursor compares with the stored value
+	sortKey := column
+	if column == "created_at" || column == "updated_at" {
+		sortKey = "CAST(" + column + " AS TEXT)"
+	}
+
+	query := `SELECT id, title, created_at, updated_at, duration_seconds, is_recording, status, scheduled_start, audio_path != '',
+		meeting_type, folder_id, description, substr(notes, 1, 1000), substr(transcript, 1, 400), ` + sortKey + `
+		FROM meetings WHERE deleted_at IS NULL`
+	var args []interface{}
+
+	if filter.Tag != "" {
+		query += " AND id IN (SELECT mt.meeting_id FROM meeting_tags mt JOIN tags t ON t.id = mt.tag_id WHERE t.name = ?)"
+		args = append(args, NormalizeTagName(filter.Tag))
+	}
+	if filter.Type != "" {
+		query += " AND meeting_type = ?"
+		args = append(args, filter.Type)
+	}
+	if filter.FolderIDs != nil {
+		query += " AND folder_id IN (" + placeholders(len(filter.FolderIDs)) + ")"
+		for _, id := range filter.FolderIDs {
+			args = append(args, id)
+		}
+	}
+	if filter.Unfiled {
+		query += " AND folder_id IS NULL"
+	}
+	if filter.From != nil {
+		query += " AND created_at >= ?"
+		args = append(args, filter.From.UTC().Format("2006-01-02 15:04:05"))
+	}
+	if filter.To != nil {
+		query += " AND created_at < ?"
+		args = append(args, filter.To.UTC().Format("2006-01-02 15:04:05"))
+	}
+	if filter.IsRecording != nil {
+		query += " AND is_recording = ?"
+		args = append(args, *filter.IsRecording)
+	}
+	if filter.Status != "" {
+		query += " AND status = ?"
+		args = append(args, filter.Status)
+	}
+	if filter.HasAudio != nil {
+		query += " AND (audio_path != '') = ?"
+		args = append(args, *filter.HasAudio)
+	}
+	if filter.PersonID != 0 {
+		query += " AND id IN (SELECT meeting_id FROM meeting_participants WHERE person_id = ?)"
+		args = append(args, filter.PersonID)
+	}
+	if filter.Cursor != "" {
+		cursor, err := decodeMeetingCursor(filter.Cursor)
+		if err != nil || cursor.Sort != sortField {
+			return nil, ErrInvalidCursor
+		}
+		query += fmt.Sprintf(" AND (%s, id) %s (?, ?)", column, comparison)
+		args = append(args, cursor.Value, cursor.ID)
+	}
+
+	// Fetch one extra row to know whether there is a next page
+	query += fmt.Sprintf(" ORDER BY %s %s, id %s", column, direction, direction)
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit+1)
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	page := &MeetingPage{Meetings: []MeetingSummary{}}
+	var lastKey interface{}
+	for rows.Next() {
+		var m MeetingSummary
+		var createdAt, updatedAt timestamp
+		var description, notes, transcript string
+		var folderID, scheduledStart sql.NullInt64
+		var key interface{}
+		if err := rows.Scan(&m.ID, &m.Title, &createdAt, &updatedAt, &m.DurationSeconds, &m.IsRecording, &m.Status, &scheduledStart, &m.HasAudio,
+			&m.MeetingType, &folderID, &description, &notes, &transcript, &key); err != nil {
+			return nil, err
+		}
+
+		if filter.Limit > 0 && len(page.Meetings) == filter.Limit {
+			last := page.Meetings[len(page.Meetings)-1]
+			page.NextCursor = encodeMeetingCursor(meetingCursor{Sort: sortField, Value: lastKey, ID: last.ID})
+			break
+		}
+
+		m.CreatedAt = createdAt.Time
+		m.UpdatedAt = updatedAt.Time
+		m.FolderID = nullableID(folderID)
+		m.ScheduledStart = nullableTime(scheduledStart)
+		m.Snippet = meetingSnippet(description, notes, transcript)
+		page.Meetings = append(page.Meetings, m)
+		lastKey = key
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, len(page.Meetings))
+	for i, m := range page.Meetings {
+		ids[i] = m.ID
+	}
+	names, err := tagNamesForMeetings(s.db, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range page.Meetings {
+		page.Meetings[i].Tags = names[page.Meetings[i].ID]
+		if page.Meetings[i].Tags == nil {
+			page.Meetings[i].Tags = []string{}
+		}
+	}
+
+	return page, nil
 }
 
-// GetAll retrieves all meetings ordered by creation date
-func (s *MeetingService) GetAll() ([]Meeting, error) {
-	rows, err := database.DB.Query(`
-		SELECT id, title, created_at, updated_at, transcript, notes, audio_path, duration_seconds, is_recording
-		FROM meetings ORDER BY created_at DESC
-	`)
+// meetingSnippet previews a meeting by its description, notes or transcript, whichever exists first
+func meetingSnippet(description, notes, transcript string) string {
+	text := strings.TrimSpace(description)
+	if text == "" {
+		text = plainText(notes)
+	}
+	if text == "" {
+		text = strings.TrimSpace(transcript)
+	}
+
+	if runes := []rune(text); len(runes) > snippetLength {
+		return strings.TrimSpace(string(runes[:snippetLength])) + "…"
+	}
+	return text
+}
+
+func encodeMeetingCursor(c meetingCursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeMeetingCursor(value string) (*meetingCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var c meetingCursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// GetInRange retrieves meetings created in [from, to), oldest first.
+// Scheduled meetings that were never started are left out.
+func (s *MeetingService) GetInRange(from, to time.Time) ([]Meeting, error) {
+	rows, err := s.db.Query(
+		"SELECT "+meetingColumns+" FROM meetings WHERE created_at >= ? AND created_at < ? AND deleted_at IS NULL AND status != 'scheduled' ORDER BY created_at ASC",
+		from.UTC().Format("2006-01-02 15:04:05"), to.UTC().Format("2006-01-02 15:04:05"),
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -77,57 +434,251 @@ func (s *MeetingService) GetAll() ([]Meeting, error) {
 
 	var meetings []Meeting
 	for rows.Next() {
-		var m Meeting
-		var createdAt, updatedAt string
-		if err := rows.Scan(&m.ID, &m.Title, &createdAt, &updatedAt, &m.Transcript, &m.Notes, &m.AudioPath, &m.DurationSeconds, &m.IsRecording); err != nil {
+		m, err := scanMeeting(rows)
+		if err != nil {
 			return nil, err
 		}
-		m.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
-		m.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAt)
-		meetings = append(meetings, m)
+		meetings = append(meetings, *m)
+	}
+
+	return meetings, rows.Err()
+}
+
+// ClaimVersion bumps a meeting's version if it is currently one of versions,
+// reserving the edit that follows. It reports false when the meeting has
+// been changed since, or doesn't exist.
+func (s *MeetingService) ClaimVersion(id int, versions []int) (bool, error) {
+	args := []interface{}{id}
+	for _, v := range versions {
+		args = append(args, v)
 	}
 
-	return meetings, nil
+	result, err := s.db.Exec(
+		"UPDATE meetings SET version = version + 1 WHERE id = ? AND deleted_at IS NULL AND version IN ("+placeholders(len(versions))+")",
+		args...,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
 }
 
 // UpdateTitle updates a meeting's title
 func (s *MeetingService) UpdateTitle(id int, title string) error {
-	_, err := database.DB.Exec(
-		"UPDATE meetings SET title = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?",
+	if _, err := s.db.Exec(
+		"UPDATE meetings SET title = ?, updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = ?",
 		title, id,
+	); err != nil {
+		return err
+	}
+
+	return indexMeeting(s.db, id)
+}
+
+// SetAutoTitle enables or disables automatic title generation for a meeting
+func (s *MeetingService) SetAutoTitle(id int, enabled bool) error {
+	_, err := s.db.Exec(
+		"UPDATE meetings SET auto_title = ?, updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = ?",
+		enabled, id,
 	)
 	return err
 }
 
-// UpdateNotes updates a meeting's notes
-func (s *MeetingService) UpdateNotes(id int, notes string) error {
-	_, err := database.DB.Exec(
-		"UPDATE meetings SET notes = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?",
-		notes, id,
+// ApplyGeneratedTitle stores an AI generated title and description, unless
+// the meeting was renamed in the meantime. It reports whether the title was applied.
+func (s *MeetingService) ApplyGeneratedTitle(id int, title, description string) (bool, error) {
+	result, err := s.db.Exec(
+		"UPDATE meetings SET title = ?, description = ?, updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = ? AND title = ? AND auto_title = TRUE",
+		title, description, id, DefaultMeetingTitle,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil || affected == 0 {
+		return false, err
+	}
+
+	return true, indexMeeting(s.db, id)
+}
+
+// UpdateSettings replaces a meeting's glossary, prompt template and retention
+func (s *MeetingService) UpdateSettings(id int, settings FolderDefaults) error {
+	if err := validateDefaults(settings); err != nil {
+		return err
+	}
+
+	_, err := s.db.Exec(
+		"UPDATE meetings SET glossary = ?, prompt_template = ?, retention_days = ?, updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = ?",
+		settings.Glossary, settings.PromptTemplate, settings.RetentionDays, id,
 	)
 	return err
 }
 
-// AppendTranscript appends text to a meeting's transcript
+// SetTemplate records the template a meeting was created from
+func (s *MeetingService) SetTemplate(id, templateID int) error {
+	_, err := s.db.Exec(
+		"UPDATE meetings SET template_id = ?, updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = ?",
+		templateID, id,
+	)
+	return err
+}
+
+// UpdateNotes updates a meeting's notes and records the save in its revision
+// history. source is one of the NoteSource constants or an AI action name.
+func (s *MeetingService) UpdateNotes(id int, notes, author, source string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(
+		"UPDATE meetings SET notes = ?, updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = ?",
+		notes, id,
+	); err != nil {
+		return err
+	}
+	if err := recordRevision(tx, id, notes, author, source); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return indexMeeting(s.db, id)
+}
+
+// UpdateTranscript replaces the transcript of a finished meeting. Stored
+// transcript segments are left as they are.
+func (s *MeetingService) UpdateTranscript(id int, transcript string) error {
+	result, err := s.db.Exec(
+		"UPDATE meetings SET transcript = ?, updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = ? AND is_recording = FALSE",
+		transcript, id,
+	)
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrStillRecording
+	}
+
+	return indexMeeting(s.db, id)
+}
+
+// AppendTranscript appends text to a meeting's transcript. Live appends
+// don't bump the version, so notes can be saved while recording. The
+// meeting's search row is left alone: the chunk is searchable through its
+// segment, and FinishRecording indexes the whole transcript once.
 func (s *MeetingService) AppendTranscript(id int, text string) error {
-	_, err := database.DB.Exec(
+	_, err := s.db.Exec(
 		"UPDATE meetings SET transcript = transcript || ' ' || ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?",
 		text, id,
 	)
 	return err
 }
 
-// FinishRecording marks recording as complete and updates audio path
+// FinishRecording marks recording as complete, updates audio path and
+// indexes the final transcript for search
 func (s *MeetingService) FinishRecording(id int, audioPath string, duration int) error {
-	_, err := database.DB.Exec(
-		"UPDATE meetings SET is_recording = FALSE, audio_path = ?, duration_seconds = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?",
+	if _, err := s.db.Exec(
+		"UPDATE meetings SET is_recording = FALSE, status = 'finished', audio_path = ?, duration_seconds = ?, updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = ?",
 		audioPath, duration, id,
+	); err != nil {
+		return err
+	}
+
+	return indexMeeting(s.db, id)
+}
+
+// Start turns a scheduled meeting into a recording that starts now. It
+// reports false if there is no such meeting in the scheduled state.
+func (s *MeetingService) Start(id int) (bool, error) {
+	result, err := s.db.Exec(`
+		UPDATE meetings SET status = 'recording', is_recording = TRUE, created_at = CURRENT_TIMESTAMP,
+			updated_at = CURRENT_TIMESTAMP, version = version + 1
+		WHERE id = ? AND status = 'scheduled' AND deleted_at IS NULL
+	`, id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// Trash moves a meeting to the trash. It reports false if there is no such meeting outside the trash.
+func (s *MeetingService) Trash(id int) (bool, error) {
+	result, err := s.db.Exec(
+		"UPDATE meetings SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL",
+		time.Now().Unix(), id,
 	)
-	return err
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// Restore takes a meeting out of the trash
+func (s *MeetingService) Restore(id int) (bool, error) {
+	result, err := s.db.Exec("UPDATE meetings SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL", id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// TrashExpired moves finished meetings older than their retention period to
+// the trash and returns how many were moved
+func (s *MeetingService) TrashExpired() (int, error) {
+	cutoff := "datetime('now', '-' || retention_days || ' days')"
+	if s.dialect == database.Postgres {
+		cutoff = "now() - retention_days * INTERVAL '1 day'"
+	}
+
+	result, err := s.db.Exec(`
+		UPDATE meetings SET deleted_at = ?
+		WHERE deleted_at IS NULL AND status = 'finished' AND retention_days > 0
+			AND created_at < `+cutoff, time.Now().Unix())
+	if err != nil {
+		return 0, err
+	}
+
+	affected, err := result.RowsAffected()
+	return int(affected), err
 }
 
-// Delete removes a meeting
+// Delete permanently removes a meeting and everything stored with it
 func (s *MeetingService) Delete(id int) error {
-	_, err := database.DB.Exec("DELETE FROM meetings WHERE id = ?", id)
-	return err
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, query := range []string{
+		"DELETE FROM tasks WHERE meeting_id = ?",
+		"DELETE FROM transcript_segments WHERE meeting_id = ?",
+		"DELETE FROM meeting_analytics WHERE meeting_id = ?",
+		"DELETE FROM meeting_tags WHERE meeting_id = ?",
+		"DELETE FROM note_revisions WHERE meeting_id = ?",
+		"DELETE FROM meeting_participants WHERE meeting_id = ?",
+		"DELETE FROM meetings WHERE id = ?",
+	} {
+		if _, err := tx.Exec(query, id); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return unindexMeeting(s.db, id)
 }
@@ -0,0 +1,1226 @@
+package services
+
+import (
+	"backend/internal/database"
+	"database/sql"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps meetings, tags, folders, segments and tasks in process
+// memory, for tests and throwaway instances. Nothing is persisted. The
+// services that only run on SQL storage (see Repositories) get a private
+// in-memory SQLite database that never sees these meetings: participants,
+// revisions, the trash listing, search and analytics don't work against this
+// store, and notes saved here keep no revision history.
+type MemoryStore struct {
+	Meetings *MemoryMeetingRepository
+	Tags     *MemoryTagRepository
+	Folders  *MemoryFolderRepository
+	Segments *MemorySegmentRepository
+	Tasks    *MemoryTaskRepository
+
+	db *sql.DB
+}
+
+// NewMemoryStore returns an empty store, which must be closed after use
+func NewMemoryStore() (*MemoryStore, error) {
+	db, err := database.OpenSQLite(":memory:")
+	if err != nil {
+		return nil, err
+	}
+
+	data := &memoryData{
+		meetings: map[int]*memoryMeeting{},
+		tags:     map[int]string{},
+		folders:  map[int]*Folder{},
+		segments: map[int][]Segment{},
+	}
+	return &MemoryStore{
+		Meetings: &MemoryMeetingRepository{data: data},
+		Tags:     &MemoryTagRepository{data: data},
+		Folders:  &MemoryFolderRepository{data: data},
+		Segments: &MemorySegmentRepository{data: data},
+		Tasks:    &MemoryTaskRepository{data: data},
+		db:       db,
+	}, nil
+}
+
+// Repositories returns the store's repositories for SetupRouter
+func (s *MemoryStore) Repositories() Repositories {
+	return Repositories{
+		DB:       s.db,
+		Dialect:  database.SQLite,
+		Meetings: s.Meetings,
+		Tags:     s.Tags,
+		Folders:  s.Folders,
+		Segments: s.Segments,
+		Tasks:    s.Tasks,
+	}
+}
+
+// Close drops the store's data
+func (s *MemoryStore) Close() error {
+	return s.db.Close()
+}
+
+// memoryData is shared by the repositories of a store, since meetings,
+// their tags and folder counts refer to each other
+type memoryData struct {
+	mu       sync.Mutex
+	meetings map[int]*memoryMeeting
+	tags     map[int]string
+	folders  map[int]*Folder
+	segments map[int][]Segment // By meeting, in the order they were added
+	tasks    []Task
+	lastID   int
+}
+
+type memoryMeeting struct {
+	Meeting
+	deletedAt *time.Time
+	tagSource map[int]string // Tag ID to TagSourceManual or TagSourceAuto
+	importKey string
+}
+
+// memoryTimeLayout formats sort keys at a fixed width, so they compare as strings
+const memoryTimeLayout = "2006-01-02T15:04:05.000000000Z"
+
+// memoryNow matches the second precision of SQLite's CURRENT_TIMESTAMP
+func memoryNow() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
+}
+
+func (d *memoryData) nextID() int {
+	d.lastID++
+	return d.lastID
+}
+
+// live returns a meeting unless it doesn't exist or is in the trash
+func (d *memoryData) live(id int) *memoryMeeting {
+	if m := d.meetings[id]; m != nil && m.deletedAt == nil {
+		return m
+	}
+	return nil
+}
+
+func (d *memoryData) touch(m *memoryMeeting) {
+	m.UpdatedAt = memoryNow()
+	m.Version++
+}
+
+// tagNames returns a meeting's tag names sorted like tagNamesForMeetings
+func (d *memoryData) tagNames(m *memoryMeeting) []string {
+	names := []string{}
+	for id := range m.tagSource {
+		names = append(names, d.tags[id])
+	}
+	sort.Strings(names)
+	return names
+}
+
+// meeting returns a copy of m that callers can't use to change the store
+func (d *memoryData) meeting(m *memoryMeeting) *Meeting {
+	meeting := m.Meeting
+	if m.FolderID != nil {
+		folderID := *m.FolderID
+		meeting.FolderID = &folderID
+	}
+	if m.TemplateID != nil {
+		templateID := *m.TemplateID
+		meeting.TemplateID = &templateID
+	}
+	meeting.Tags = d.tagNames(m)
+	meeting.Participants = append([]string{}, m.Participants...)
+	return &meeting
+}
+
+func (d *memoryData) tagID(name string) (int, bool) {
+	for id, existing := range d.tags {
+		if existing == name {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+func (d *memoryData) getOrCreateTag(name string) int {
+	if id, ok := d.tagID(name); ok {
+		return id
+	}
+	id := d.nextID()
+	d.tags[id] = name
+	return id
+}
+
+func (d *memoryData) folderMeetings(folderID int) int {
+	count := 0
+	for _, m := range d.meetings {
+		if m.deletedAt == nil && m.FolderID != nil && *m.FolderID == folderID {
+			count++
+		}
+	}
+	return count
+}
+
+// MemoryMeetingRepository is the in-memory MeetingRepository
+type MemoryMeetingRepository struct {
+	data *memoryData
+}
+
+// Add stores meeting as given, e.g. a scheduled meeting for a test. Zero
+// IDs, versions and times are filled in; the status defaults to finished.
+func (r *MemoryMeetingRepository) Add(meeting Meeting) *Meeting {
+	d := r.data
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if meeting.ID == 0 {
+		meeting.ID = d.nextID()
+	}
+	d.lastID = max(d.lastID, meeting.ID)
+	if meeting.Version == 0 {
+		meeting.Version = 1
+	}
+	if meeting.CreatedAt.IsZero() {
+		meeting.CreatedAt = memoryNow()
+	}
+	if meeting.UpdatedAt.IsZero() {
+		meeting.UpdatedAt = meeting.CreatedAt
+	}
+	if meeting.Status == "" {
+		meeting.Status = MeetingStatusFinished
+	}
+	if meeting.Workspace == "" {
+		meeting.Workspace = DefaultWorkspace
+	}
+	meeting.Participants = append([]string{}, meeting.Participants...)
+
+	m := &memoryMeeting{Meeting: meeting, tagSource: map[int]string{}}
+	for _, name := range meeting.Tags {
+		m.tagSource[d.getOrCreateTag(NormalizeTagName(name))] = TagSourceManual
+	}
+	d.meetings[meeting.ID] = m
+	return d.meeting(m)
+}
+
+func (r *MemoryMeetingRepository) Create(title string, autoTitle bool, folderID *int, defaults FolderDefaults) (*Meeting, error) {
+	d := r.data
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := memoryNow()
+	m := &memoryMeeting{
+		Meeting: Meeting{
+			ID:             d.nextID(),
+			Title:          title,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+			IsRecording:    true,
+			Status:         MeetingStatusRecording,
+			AutoTitle:      autoTitle,
+			Glossary:       defaults.Glossary,
+			PromptTemplate: defaults.PromptTemplate,
+			RetentionDays:  defaults.RetentionDays,
+			Workspace:      defaults.workspace(),
+			Version:        1,
+		},
+		tagSource: map[int]string{},
+	}
+	if folderID != nil {
+		id := *folderID
+		m.FolderID = &id
+	}
+	d.meetings[m.ID] = m
+	return d.meeting(m), nil
+}
+
+func (r *MemoryMeetingRepository) GetByID(id int) (*Meeting, error) {
+	d := r.data
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if m := d.live(id); m != nil {
+		return d.meeting(m), nil
+	}
+	return nil, nil
+}
+
+func (r *MemoryMeetingRepository) List(filter MeetingFilter) (*MeetingPage, error) {
+	sortField := filter.Sort
+	if sortField == "" {
+		sortField = "created_at"
+	}
+	if _, ok := MeetingSortFields[sortField]; !ok {
+		return nil, fmt.Errorf("unknown sort field %q", sortField)
+	}
+
+	var cursor *meetingCursor
+	if filter.Cursor != "" {
+		var err error
+		if cursor, err = decodeMeetingCursor(filter.Cursor); err != nil || cursor.Sort != sortField {
+			return nil, ErrInvalidCursor
+		}
+	}
+
+	d := r.data
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	type entry struct {
+		m   *memoryMeeting
+		key interface{}
+	}
+	// after reports whether a sorts after b in the requested order
+	after := func(aKey interface{}, aID int, bKey interface{}, bID int) bool {
+		c := compareSortKeys(aKey, bKey)
+		if c == 0 {
+			c = aID - bID
+		}
+		if filter.Ascending {
+			return c > 0
+		}
+		return c < 0
+	}
+
+	var entries []entry
+	for _, m := range d.meetings {
+		if m.deletedAt != nil || !memoryMatches(d, m, filter) {
+			continue
+		}
+		key := memorySortKey(m, sortField)
+		if cursor != nil && !after(key, m.ID, cursor.Value, cursor.ID) {
+			continue
+		}
+		entries = append(entries, entry{m, key})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return after(entries[j].key, entries[j].m.ID, entries[i].key, entries[i].m.ID)
+	})
+
+	page := &MeetingPage{Meetings: []MeetingSummary{}}
+	for i, e := range entries {
+		if filter.Limit > 0 && i == filter.Limit {
+			last := entries[i-1]
+			page.NextCursor = encodeMeetingCursor(meetingCursor{Sort: sortField, Value: last.key, ID: last.m.ID})
+			break
+		}
+
+		m := e.m
+		summary := MeetingSummary{
+			ID:              m.ID,
+			Title:           m.Title,
+			CreatedAt:       m.CreatedAt,
+			UpdatedAt:       m.UpdatedAt,
+			DurationSeconds: m.DurationSeconds,
+			IsRecording:     m.IsRecording,
+			Status:          m.Status,
+			ScheduledStart:  m.ScheduledStart,
+			HasAudio:        m.AudioPath != "",
+			MeetingType:     m.MeetingType,
+			Tags:            d.tagNames(m),
+			Snippet:         meetingSnippet(m.Description, m.Notes, m.Transcript),
+		}
+		if m.FolderID != nil {
+			folderID := *m.FolderID
+			summary.FolderID = &folderID
+		}
+		page.Meetings = append(page.Meetings, summary)
+	}
+
+	return page, nil
+}
+
+// memoryMatches applies the filters of List other than paging. People
+// aren't kept in memory, so a person filter matches nothing.
+func memoryMatches(d *memoryData, m *memoryMeeting, filter MeetingFilter) bool {
+	if filter.Tag != "" {
+		id, ok := d.tagID(NormalizeTagName(filter.Tag))
+		if _, tagged := m.tagSource[id]; !ok || !tagged {
+			return false
+		}
+	}
+	if filter.Type != "" && m.MeetingType != filter.Type {
+		return false
+	}
+	if filter.FolderIDs != nil && (m.FolderID == nil || !slices.Contains(filter.FolderIDs, *m.FolderID)) {
+		return false
+	}
+	if filter.Unfiled && m.FolderID != nil {
+		return false
+	}
+	if filter.From != nil && m.CreatedAt.Before(*filter.From) {
+		return false
+	}
+	if filter.To != nil && !m.CreatedAt.Before(*filter.To) {
+		return false
+	}
+	if filter.IsRecording != nil && m.IsRecording != *filter.IsRecording {
+		return false
+	}
+	if filter.Status != "" && m.Status != filter.Status {
+		return false
+	}
+	if filter.HasAudio != nil && (m.AudioPath != "") != *filter.HasAudio {
+		return false
+	}
+	return filter.PersonID == 0
+}
+
+// memorySortKey returns the value a meeting sorts by. Keys survive the JSON
+// round trip through a cursor: times as fixed width strings, durations as float64.
+func memorySortKey(m *memoryMeeting, sortField string) interface{} {
+	switch sortField {
+	case "updated_at":
+		return m.UpdatedAt.UTC().Format(memoryTimeLayout)
+	case "title":
+		return m.Title
+	case "duration":
+		return float64(m.DurationSeconds)
+	default:
+		return m.CreatedAt.UTC().Format(memoryTimeLayout)
+	}
+}
+
+func compareSortKeys(a, b interface{}) int {
+	switch a := a.(type) {
+	case string:
+		b, _ := b.(string)
+		return strings.Compare(a, b)
+	case float64:
+		b, _ := b.(float64)
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+	}
+	return 0
+}
+
+func (r *MemoryMeetingRepository) GetInRange(from, to time.Time) ([]Meeting, error) {
+	d := r.data
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var meetings []Meeting
+	for _, m := range d.meetings {
+		if m.deletedAt == nil && m.Status != MeetingStatusScheduled && !m.CreatedAt.Before(from) && m.CreatedAt.Before(to) {
+			meeting := d.meeting(m)
+			meeting.Tags, meeting.Participants = nil, nil // Like the SQLite query, which doesn't load them
+			meetings = append(meetings, *meeting)
+		}
+	}
+	sort.Slice(meetings, func(i, j int) bool {
+		if !meetings[i].CreatedAt.Equal(meetings[j].CreatedAt) {
+			return meetings[i].CreatedAt.Before(meetings[j].CreatedAt)
+		}
+		return meetings[i].ID < meetings[j].ID
+	})
+	return meetings, nil
+}
+
+func (r *MemoryMeetingRepository) ClaimVersion(id int, versions []int) (bool, error) {
+	d := r.data
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	m := d.live(id)
+	if m == nil || !slices.Contains(versions, m.Version) {
+		return false, nil
+	}
+	m.Version++
+	return true, nil
+}
+
+// update changes a meeting whether or not it is in the trash, like the
+// SQLite updates that don't check deleted_at
+func (r *MemoryMeetingRepository) update(id int, change func(m *memoryMeeting)) {
+	d := r.data
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if m := d.meetings[id]; m != nil {
+		change(m)
+	}
+}
+
+func (r *MemoryMeetingRepository) UpdateTitle(id int, title string) error {
+	r.update(id, func(m *memoryMeeting) {
+		m.Title = title
+		r.data.touch(m)
+	})
+	return nil
+}
+
+func (r *MemoryMeetingRepository) SetAutoTitle(id int, enabled bool) error {
+	r.update(id, func(m *memoryMeeting) {
+		m.AutoTitle = enabled
+		r.data.touch(m)
+	})
+	return nil
+}
+
+func (r *MemoryMeetingRepository) ApplyGeneratedTitle(id int, title, description string) (bool, error) {
+	applied := false
+	r.update(id, func(m *memoryMeeting) {
+		if m.Title == DefaultMeetingTitle && m.AutoTitle {
+			m.Title, m.Description = title, description
+			r.data.touch(m)
+			applied = true
+		}
+	})
+	return applied, nil
+}
+
+func (r *MemoryMeetingRepository) UpdateSettings(id int, settings FolderDefaults) error {
+	if err := validateDefaults(settings); err != nil {
+		return err
+	}
+
+	r.update(id, func(m *memoryMeeting) {
+		m.Glossary, m.PromptTemplate, m.RetentionDays = settings.Glossary, settings.PromptTemplate, settings.RetentionDays
+		r.data.touch(m)
+	})
+	return nil
+}
+
+func (r *MemoryMeetingRepository) SetTemplate(id, templateID int) error {
+	r.update(id, func(m *memoryMeeting) {
+		m.TemplateID = &templateID
+		r.data.touch(m)
+	})
+	return nil
+}
+
+// UpdateNotes replaces a meeting's notes. No revision history is kept.
+func (r *MemoryMeetingRepository) UpdateNotes(id int, notes, author, source string) error {
+	r.update(id, func(m *memoryMeeting) {
+		m.Notes = notes
+		r.data.touch(m)
+	})
+	return nil
+}
+
+func (r *MemoryMeetingRepository) UpdateTranscript(id int, transcript string) error {
+	updated := false
+	r.update(id, func(m *memoryMeeting) {
+		if !m.IsRecording {
+			m.Transcript = transcript
+			r.data.touch(m)
+			updated = true
+		}
+	})
+	if !updated {
+		return ErrStillRecording
+	}
+	return nil
+}
+
+func (r *MemoryMeetingRepository) AppendTranscript(id int, text string) error {
+	r.update(id, func(m *memoryMeeting) {
+		m.Transcript += " " + text
+		m.UpdatedAt = memoryNow()
+	})
+	return nil
+}
+
+func (r *MemoryMeetingRepository) FinishRecording(id int, audioPath string, duration int) error {
+	r.update(id, func(m *memoryMeeting) {
+		m.IsRecording, m.Status = false, MeetingStatusFinished
+		m.AudioPath, m.DurationSeconds = audioPath, duration
+		r.data.touch(m)
+	})
+	return nil
+}
+
+func (r *MemoryMeetingRepository) Start(id int) (bool, error) {
+	d := r.data
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	m := d.live(id)
+	if m == nil || m.Status != MeetingStatusScheduled {
+		return false, nil
+	}
+	m.Status, m.IsRecording = MeetingStatusRecording, true
+	m.CreatedAt = memoryNow()
+	d.touch(m)
+	return true, nil
+}
+
+func (r *MemoryMeetingRepository) Patch(id int, patch MeetingPatch, author string, versions []int) (*Meeting, error) {
+	if err := patch.Validate(); err != nil {
+		return nil, err
+	}
+
+	d := r.data
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	m := d.live(id)
+	if m == nil {
+		return nil, nil
+	}
+	if versions != nil && !slices.Contains(versions, m.Version) {
+		return nil, ErrVersionConflict
+	}
+	if patch.Transcript != nil && m.IsRecording {
+		return nil, ErrStillRecording
+	}
+	if patch.SetFolder && patch.FolderID != nil && d.folders[*patch.FolderID] == nil {
+		return nil, fmt.Errorf("%w: folder %d does not exist", ErrInvalidPatch, *patch.FolderID)
+	}
+
+	if patch.Title != nil {
+		m.Title = strings.TrimSpace(*patch.Title)
+		if m.Title == "" {
+			m.Title = DefaultMeetingTitle
+		}
+	}
+	for _, field := range []struct {
+		value  *string
+		target *string
+	}{
+		{patch.Description, &m.Description},
+		{patch.Notes, &m.Notes},
+		{patch.Transcript, &m.Transcript},
+		{patch.MeetingType, &m.MeetingType},
+		{patch.Language, &m.Language},
+		{patch.Glossary, &m.Glossary},
+		{patch.PromptTemplate, &m.PromptTemplate},
+	} {
+		if field.value != nil {
+			*field.target = *field.value
+		}
+	}
+	if patch.AutoTitle != nil {
+		m.AutoTitle = *patch.AutoTitle
+	}
+	if patch.RetentionDays != nil {
+		m.RetentionDays = *patch.RetentionDays
+	}
+	if patch.Workspace != nil {
+		m.Workspace = FolderDefaults{Workspace: *patch.Workspace}.workspace()
+	}
+	if patch.SetFolder {
+		m.FolderID = nil
+		if patch.FolderID != nil {
+			folderID := *patch.FolderID
+			m.FolderID = &folderID
+		}
+	}
+
+	if patch.Tags != nil {
+		m.tagSource = map[int]string{}
+		for _, name := range *patch.Tags {
+			m.tagSource[d.getOrCreateTag(NormalizeTagName(name))] = TagSourceManual
+		}
+	}
+	if patch.Participants != nil {
+		m.Participants = mergeParticipants(m.Participants, *patch.Participants)
+	}
+
+	d.touch(m)
+	return d.meeting(m), nil
+}
+
+// mergeParticipants makes names the participant list like setParticipants:
+// names are trimmed, duplicates dropped and participants still listed keep
+// their spelling
+func mergeParticipants(current, names []string) []string {
+	spelling := map[string]string{}
+	for _, name := range current {
+		spelling[strings.ToLower(name)] = name
+	}
+
+	merged := []string{}
+	seen := map[string]bool{}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		key := strings.ToLower(name)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if existing, ok := spelling[key]; ok {
+			name = existing
+		}
+		merged = append(merged, name)
+	}
+	return merged
+}
+
+func (r *MemoryMeetingRepository) Trash(id int) (bool, error) {
+	d := r.data
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	m := d.live(id)
+	if m == nil {
+		return false, nil
+	}
+	now := time.Now()
+	m.deletedAt = &now
+	return true, nil
+}
+
+func (r *MemoryMeetingRepository) Restore(id int) (bool, error) {
+	d := r.data
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	m := d.meetings[id]
+	if m == nil || m.deletedAt == nil {
+		return false, nil
+	}
+	m.deletedAt = nil
+	return true, nil
+}
+
+func (r *MemoryMeetingRepository) TrashExpired() (int, error) {
+	d := r.data
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	trashed := 0
+	for _, m := range d.meetings {
+		if m.deletedAt == nil && m.Status == MeetingStatusFinished && m.RetentionDays > 0 &&
+			m.CreatedAt.Before(now.AddDate(0, 0, -m.RetentionDays)) {
+			m.deletedAt = &now
+			trashed++
+		}
+	}
+	return trashed, nil
+}
+
+func (r *MemoryMeetingRepository) Delete(id int) error {
+	d := r.data
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.meetings, id)
+	delete(d.segments, id)
+	d.tasks = slices.DeleteFunc(d.tasks, func(t Task) bool { return t.MeetingID == id })
+	return nil
+}
+
+func (r *MemoryMeetingRepository) GetByImportKey(key string) (*Meeting, error) {
+	d := r.data
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, m := range d.meetings {
+		if m.importKey == key && m.deletedAt == nil {
+			return d.meeting(m), nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *MemoryMeetingRepository) RestoreImported(key string) (*Meeting, error) {
+	d := r.data
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, m := range d.meetings {
+		if m.importKey == key && m.deletedAt != nil {
+			m.deletedAt = nil
+			return d.meeting(m), nil
+		}
+	}
+	return nil, nil
+}
+
+// CreateImported stores the meeting and its segments. Named speakers become
+// participants; there is no people directory to link them to.
+func (r *MemoryMeetingRepository) CreateImported(meeting Meeting, key, source string, segments []Segment) (*Meeting, error) {
+	d := r.data
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var speakers []string
+	for _, seg := range segments {
+		if realSpeaker(strings.TrimSpace(seg.Speaker)) {
+			speakers = append(speakers, seg.Speaker)
+		}
+	}
+
+	meeting.ID = d.nextID()
+	meeting.IsRecording, meeting.Status, meeting.Version = false, MeetingStatusFinished, 1
+	meeting.CreatedAt = meeting.CreatedAt.UTC().Truncate(time.Second)
+	meeting.UpdatedAt = memoryNow()
+	meeting.Workspace = FolderDefaults{Workspace: meeting.Workspace}.workspace()
+	meeting.Participants = mergeParticipants(nil, speakers)
+	if meeting.FolderID != nil {
+		id := *meeting.FolderID
+		meeting.FolderID = &id
+	}
+	m := &memoryMeeting{Meeting: meeting, tagSource: map[int]string{}, importKey: key}
+	d.meetings[m.ID] = m
+
+	for _, seg := range segments {
+		seg.MeetingID = m.ID
+		if strings.TrimSpace(seg.Speaker) == "" {
+			seg.Speaker = UnknownSpeaker
+		}
+		seg.ID = d.nextID()
+		d.segments[m.ID] = append(d.segments[m.ID], seg)
+	}
+	return d.meeting(m), nil
+}
+
+// MemoryTagRepository is the in-memory TagRepository
+type MemoryTagRepository struct {
+	data *memoryData
+}
+
+func (r *MemoryTagRepository) GetAll() ([]Tag, error) {
+	d := r.data
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var tags []Tag
+	for id, name := range d.tags {
+		t := Tag{ID: id, Name: name}
+		for _, m := range d.meetings {
+			if _, tagged := m.tagSource[id]; tagged && m.deletedAt == nil {
+				t.MeetingCount++
+			}
+		}
+		tags = append(tags, t)
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Name < tags[j].Name })
+	return tags, nil
+}
+
+func (r *MemoryTagRepository) GetOrCreate(name string) (*Tag, error) {
+	name = NormalizeTagName(name)
+	if name == "" {
+		return nil, fmt.Errorf("tag name must not be empty")
+	}
+	if len(name) > maxTagLength {
+		return nil, fmt.Errorf("tag name must be at most %d characters", maxTagLength)
+	}
+
+	d := r.data
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return &Tag{ID: d.getOrCreateTag(name), Name: name}, nil
+}
+
+func (r *MemoryTagRepository) Rename(id int, name string) (*Tag, error) {
+	name = NormalizeTagName(name)
+	if name == "" {
+		return nil, fmt.Errorf("tag name must not be empty")
+	}
+
+	d := r.data
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if existing, ok := d.tagID(name); ok && existing != id {
+		return nil, fmt.Errorf("a tag named %q already exists", name)
+	}
+	if _, ok := d.tags[id]; !ok {
+		return nil, nil
+	}
+	d.tags[id] = name
+	return &Tag{ID: id, Name: name}, nil
+}
+
+func (r *MemoryTagRepository) Delete(id int) error {
+	d := r.data
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, m := range d.meetings {
+		delete(m.tagSource, id)
+	}
+	delete(d.tags, id)
+	return nil
+}
+
+func (r *MemoryTagRepository) AddToMeeting(meetingID int, name, source string) (*MeetingTag, error) {
+	tag, err := r.GetOrCreate(name)
+	if err != nil {
+		return nil, err
+	}
+
+	d := r.data
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	m := d.meetings[meetingID]
+	if m == nil {
+		return nil, fmt.Errorf("meeting %d does not exist", meetingID)
+	}
+	if m.tagSource[tag.ID] != TagSourceManual {
+		m.tagSource[tag.ID] = source
+	}
+	return &MeetingTag{ID: tag.ID, Name: tag.Name, Source: source}, nil
+}
+
+func (r *MemoryTagRepository) RemoveFromMeeting(meetingID, tagID int) error {
+	d := r.data
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if m := d.meetings[meetingID]; m != nil {
+		delete(m.tagSource, tagID)
+	}
+	return nil
+}
+
+func (r *MemoryTagRepository) GetForMeeting(meetingID int) ([]MeetingTag, error) {
+	d := r.data
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	tags := []MeetingTag{}
+	if m := d.meetings[meetingID]; m != nil {
+		for id, source := range m.tagSource {
+			tags = append(tags, MeetingTag{ID: id, Name: d.tags[id], Source: source})
+		}
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Name < tags[j].Name })
+	return tags, nil
+}
+
+func (r *MemoryTagRepository) SetMeetingType(meetingID int, meetingType string, onlyIfEmpty bool) error {
+	if !ValidMeetingType(meetingType) {
+		return fmt.Errorf("unknown meeting type %q, use one of %s", meetingType, strings.Join(MeetingTypes, ", "))
+	}
+
+	d := r.data
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if m := d.meetings[meetingID]; m != nil && (!onlyIfEmpty || m.MeetingType == "") {
+		m.MeetingType = meetingType
+		d.touch(m)
+	}
+	return nil
+}
+
+func (r *MemoryTagRepository) Exists(id int) (bool, error) {
+	d := r.data
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	_, ok := d.tags[id]
+	return ok, nil
+}
+
+func (r *MemoryTagRepository) Names() ([]string, error) {
+	return tagNames(r)
+}
+
+func (r *MemoryTagRepository) ApplyClassification(meetingID int, classification *MeetingClassification) error {
+	return applyClassification(r, meetingID, classification)
+}
+
+// MemoryFolderRepository is the in-memory FolderRepository
+type MemoryFolderRepository struct {
+	data *memoryData
+}
+
+func (r *MemoryFolderRepository) GetAll() ([]Folder, error) {
+	d := r.data
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	folders := []Folder{}
+	for _, f := range d.folders {
+		folders = append(folders, *d.folder(f))
+	}
+	sort.Slice(folders, func(i, j int) bool {
+		a, b := strings.ToLower(folders[i].Name), strings.ToLower(folders[j].Name)
+		if a != b {
+			return a < b
+		}
+		return folders[i].ID < folders[j].ID
+	})
+	return folders, nil
+}
+
+// folder returns a copy of f with its meeting count
+func (d *memoryData) folder(f *Folder) *Folder {
+	folder := *f
+	if f.ParentID != nil {
+		parentID := *f.ParentID
+		folder.ParentID = &parentID
+	}
+	folder.MeetingCount = d.folderMeetings(f.ID)
+	return &folder
+}
+
+func (r *MemoryFolderRepository) GetByID(id int) (*Folder, error) {
+	d := r.data
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if f := d.folders[id]; f != nil {
+		return d.folder(f), nil
+	}
+	return nil, nil
+}
+
+func (r *MemoryFolderRepository) Create(name string, parentID *int, defaults FolderDefaults) (*Folder, error) {
+	name = strings.TrimSpace(name)
+	if err := validateFolder(name, defaults); err != nil {
+		return nil, err
+	}
+	if err := checkParent(r, 0, parentID); err != nil {
+		return nil, err
+	}
+
+	d := r.data
+	d.mu.Lock()
+	f := &Folder{ID: d.nextID(), Name: name, FolderDefaults: defaults, CreatedAt: time.Unix(time.Now().Unix(), 0)}
+	if parentID != nil {
+		id := *parentID
+		f.ParentID = &id
+	}
+	d.folders[f.ID] = f
+	d.mu.Unlock()
+
+	return r.GetByID(f.ID)
+}
+
+func (r *MemoryFolderRepository) Update(id int, name string, parentID *int, defaults FolderDefaults) (*Folder, error) {
+	name = strings.TrimSpace(name)
+	if err := validateFolder(name, defaults); err != nil {
+		return nil, err
+	}
+	if err := checkParent(r, id, parentID); err != nil {
+		return nil, err
+	}
+
+	d := r.data
+	d.mu.Lock()
+	f := d.folders[id]
+	if f != nil {
+		f.Name, f.FolderDefaults, f.ParentID = name, defaults, nil
+		if parentID != nil {
+			parent := *parentID
+			f.ParentID = &parent
+		}
+	}
+	d.mu.Unlock()
+
+	if f == nil {
+		return nil, nil
+	}
+	return r.GetByID(id)
+}
+
+func (r *MemoryFolderRepository) Delete(id int) error {
+	d := r.data
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	folder := d.folders[id]
+	if folder == nil {
+		return nil
+	}
+
+	// Meetings in the trash move too, as with SQLite
+	for _, m := range d.meetings {
+		if m.FolderID != nil && *m.FolderID == id {
+			m.FolderID = folder.ParentID
+			m.Version++
+		}
+	}
+	for _, f := range d.folders {
+		if f.ParentID != nil && *f.ParentID == id {
+			f.ParentID = folder.ParentID
+		}
+	}
+	delete(d.folders, id)
+	return nil
+}
+
+func (r *MemoryFolderRepository) Subtree(id int) ([]int, error) {
+	d := r.data
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.folders[id] == nil {
+		return nil, nil
+	}
+
+	ids := []int{id}
+	for i := 0; i < len(ids); i++ {
+		for _, f := range d.folders {
+			if f.ParentID != nil && *f.ParentID == ids[i] && !slices.Contains(ids, f.ID) {
+				ids = append(ids, f.ID)
+			}
+		}
+	}
+	return ids, nil
+}
+
+func (r *MemoryFolderRepository) EffectiveDefaults(id int) (FolderDefaults, error) {
+	return resolveDefaults(r, id)
+}
+
+func (r *MemoryFolderRepository) MoveMeeting(meetingID int, folderID *int) (bool, error) {
+	d := r.data
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	m := d.live(meetingID)
+	if m == nil {
+		return false, nil
+	}
+	m.FolderID = nil
+	if folderID != nil {
+		id := *folderID
+		m.FolderID = &id
+	}
+	d.touch(m)
+	return true, nil
+}
+
+// MemorySegmentRepository is the in-memory SegmentRepository
+type MemorySegmentRepository struct {
+	data *memoryData
+}
+
+func (r *MemorySegmentRepository) Add(seg *Segment) error {
+	d := r.data
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if strings.TrimSpace(seg.Speaker) == "" {
+		seg.Speaker = UnknownSpeaker
+	}
+	seg.ID = d.nextID()
+	d.segments[seg.MeetingID] = append(d.segments[seg.MeetingID], *seg)
+	return nil
+}
+
+func (r *MemorySegmentRepository) GetByMeeting(meetingID int) ([]Segment, error) {
+	d := r.data
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	segments := slices.Clone(d.segments[meetingID])
+	sort.SliceStable(segments, func(i, j int) bool {
+		if segments[i].StartMs != segments[j].StartMs {
+			return segments[i].StartMs < segments[j].StartMs
+		}
+		return segments[i].ID < segments[j].ID
+	})
+	return segments, nil
+}
+
+func (r *MemorySegmentRepository) LastEnd(meetingID int) (int64, error) {
+	d := r.data
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var end int64
+	for _, seg := range d.segments[meetingID] {
+		end = max(end, seg.EndMs)
+	}
+	return end, nil
+}
+
+func (r *MemorySegmentRepository) SetSpeaker(meetingID, segmentID int, speaker string) (bool, error) {
+	d := r.data
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if strings.TrimSpace(speaker) == "" {
+		speaker = UnknownSpeaker
+	}
+	segments := d.segments[meetingID]
+	for i := range segments {
+		if segments[i].ID == segmentID {
+			segments[i].Speaker = strings.TrimSpace(speaker)
+			if m := d.meetings[meetingID]; m != nil {
+				m.Version++
+			}
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// MemoryTaskRepository is the in-memory TaskRepository
+type MemoryTaskRepository struct {
+	data *memoryData
+}
+
+func (r *MemoryTaskRepository) GetForMeeting(meetingID int) ([]Task, error) {
+	d := r.data
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	tasks := []Task{}
+	for _, t := range d.tasks {
+		if t.MeetingID == meetingID {
+			tasks = append(tasks, t)
+		}
+	}
+	// Open tasks first, each group in the order added
+	sort.SliceStable(tasks, func(i, j int) bool { return !tasks[i].Completed && tasks[j].Completed })
+	return tasks, nil
+}
+
+func (r *MemoryTaskRepository) GetOpen(meetingIDs []int) ([]Task, error) {
+	d := r.data
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	tasks := []Task{}
+	for _, t := range d.tasks {
+		if !t.Completed && slices.Contains(meetingIDs, t.MeetingID) {
+			tasks = append(tasks, t)
+		}
+	}
+	return tasks, nil
+}
+
+func (r *MemoryTaskRepository) AddExtracted(meetingID int, extracted []ExtractedTask) ([]Task, error) {
+	d := r.data
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	existing := map[string]bool{}
+	for _, t := range d.tasks {
+		if t.MeetingID == meetingID {
+			existing[taskKey(t.Content)] = true
+		}
+	}
+
+	added := []Task{}
+	for _, e := range extracted {
+		if existing[taskKey(e.Content)] {
+			continue
+		}
+		existing[taskKey(e.Content)] = true
+
+		t := Task{
+			ID:        d.nextID(),
+			MeetingID: meetingID,
+			Content:   e.Content,
+			Assignee:  e.Assignee,
+			DueDate:   e.DueDate,
+			Priority:  e.Priority,
+			CreatedAt: memoryNow(),
+		}
+		d.tasks = append(d.tasks, t)
+		added = append(added, t)
+	}
+	return added, nil
+}
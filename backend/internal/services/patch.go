@@ -0,0 +1,265 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrInvalidPatch wraps validation failures of a MeetingPatch
+var ErrInvalidPatch = errors.New("invalid patch")
+
+// Length limits for editable meeting fields
+const (
+	maxTitleLength          = 200
+	maxDescriptionLength    = 1000
+	maxNotesLength          = 1 << 20
+	maxTranscriptLength     = 4 << 20
+	maxPromptTemplateLength = 2000
+	maxMeetingTags          = 50
+	maxTagLength            = 50
+	maxParticipants         = 100
+	maxParticipantLength    = 100
+)
+
+// languageCode matches the ISO 639-1 and 639-3 codes Whisper accepts
+var languageCode = regexp.MustCompile(`^[a-z]{2,3}$`)
+
+// MeetingPatch is a JSON Merge Patch (RFC 7396) of a meeting's editable
+// fields. Nil fields are left unchanged; a JSON null arrives as the field's
+// zero value, so it clears the field. FolderID only applies when SetFolder is
+// true, nil then moving the meeting to the top level.
+type MeetingPatch struct {
+	Title          *string // Empty resets to DefaultMeetingTitle
+	Description    *string
+	Notes          *string
+	NotesSource    string // Revision source of Notes, NoteSourceManual when empty
+	Transcript     *string
+	MeetingType    *string
+	AutoTitle      *bool
+	Tags           *[]string // Replaces all tags; listed tags become manual
+	SetFolder      bool
+	FolderID       *int
+	Language       *string
+	Participants   *[]string // Replaces the participant list
+	Glossary       *string
+	PromptTemplate *string
+	RetentionDays  *int
+	Workspace      *string // Empty resets to DefaultWorkspace
+}
+
+// Validate checks a patch's values and lengths without touching the database
+func (p *MeetingPatch) Validate() error {
+	invalid := func(format string, args ...interface{}) error {
+		return fmt.Errorf("%w: %s", ErrInvalidPatch, fmt.Sprintf(format, args...))
+	}
+
+	lengths := []struct {
+		field string
+		value *string
+		max   int
+	}{
+		{"title", p.Title, maxTitleLength},
+		{"description", p.Description, maxDescriptionLength},
+		{"notes", p.Notes, maxNotesLength},
+		{"transcript", p.Transcript, maxTranscriptLength},
+		{"glossary", p.Glossary, maxGlossaryLength},
+		{"prompt_template", p.PromptTemplate, maxPromptTemplateLength},
+	}
+	for _, l := range lengths {
+		if l.value != nil && utf8.RuneCountInString(*l.value) > l.max {
+			return invalid("%s must be at most %d characters", l.field, l.max)
+		}
+	}
+
+	if p.NotesSource != "" && !ValidNoteSource(p.NotesSource) {
+		return invalid("unknown notes_source %q, use 'manual' or an AI action name", p.NotesSource)
+	}
+	if p.MeetingType != nil && !ValidMeetingType(*p.MeetingType) {
+		return invalid("unknown meeting type %q, use one of %s", *p.MeetingType, strings.Join(MeetingTypes, ", "))
+	}
+	if p.Language != nil && *p.Language != "" && !languageCode.MatchString(*p.Language) {
+		return invalid("language must be an ISO 639 code like \"en\", or empty to auto-detect")
+	}
+	if p.RetentionDays != nil && *p.RetentionDays < 0 {
+		return invalid("retention_days must not be negative")
+	}
+	if p.Workspace != nil && *p.Workspace != "" && !ValidWorkspace(*p.Workspace) {
+		return invalid("invalid workspace %q, %s", *p.Workspace, workspaceRule)
+	}
+
+	if p.Tags != nil {
+		if len(*p.Tags) > maxMeetingTags {
+			return invalid("a meeting can have at most %d tags", maxMeetingTags)
+		}
+		for _, name := range *p.Tags {
+			name = NormalizeTagName(name)
+			if name == "" {
+				return invalid("tag names must not be empty")
+			}
+			if utf8.RuneCountInString(name) > maxTagLength {
+				return invalid("tag names must be at most %d characters", maxTagLength)
+			}
+		}
+	}
+
+	if p.Participants != nil {
+		if len(*p.Participants) > maxParticipants {
+			return invalid("a meeting can have at most %d participants", maxParticipants)
+		}
+		for _, name := range *p.Participants {
+			if strings.TrimSpace(name) == "" {
+				return invalid("participant names must not be empty")
+			}
+			if utf8.RuneCountInString(strings.TrimSpace(name)) > maxParticipantLength {
+				return invalid("participant names must be at most %d characters", maxParticipantLength)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Patch applies a merge patch to a meeting in a single transaction and
+// returns the updated meeting, or nil if there is no such meeting. With
+// versions (from If-Match) it fails with ErrVersionConflict unless the
+// meeting's current version is one of them. Changed notes are recorded as a
+// revision by author.
+func (s *MeetingService) Patch(id int, patch MeetingPatch, author string, versions []int) (*Meeting, error) {
+	if err := patch.Validate(); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Claim the next version first, so concurrent patches can't both pass the check
+	query := "UPDATE meetings SET version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND deleted_at IS NULL"
+	args := []interface{}{id}
+	if versions != nil {
+		query += " AND version IN (" + placeholders(len(versions)) + ")"
+		for _, v := range versions {
+			args = append(args, v)
+		}
+	}
+	result, err := tx.Exec(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		var exists int
+		err := tx.QueryRow("SELECT COUNT(*) FROM meetings WHERE id = ? AND deleted_at IS NULL", id).Scan(&exists)
+		if err != nil || exists == 0 {
+			return nil, err
+		}
+		return nil, ErrVersionConflict
+	}
+
+	if patch.Transcript != nil {
+		var recording bool
+		if err := tx.QueryRow("SELECT is_recording FROM meetings WHERE id = ?", id).Scan(&recording); err != nil {
+			return nil, err
+		}
+		if recording {
+			return nil, ErrStillRecording
+		}
+	}
+	if patch.SetFolder && patch.FolderID != nil {
+		var found int
+		err := tx.QueryRow("SELECT COUNT(*) FROM folders WHERE id = ?", *patch.FolderID).Scan(&found)
+		if err != nil {
+			return nil, err
+		}
+		if found == 0 {
+			return nil, fmt.Errorf("%w: folder %d does not exist", ErrInvalidPatch, *patch.FolderID)
+		}
+	}
+
+	var sets []string
+	var values []interface{}
+	set := func(column string, value interface{}) {
+		sets = append(sets, column+" = ?")
+		values = append(values, value)
+	}
+
+	if patch.Title != nil {
+		title := strings.TrimSpace(*patch.Title)
+		if title == "" {
+			title = DefaultMeetingTitle
+		}
+		set("title", title)
+	}
+	if patch.Description != nil {
+		set("description", *patch.Description)
+	}
+	if patch.Notes != nil {
+		set("notes", *patch.Notes)
+	}
+	if patch.Transcript != nil {
+		set("transcript", *patch.Transcript)
+	}
+	if patch.MeetingType != nil {
+		set("meeting_type", *patch.MeetingType)
+	}
+	if patch.AutoTitle != nil {
+		set("auto_title", *patch.AutoTitle)
+	}
+	if patch.SetFolder {
+		set("folder_id", patch.FolderID)
+	}
+	if patch.Language != nil {
+		set("language", *patch.Language)
+	}
+	if patch.Glossary != nil {
+		set("glossary", *patch.Glossary)
+	}
+	if patch.PromptTemplate != nil {
+		set("prompt_template", *patch.PromptTemplate)
+	}
+	if patch.RetentionDays != nil {
+		set("retention_days", *patch.RetentionDays)
+	}
+	if patch.Workspace != nil {
+		set("workspace", FolderDefaults{Workspace: *patch.Workspace}.workspace())
+	}
+
+	if len(sets) > 0 {
+		if _, err := tx.Exec("UPDATE meetings SET "+strings.Join(sets, ", ")+" WHERE id = ?", append(values, id)...); err != nil {
+			return nil, err
+		}
+	}
+
+	if patch.Notes != nil {
+		source := patch.NotesSource
+		if source == "" {
+			source = NoteSourceManual
+		}
+		if err := recordRevision(tx, id, *patch.Notes, author, source); err != nil {
+			return nil, err
+		}
+	}
+	if patch.Tags != nil {
+		if err := setMeetingTags(tx, id, *patch.Tags); err != nil {
+			return nil, err
+		}
+	}
+	if patch.Participants != nil {
+		if err := setParticipants(tx, id, *patch.Participants); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	if err := indexMeeting(s.db, id); err != nil {
+		return nil, err
+	}
+	return s.GetByID(id)
+}
@@ -0,0 +1,679 @@
+package services
+
+import (
+	"backend/internal/database"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// Participant roles
+const (
+	ParticipantRoleHost     = "host"
+	ParticipantRoleAttendee = "attendee"
+	ParticipantRoleExternal = "external"
+)
+
+// ParticipantRoles are the values accepted for Participant.Role
+var ParticipantRoles = []string{ParticipantRoleHost, ParticipantRoleAttendee, ParticipantRoleExternal}
+
+// maxAliases caps the aliases stored per person
+const maxAliases = 20
+
+var (
+	// ErrDuplicateEmail is returned when a person would share an email with another
+	ErrDuplicateEmail = errors.New("another person already has this email")
+	// ErrDuplicateParticipant is returned when someone is added to a meeting twice
+	ErrDuplicateParticipant = errors.New("this person is already a participant")
+)
+
+type Person struct {
+	ID           int       `json:"id"`
+	Name         string    `json:"name"`
+	Email        string    `json:"email"`
+	Aliases      []string  `json:"aliases"`
+	MeetingCount int       `json:"meeting_count"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// PersonInput holds the editable fields of a person
+type PersonInput struct {
+	Name    string   `json:"name" binding:"required"`
+	Email   string   `json:"email"`
+	Aliases []string `json:"aliases"`
+}
+
+// Participant is someone who took part in a meeting. PersonID is nil for
+// names that aren't in the people directory.
+type Participant struct {
+	ID        int    `json:"id"`
+	MeetingID int    `json:"meeting_id"`
+	PersonID  *int   `json:"person_id"`
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	Speaker   string `json:"speaker"` // Diarized speaker label mapped to this participant
+}
+
+type PeopleService struct {
+	db *sql.DB
+}
+
+func NewPeopleService(db *sql.DB) *PeopleService {
+	return &PeopleService{db: db}
+}
+
+// ValidParticipantRole reports whether role is one of ParticipantRoles
+func ValidParticipantRole(role string) bool {
+	return contains(ParticipantRoles, role)
+}
+
+const personColumns = `p.id, p.name, p.email, p.created_at,
+	(SELECT COUNT(*) FROM meeting_participants mp JOIN meetings m ON m.id = mp.meeting_id
+	 WHERE mp.person_id = p.id AND m.deleted_at IS NULL)`
+
+func scanPerson(row rowScanner) (*Person, error) {
+	var p Person
+	var createdAt int64
+	if err := row.Scan(&p.ID, &p.Name, &p.Email, &createdAt, &p.MeetingCount); err != nil {
+		return nil, err
+	}
+	p.CreatedAt = time.Unix(createdAt, 0)
+	p.Aliases = []string{}
+	return &p, nil
+}
+
+// GetAll lists people by name. A non-empty query matches names, emails and aliases.
+func (s *PeopleService) GetAll(query string) ([]Person, error) {
+	sqlQuery := "SELECT " + personColumns + " FROM people p"
+	var args []interface{}
+	if query = strings.TrimSpace(query); query != "" {
+		pattern := "%" + query + "%"
+		sqlQuery += ` WHERE LOWER(p.name) LIKE LOWER(?) OR LOWER(p.email) LIKE LOWER(?)
+			OR p.id IN (SELECT person_id FROM person_aliases WHERE LOWER(alias) LIKE LOWER(?))`
+		args = append(args, pattern, pattern, pattern)
+	}
+	sqlQuery += " ORDER BY LOWER(p.name)"
+
+	rows, err := s.db.Query(sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	people := []Person{}
+	for rows.Next() {
+		p, err := scanPerson(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		people = append(people, *p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range people {
+		if people[i].Aliases, err = aliases(s.db, people[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return people, nil
+}
+
+// GetByID returns a person, or nil if they don't exist
+func (s *PeopleService) GetByID(id int) (*Person, error) {
+	p, err := scanPerson(s.db.QueryRow("SELECT "+personColumns+" FROM people p WHERE p.id = ?", id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	p.Aliases, err = aliases(s.db, id)
+	return p, err
+}
+
+// Create adds a person to the directory
+func (s *PeopleService) Create(input PersonInput) (*Person, error) {
+	input, err := normalizePerson(input)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var id int
+	err = tx.QueryRow("INSERT INTO people (name, email, created_at) VALUES (?, ?, ?) RETURNING id", input.Name, input.Email, time.Now().Unix()).Scan(&id)
+	if err != nil {
+		return nil, personError(err)
+	}
+	if err := setAliases(tx, id, input.Aliases); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(id)
+}
+
+// Update replaces a person's name, email and aliases. Participant entries
+// linked to them take the new name.
+func (s *PeopleService) Update(id int, input PersonInput) (*Person, error) {
+	input, err := normalizePerson(input)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("UPDATE people SET name = ?, email = ? WHERE id = ?", input.Name, input.Email, id)
+	if err != nil {
+		return nil, personError(err)
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return nil, nil
+	}
+	if err := setAliases(tx, id, input.Aliases); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec("UPDATE meeting_participants SET name = ? WHERE person_id = ?", input.Name, id); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(id)
+}
+
+// Delete removes a person from the directory. Meetings keep them as a
+// participant by name.
+func (s *PeopleService) Delete(id int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, query := range []string{
+		"UPDATE meeting_participants SET person_id = NULL WHERE person_id = ?",
+		"DELETE FROM person_aliases WHERE person_id = ?",
+		"DELETE FROM people WHERE id = ?",
+	} {
+		if _, err := tx.Exec(query, id); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetParticipants lists a meeting's participants in the order they were added
+func (s *PeopleService) GetParticipants(meetingID int) ([]Participant, error) {
+	rows, err := s.db.Query(`
+		SELECT mp.id, mp.meeting_id, mp.person_id, mp.name, COALESCE(p.email, ''), mp.role, mp.speaker
+		FROM meeting_participants mp LEFT JOIN people p ON p.id = mp.person_id
+		WHERE mp.meeting_id = ?
+		ORDER BY mp.position, mp.id
+	`, meetingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	participants := []Participant{}
+	for rows.Next() {
+		var p Participant
+		var personID sql.NullInt64
+		if err := rows.Scan(&p.ID, &p.MeetingID, &personID, &p.Name, &p.Email, &p.Role, &p.Speaker); err != nil {
+			return nil, err
+		}
+		p.PersonID = nullableID(personID)
+		participants = append(participants, p)
+	}
+
+	return participants, rows.Err()
+}
+
+// getParticipant returns one participant of a meeting, or nil
+func (s *PeopleService) getParticipant(meetingID, id int) (*Participant, error) {
+	participants, err := s.GetParticipants(meetingID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range participants {
+		if p.ID == id {
+			return &p, nil
+		}
+	}
+	return nil, nil
+}
+
+// AddParticipant adds someone to a meeting, either a person from the
+// directory or a name, which is linked to the person it matches if any
+func (s *PeopleService) AddParticipant(meetingID int, personID *int, name, role string) (*Participant, error) {
+	if role == "" {
+		role = ParticipantRoleAttendee
+	}
+	if !ValidParticipantRole(role) {
+		return nil, fmt.Errorf("unknown role %q, use one of %s", role, strings.Join(ParticipantRoles, ", "))
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if personID != nil {
+		if err := tx.QueryRow("SELECT name FROM people WHERE id = ?", *personID).Scan(&name); err == sql.ErrNoRows {
+			return nil, fmt.Errorf("person %d does not exist", *personID)
+		} else if err != nil {
+			return nil, err
+		}
+	} else {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, fmt.Errorf("participants need a person_id or a name")
+		}
+		if len(name) > maxParticipantLength {
+			return nil, fmt.Errorf("participant names must be at most %d characters", maxParticipantLength)
+		}
+		if personID, err = findPersonID(tx, name); err != nil {
+			return nil, err
+		}
+		if personID != nil {
+			if err := tx.QueryRow("SELECT name FROM people WHERE id = ?", *personID).Scan(&name); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var duplicates int
+	if err := tx.QueryRow(
+		"SELECT COUNT(*) FROM meeting_participants WHERE meeting_id = ? AND (person_id = ? OR LOWER(name) = LOWER(?))",
+		meetingID, personID, name,
+	).Scan(&duplicates); err != nil {
+		return nil, err
+	}
+	if duplicates > 0 {
+		return nil, ErrDuplicateParticipant
+	}
+
+	var id int
+	err = tx.QueryRow(`
+		INSERT INTO meeting_participants (meeting_id, person_id, name, role, position)
+		VALUES (?, ?, ?, ?, (SELECT COALESCE(MAX(position) + 1, 0) FROM meeting_participants WHERE meeting_id = ?))
+		RETURNING id
+	`, meetingID, personID, name, role, meetingID).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	if err := bumpVersion(tx, meetingID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return s.getParticipant(meetingID, int(id))
+}
+
+// SetParticipantRole changes a participant's role, returning nil if there is no such participant
+func (s *PeopleService) SetParticipantRole(meetingID, id int, role string) (*Participant, error) {
+	if !ValidParticipantRole(role) {
+		return nil, fmt.Errorf("unknown role %q, use one of %s", role, strings.Join(ParticipantRoles, ", "))
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("UPDATE meeting_participants SET role = ? WHERE id = ? AND meeting_id = ?", role, id, meetingID)
+	if err != nil {
+		return nil, err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return nil, nil
+	}
+	if err := bumpVersion(tx, meetingID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return s.getParticipant(meetingID, id)
+}
+
+// RemoveParticipant takes someone off a meeting's participant list
+func (s *PeopleService) RemoveParticipant(meetingID, id int) (bool, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("DELETE FROM meeting_participants WHERE id = ? AND meeting_id = ?", id, meetingID)
+	if err != nil {
+		return false, err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return false, nil
+	}
+	if err := bumpVersion(tx, meetingID); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// MapSpeaker attributes a diarized speaker label to a participant: their
+// transcript segments are relabelled with the participant's name. Mapping a
+// label again moves those segments on to the new participant. It returns the
+// number of relabelled segments, and nil if there is no such participant.
+func (s *PeopleService) MapSpeaker(meetingID int, speaker string, participantID int) (*Participant, int, error) {
+	speaker = strings.TrimSpace(speaker)
+	if speaker == "" {
+		return nil, 0, fmt.Errorf("speaker must not be empty")
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, 0, err
+	}
+	defer tx.Rollback()
+
+	var name string
+	err = tx.QueryRow("SELECT name FROM meeting_participants WHERE id = ? AND meeting_id = ?", participantID, meetingID).Scan(&name)
+	if err == sql.ErrNoRows {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Segments already relabelled for a previous mapping of this label carry that participant's name
+	labels := []interface{}{speaker}
+	var previous string
+	err = tx.QueryRow(
+		"SELECT name FROM meeting_participants WHERE meeting_id = ? AND speaker = ? AND id != ?",
+		meetingID, speaker, participantID,
+	).Scan(&previous)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, 0, err
+	}
+	if previous != "" {
+		labels = append(labels, previous)
+	}
+
+	if _, err := tx.Exec("UPDATE meeting_participants SET speaker = '' WHERE meeting_id = ? AND speaker = ?", meetingID, speaker); err != nil {
+		return nil, 0, err
+	}
+	if _, err := tx.Exec("UPDATE meeting_participants SET speaker = ? WHERE id = ?", speaker, participantID); err != nil {
+		return nil, 0, err
+	}
+
+	result, err := tx.Exec(
+		"UPDATE transcript_segments SET speaker = ? WHERE meeting_id = ? AND speaker IN ("+placeholders(len(labels))+")",
+		append([]interface{}{name, meetingID}, labels...)...,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	relabelled, err := result.RowsAffected()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := bumpVersion(tx, meetingID); err != nil {
+		return nil, 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, 0, err
+	}
+
+	participant, err := s.getParticipant(meetingID, participantID)
+	return participant, int(relabelled), err
+}
+
+// ParticipantEmails returns the email addresses of a meeting's participants that have one
+func (s *PeopleService) ParticipantEmails(meetingID int) ([]string, error) {
+	participants, err := s.GetParticipants(meetingID)
+	if err != nil {
+		return nil, err
+	}
+
+	var emails []string
+	for _, p := range participants {
+		if p.Email != "" {
+			emails = append(emails, p.Email)
+		}
+	}
+	return emails, nil
+}
+
+// setParticipants makes names a meeting's participant list. Participants
+// still listed keep their role and speaker; new names are linked to the
+// person they match and take that person's name.
+func setParticipants(tx *sql.Tx, meetingID int, names []string) error {
+	rows, err := tx.Query("SELECT id, name, person_id FROM meeting_participants WHERE meeting_id = ?", meetingID)
+	if err != nil {
+		return err
+	}
+	byName, byPerson := map[string]int{}, map[int]int{}
+	for rows.Next() {
+		var id int
+		var name string
+		var personID sql.NullInt64
+		if err := rows.Scan(&id, &name, &personID); err != nil {
+			rows.Close()
+			return err
+		}
+		byName[strings.ToLower(name)] = id
+		if personID.Valid {
+			byPerson[int(personID.Int64)] = id
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	var keep []interface{}
+	kept := map[int]bool{}
+	position := 0
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		id, found := byName[strings.ToLower(name)]
+
+		var personID *int
+		if !found {
+			if personID, err = findPersonID(tx, name); err != nil {
+				return err
+			}
+			if personID != nil {
+				id, found = byPerson[*personID]
+				if err := tx.QueryRow("SELECT name FROM people WHERE id = ?", *personID).Scan(&name); err != nil {
+					return err
+				}
+			}
+		}
+		if found && kept[id] {
+			continue
+		}
+
+		if found {
+			if _, err := tx.Exec("UPDATE meeting_participants SET position = ? WHERE id = ?", position, id); err != nil {
+				return err
+			}
+		} else {
+			err := tx.QueryRow(
+				"INSERT INTO meeting_participants (meeting_id, person_id, name, position) VALUES (?, ?, ?, ?) RETURNING id",
+				meetingID, personID, name, position,
+			).Scan(&id)
+			if err != nil {
+				return err
+			}
+			byName[strings.ToLower(name)] = id
+			if personID != nil {
+				byPerson[*personID] = id
+			}
+		}
+		kept[id] = true
+		keep = append(keep, id)
+		position++
+	}
+
+	query := "DELETE FROM meeting_participants WHERE meeting_id = ?"
+	if len(keep) > 0 {
+		query += " AND id NOT IN (" + placeholders(len(keep)) + ")"
+	}
+	_, err = tx.Exec(query, append([]interface{}{meetingID}, keep...)...)
+	return err
+}
+
+// participantNames returns a meeting's participants in the order they were listed
+func participantNames(q querier, meetingID int) ([]string, error) {
+	rows, err := q.Query("SELECT name FROM meeting_participants WHERE meeting_id = ? ORDER BY position, id", meetingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+
+	return names, rows.Err()
+}
+
+// findPersonID returns the person whose name or alias is name, or nil when
+// nobody or more than one person matches
+func findPersonID(tx *sql.Tx, name string) (*int, error) {
+	rows, err := tx.Query(`
+		SELECT id FROM people WHERE LOWER(name) = LOWER(?)
+		UNION SELECT person_id FROM person_aliases WHERE LOWER(alias) = LOWER(?)
+		LIMIT 2
+	`, name, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil || len(ids) != 1 {
+		return nil, err
+	}
+	return &ids[0], nil
+}
+
+// bumpVersion marks a meeting as edited for optimistic concurrency
+func bumpVersion(tx *sql.Tx, meetingID int) error {
+	_, err := tx.Exec("UPDATE meetings SET version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = ?", meetingID)
+	return err
+}
+
+func aliases(q querier, personID int) ([]string, error) {
+	rows, err := q.Query("SELECT alias FROM person_aliases WHERE person_id = ? ORDER BY LOWER(alias)", personID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := []string{}
+	for rows.Next() {
+		var alias string
+		if err := rows.Scan(&alias); err != nil {
+			return nil, err
+		}
+		names = append(names, alias)
+	}
+
+	return names, rows.Err()
+}
+
+func setAliases(tx *sql.Tx, personID int, aliases []string) error {
+	if _, err := tx.Exec("DELETE FROM person_aliases WHERE person_id = ?", personID); err != nil {
+		return err
+	}
+	for _, alias := range aliases {
+		if _, err := tx.Exec("INSERT INTO person_aliases (person_id, alias) VALUES (?, ?) ON CONFLICT DO NOTHING", personID, alias); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// normalizePerson trims and validates a person's fields
+func normalizePerson(input PersonInput) (PersonInput, error) {
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Name == "" {
+		return input, fmt.Errorf("name must not be empty")
+	}
+	if len(input.Name) > maxParticipantLength {
+		return input, fmt.Errorf("name must be at most %d characters", maxParticipantLength)
+	}
+
+	if input.Email = strings.TrimSpace(input.Email); input.Email != "" {
+		address, err := mail.ParseAddress(input.Email)
+		if err != nil || address.Address != input.Email {
+			return input, fmt.Errorf("invalid email address %q", input.Email)
+		}
+	}
+
+	if len(input.Aliases) > maxAliases {
+		return input, fmt.Errorf("a person can have at most %d aliases", maxAliases)
+	}
+	var aliases []string
+	for _, alias := range input.Aliases {
+		alias = strings.TrimSpace(alias)
+		if alias == "" || strings.EqualFold(alias, input.Name) {
+			continue
+		}
+		if len(alias) > maxParticipantLength {
+			return input, fmt.Errorf("aliases must be at most %d characters", maxParticipantLength)
+		}
+		aliases = append(aliases, alias)
+	}
+	input.Aliases = aliases
+
+	return input, nil
+}
+
+// personError turns the unique email index violation into ErrDuplicateEmail
+func personError(err error) error {
+	if database.IsUniqueViolation(err) {
+		return ErrDuplicateEmail
+	}
+	return err
+}
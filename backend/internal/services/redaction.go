@@ -0,0 +1,491 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultWorkspace is the workspace used until Echo supports more than one
+const DefaultWorkspace = "default"
+
+// workspaceName matches the names of workspaces, described by workspaceRule
+var workspaceName = regexp.MustCompile(`^[A-Za-z0-9_-]{1,50}$`)
+
+const workspaceRule = "use up to 50 letters, digits, '-' or '_'"
+
+// ValidWorkspace reports whether name can be used as a workspace
+func ValidWorkspace(name string) bool {
+	return workspaceName.MatchString(name)
+}
+
+// Detector names usable in a RedactionPolicy
+const (
+	DetectorEmail = "email"
+	DetectorIBAN  = "iban"
+	DetectorCard  = "card"
+	DetectorPhone = "phone"
+	DetectorName  = "name"
+)
+
+var allDetectors = []string{DetectorEmail, DetectorIBAN, DetectorCard, DetectorPhone, DetectorName}
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	ibanPattern  = regexp.MustCompile(`\b[A-Z]{2}\d{2}(?:[ ]?[A-Z0-9]{4}){2,7}(?:[ ]?[A-Z0-9]{1,3})?\b`)
+	cardPattern  = regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`)
+	phonePattern = regexp.MustCompile(`(?:\+|\b)\d[\d ().-]{6,}\d\b`)
+)
+
+type RedactionPolicy struct {
+	Workspace string   `json:"workspace"`
+	Enabled   bool     `json:"enabled"`
+	Detectors []string `json:"detectors"`
+}
+
+type RedactionTerm struct {
+	ID        int    `json:"id"`
+	Workspace string `json:"workspace"`
+	Term      string `json:"term"`
+}
+
+// RedactionReport summarizes what was removed from a single outgoing AI call.
+// It never contains the original values.
+type RedactionReport struct {
+	ID           int            `json:"id"`
+	Workspace    string         `json:"workspace"`
+	Action       string         `json:"action"`
+	Counts       map[string]int `json:"counts"`
+	Placeholders []string       `json:"placeholders"`
+	CreatedAt    time.Time      `json:"created_at"`
+}
+
+// Redaction holds the placeholder mapping for one call so the response can be re-hydrated
+type Redaction struct {
+	Text     string
+	Report   RedactionReport
+	original map[string]string
+}
+
+type RedactionService struct {
+	db *sql.DB
+
+	mu sync.Mutex
+	// termPatterns caches each workspace's deny-list compiled into one
+	// pattern, nil when the list is empty
+	termPatterns map[string]*regexp.Regexp
+}
+
+func NewRedactionService(db *sql.DB) *RedactionService {
+	return &RedactionService{db: db, termPatterns: map[string]*regexp.Regexp{}}
+}
+
+// GetPolicy returns the workspace policy, defaulting to every detector enabled
+func (s *RedactionService) GetPolicy(workspace string) (*RedactionPolicy, error) {
+	var enabled bool
+	var detectors string
+	err := s.db.QueryRow(
+		"SELECT enabled, detectors FROM redaction_policies WHERE workspace = ?", workspace,
+	).Scan(&enabled, &detectors)
+	if err == sql.ErrNoRows {
+		return &RedactionPolicy{Workspace: workspace, Enabled: true, Detectors: allDetectors}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	policy := &RedactionPolicy{Workspace: workspace, Enabled: enabled, Detectors: []string{}}
+	if detectors != "" {
+		policy.Detectors = strings.Split(detectors, ",")
+	}
+	return policy, nil
+}
+
+// SetPolicy stores the workspace policy
+func (s *RedactionService) SetPolicy(policy *RedactionPolicy) error {
+	for _, d := range policy.Detectors {
+		if !contains(allDetectors, d) {
+			return fmt.Errorf("unknown detector %q, use one of %s", d, strings.Join(allDetectors, ", "))
+		}
+	}
+
+	_, err := s.db.Exec(`
+		INSERT INTO redaction_policies (workspace, enabled, detectors) VALUES (?, ?, ?)
+		ON CONFLICT(workspace) DO UPDATE SET enabled = excluded.enabled, detectors = excluded.detectors
+	`, policy.Workspace, policy.Enabled, strings.Join(policy.Detectors, ","))
+	return err
+}
+
+// GetTerms lists the deny-listed names of a workspace
+func (s *RedactionService) GetTerms(workspace string) ([]RedactionTerm, error) {
+	rows, err := s.db.Query("SELECT id, workspace, term FROM redaction_terms WHERE workspace = ? ORDER BY term", workspace)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var terms []RedactionTerm
+	for rows.Next() {
+		var t RedactionTerm
+		if err := rows.Scan(&t.ID, &t.Workspace, &t.Term); err != nil {
+			return nil, err
+		}
+		terms = append(terms, t)
+	}
+
+	return terms, rows.Err()
+}
+
+// AddTerm adds a name to the workspace deny-list
+func (s *RedactionService) AddTerm(workspace, term string) (*RedactionTerm, error) {
+	term = strings.TrimSpace(term)
+	if term == "" {
+		return nil, fmt.Errorf("term must not be empty")
+	}
+
+	// The no-op update makes RETURNING report the existing term's ID too
+	var id int
+	err := s.db.QueryRow(`
+		INSERT INTO redaction_terms (workspace, term) VALUES (?, ?)
+		ON CONFLICT(workspace, term) DO UPDATE SET term = excluded.term
+		RETURNING id
+	`, workspace, term).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.compileTerms(workspace); err != nil {
+		return nil, err
+	}
+	return &RedactionTerm{ID: id, Workspace: workspace, Term: term}, nil
+}
+
+// DeleteTerm removes a name from the deny-list
+func (s *RedactionService) DeleteTerm(id int) error {
+	var workspace string
+	err := s.db.QueryRow("DELETE FROM redaction_terms WHERE id = ? RETURNING workspace", id).Scan(&workspace)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	_, err = s.compileTerms(workspace)
+	return err
+}
+
+// termPattern returns the workspace's compiled deny-list, compiling it on
+// first use
+func (s *RedactionService) termPattern(workspace string) (*regexp.Regexp, error) {
+	s.mu.Lock()
+	pattern, ok := s.termPatterns[workspace]
+	s.mu.Unlock()
+	if ok {
+		return pattern, nil
+	}
+	return s.compileTerms(workspace)
+}
+
+// compileTerms compiles the workspace's deny-list and caches it. The lock is
+// held while reading the terms so a stale list can't overwrite a newer one.
+func (s *RedactionService) compileTerms(workspace string) (*regexp.Regexp, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	terms, err := s.GetTerms(workspace)
+	if err != nil {
+		return nil, err
+	}
+	pattern := termsPattern(terms)
+	s.termPatterns[workspace] = pattern
+	return pattern, nil
+}
+
+// termsPattern matches any of the terms, case-insensitively. Whole-word
+// boundaries are checked by termMatches, since \b doesn't work for terms
+// that start or end with punctuation like "C++" or "@acme".
+func termsPattern(terms []RedactionTerm) *regexp.Regexp {
+	if len(terms) == 0 {
+		return nil
+	}
+
+	// Longest first, so a term isn't cut short by another that prefixes it
+	quoted := make([]string, len(terms))
+	for i, t := range terms {
+		quoted[i] = regexp.QuoteMeta(t.Term)
+	}
+	sort.Slice(quoted, func(i, j int) bool { return len(quoted[i]) > len(quoted[j]) })
+
+	return regexp.MustCompile(`(?i)(?:` + strings.Join(quoted, "|") + `)`)
+}
+
+// termMatches returns the start and end of every whole-word match of pattern.
+// The characters around a match are checked rather than matched, so names
+// separated by a single space or comma are all found.
+func termMatches(pattern *regexp.Regexp, text string) [][]int {
+	var matches [][]int
+	for start := 0; start < len(text); {
+		loc := pattern.FindStringIndex(text[start:])
+		if loc == nil {
+			break
+		}
+		from, to := start+loc[0], start+loc[1]
+
+		before, _ := utf8.DecodeLastRuneInString(text[:from])
+		after, _ := utf8.DecodeRuneInString(text[to:])
+		if (from == 0 || !isWordRune(before)) && (to == len(text) || !isWordRune(after)) {
+			matches = append(matches, []int{from, to})
+			start = to
+			continue
+		}
+
+		// Not a whole word, so look again from the next character
+		_, size := utf8.DecodeRuneInString(text[from:])
+		start = from + size
+	}
+	return matches
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// GetReports lists the most recent redaction reports of a workspace
+func (s *RedactionService) GetReports(workspace string, limit int) ([]RedactionReport, error) {
+	rows, err := s.db.Query(
+		"SELECT id, workspace, action, counts, placeholders, created_at FROM redaction_reports WHERE workspace = ? ORDER BY id DESC LIMIT ?",
+		workspace, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reports []RedactionReport
+	for rows.Next() {
+		var r RedactionReport
+		var counts, placeholders string
+		var createdAt int64
+		if err := rows.Scan(&r.ID, &r.Workspace, &r.Action, &counts, &placeholders, &createdAt); err != nil {
+			return nil, err
+		}
+		json.Unmarshal([]byte(counts), &r.Counts)
+		json.Unmarshal([]byte(placeholders), &r.Placeholders)
+		r.CreatedAt = time.Unix(createdAt, 0).UTC()
+		reports = append(reports, r)
+	}
+
+	return reports, rows.Err()
+}
+
+// Redact replaces sensitive values in text with stable placeholders such as
+// [EMAIL_1]. The same value always maps to the same placeholder within a call.
+func (s *RedactionService) Redact(workspace, action, text string) (*Redaction, error) {
+	r := &Redaction{
+		Text:     text,
+		Report:   RedactionReport{Workspace: workspace, Action: action, Counts: map[string]int{}, Placeholders: []string{}},
+		original: map[string]string{},
+	}
+
+	policy, err := s.GetPolicy(workspace)
+	if err != nil {
+		return nil, err
+	}
+	if !policy.Enabled {
+		return r, nil
+	}
+
+	placeholders := map[string]string{}
+	replace := func(kind string, matches []string) {
+		for _, value := range matches {
+			if _, seen := placeholders[value]; seen {
+				continue
+			}
+			r.Report.Counts[kind]++
+			placeholder := fmt.Sprintf("[%s_%d]", strings.ToUpper(kind), r.Report.Counts[kind])
+			placeholders[value] = placeholder
+			r.original[placeholder] = value
+			r.Report.Placeholders = append(r.Report.Placeholders, placeholder)
+		}
+	}
+
+	// Longer, more specific patterns first so a card number isn't half-eaten by the phone detector
+	if contains(policy.Detectors, DetectorEmail) {
+		replace(DetectorEmail, emailPattern.FindAllString(r.Text, -1))
+		r.Text = substitute(r.Text, placeholders)
+	}
+	if contains(policy.Detectors, DetectorIBAN) {
+		replace(DetectorIBAN, filter(ibanPattern.FindAllString(r.Text, -1), validIBAN))
+		r.Text = substitute(r.Text, placeholders)
+	}
+	if contains(policy.Detectors, DetectorCard) {
+		replace(DetectorCard, filter(cardPattern.FindAllString(r.Text, -1), validLuhn))
+		r.Text = substitute(r.Text, placeholders)
+	}
+	if contains(policy.Detectors, DetectorPhone) {
+		replace(DetectorPhone, filter(phonePattern.FindAllString(r.Text, -1), likelyPhone))
+		r.Text = substitute(r.Text, placeholders)
+	}
+	if contains(policy.Detectors, DetectorName) {
+		pattern, err := s.termPattern(workspace)
+		if err != nil {
+			return nil, err
+		}
+		if pattern != nil {
+			// Replaced by position, as a name can also appear inside a
+			// longer word ("Ana" in "Anabel") that must be left alone
+			var text strings.Builder
+			last := 0
+			for _, m := range termMatches(pattern, r.Text) {
+				name := r.Text[m[0]:m[1]]
+				replace(DetectorName, []string{name})
+				text.WriteString(r.Text[last:m[0]])
+				text.WriteString(placeholders[name])
+				last = m[1]
+			}
+			text.WriteString(r.Text[last:])
+			r.Text = text.String()
+		}
+	}
+
+	return r, nil
+}
+
+// Restore re-hydrates placeholders in a model response. When jsonEncoded is
+// set the originals are escaped so they can't break a JSON response.
+func (r *Redaction) Restore(text string, jsonEncoded bool) string {
+	if len(r.original) == 0 {
+		return text
+	}
+
+	pairs := make([]string, 0, len(r.original)*2)
+	for placeholder, value := range r.original {
+		if jsonEncoded {
+			encoded, _ := json.Marshal(value)
+			value = string(encoded[1 : len(encoded)-1])
+		}
+		pairs = append(pairs, placeholder, value)
+	}
+	return strings.NewReplacer(pairs...).Replace(text)
+}
+
+// SaveReport records what was redacted, if anything
+func (s *RedactionService) SaveReport(report *RedactionReport) error {
+	if len(report.Placeholders) == 0 {
+		return nil
+	}
+
+	counts, _ := json.Marshal(report.Counts)
+	placeholders, _ := json.Marshal(report.Placeholders)
+	report.CreatedAt = time.Now().UTC().Truncate(time.Second)
+
+	return s.db.QueryRow(
+		"INSERT INTO redaction_reports (workspace, action, counts, placeholders, created_at) VALUES (?, ?, ?, ?, ?) RETURNING id",
+		report.Workspace, report.Action, string(counts), string(placeholders), report.CreatedAt.Unix(),
+	).Scan(&report.ID)
+}
+
+// substitute replaces every known value with its placeholder, longest value
+// first so overlapping values don't leave fragments behind
+func substitute(text string, placeholders map[string]string) string {
+	values := make([]string, 0, len(placeholders))
+	for v := range placeholders {
+		values = append(values, v)
+	}
+	sort.Slice(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+
+	pairs := make([]string, 0, len(values)*2)
+	for _, v := range values {
+		pairs = append(pairs, v, placeholders[v])
+	}
+	return strings.NewReplacer(pairs...).Replace(text)
+}
+
+func filter(values []string, keep func(string) bool) []string {
+	var out []string
+	for _, v := range values {
+		if keep(v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func digitsOf(value string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, value)
+}
+
+// validLuhn checks a card number candidate with the Luhn checksum
+func validLuhn(value string) bool {
+	digits := digitsOf(value)
+	if len(digits) < 13 || len(digits) > 19 {
+		return false
+	}
+
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// validIBAN checks an IBAN candidate with the ISO 13616 mod-97 checksum
+func validIBAN(value string) bool {
+	iban := strings.ReplaceAll(value, " ", "")
+	if len(iban) < 15 || len(iban) > 34 {
+		return false
+	}
+
+	rearranged := iban[4:] + iban[:4]
+	var numeric strings.Builder
+	for _, r := range rearranged {
+		switch {
+		case r >= '0' && r <= '9':
+			numeric.WriteRune(r)
+		case r >= 'A' && r <= 'Z':
+			fmt.Fprintf(&numeric, "%d", r-'A'+10)
+		default:
+			return false
+		}
+	}
+
+	n, ok := new(big.Int).SetString(numeric.String(), 10)
+	return ok && new(big.Int).Mod(n, big.NewInt(97)).Int64() == 1
+}
+
+// likelyPhone filters phone candidates: 9 to 15 digits (E.164), which rules
+// out dates, times and most plain numbers spoken in meetings
+func likelyPhone(value string) bool {
+	n := len(digitsOf(value))
+	return n >= 9 && n <= 15
+}
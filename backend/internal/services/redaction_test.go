@@ -0,0 +1,109 @@
+package services_test
+
+import (
+	"backend/internal/database"
+	"backend/internal/services"
+	"path/filepath"
+	"testing"
+)
+
+func newRedactionService(t *testing.T, detectors ...string) *services.RedactionService {
+	t.Helper()
+	db, err := database.OpenSQLite(filepath.Join(t.TempDir(), "echo.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	s := services.NewRedactionService(db)
+	policy := &services.RedactionPolicy{Workspace: services.DefaultWorkspace, Enabled: true, Detectors: detectors}
+	if err := s.SetPolicy(policy); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestRedactCardsAndIBANs(t *testing.T) {
+	s := newRedactionService(t, services.DetectorIBAN, services.DetectorCard)
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"visa", "card 4111 1111 1111 1111 on file", "card [CARD_1] on file"},
+		{"dashed card", "pay with 5500-0000-0000-0004", "pay with [CARD_1]"},
+		{"bad luhn", "order 4111 1111 1111 1112 shipped", "order 4111 1111 1111 1112 shipped"},
+		{"too short for a card", "ticket 123456789012", "ticket 123456789012"},
+		{"german iban", "send it to DE89 3704 0044 0532 0130 00 today", "send it to [IBAN_1] today"},
+		{"compact iban", "GB82WEST12345698765432", "[IBAN_1]"},
+		{"bad iban checksum", "DE88 3704 0044 0532 0130 00", "DE88 3704 0044 0532 0130 00"},
+		{"repeated value", "4111111111111111 or 4111111111111111", "[CARD_1] or [CARD_1]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := s.Redact(services.DefaultWorkspace, "test", tt.text)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r.Text != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, r.Text)
+			}
+			if restored := r.Restore(r.Text, false); restored != tt.text {
+				t.Errorf("expected the original back, got %q", restored)
+			}
+		})
+	}
+}
+
+func TestRedactTerms(t *testing.T) {
+	s := newRedactionService(t, services.DetectorName)
+	for _, term := range []string{"Ana", "Bob", "C++", "@acme", "Project Falcon"} {
+		if _, err := s.AddTerm(services.DefaultWorkspace, term); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"ana said hi", "[NAME_1] said hi"},
+		{"Ask Ana.", "Ask [NAME_1]."},
+		{"Bananas are fine", "Bananas are fine"},
+		{"We rewrote it in C++ last year", "We rewrote it in [NAME_1] last year"},
+		{"ping @acme about it", "ping [NAME_1] about it"},
+		{"project falcon ships", "[NAME_1] ships"},
+		{"Ana Bob said", "[NAME_1] [NAME_2] said"},
+		{"Ana,Bob", "[NAME_1],[NAME_2]"},
+		{"Ana met Anabel and Ana", "[NAME_1] met Anabel and [NAME_1]"},
+	}
+	for _, tt := range tests {
+		r, err := s.Redact(services.DefaultWorkspace, "test", tt.text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Text != tt.want {
+			t.Errorf("%q: expected %q, got %q", tt.text, tt.want, r.Text)
+		}
+	}
+
+	// Deleting a term takes effect without a restart
+	terms, err := s.GetTerms(services.DefaultWorkspace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, term := range terms {
+		if term.Term == "Ana" {
+			if err := s.DeleteTerm(term.ID); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if r, err := s.Redact(services.DefaultWorkspace, "test", "ask Ana"); err != nil || r.Text != "ask Ana" {
+		t.Errorf("expected a deleted term to stay, got %v, %v", r, err)
+	}
+	if r, err := s.Redact("other", "test", "ask Ana"); err != nil || r.Text != "ask Ana" {
+		t.Errorf("expected terms to apply to their workspace only, got %v, %v", r, err)
+	}
+}
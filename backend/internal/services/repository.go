@@ -0,0 +1,115 @@
+package services
+
+import (
+	"backend/internal/database"
+	"database/sql"
+	"time"
+)
+
+// MeetingRepository stores meetings. Methods follow the service conventions:
+// lookups return nil when nothing matches, conditional updates report
+// whether they applied.
+type MeetingRepository interface {
+	Create(title string, autoTitle bool, folderID *int, defaults FolderDefaults) (*Meeting, error)
+	GetByID(id int) (*Meeting, error)
+	List(filter MeetingFilter) (*MeetingPage, error)
+	GetInRange(from, to time.Time) ([]Meeting, error)
+	ClaimVersion(id int, versions []int) (bool, error)
+	UpdateTitle(id int, title string) error
+	SetAutoTitle(id int, enabled bool) error
+	ApplyGeneratedTitle(id int, title, description string) (bool, error)
+	UpdateSettings(id int, settings FolderDefaults) error
+	SetTemplate(id, templateID int) error
+	UpdateNotes(id int, notes, author, source string) error
+	UpdateTranscript(id int, transcript string) error
+	AppendTranscript(id int, text string) error
+	FinishRecording(id int, audioPath string, duration int) error
+	Start(id int) (bool, error)
+	Patch(id int, patch MeetingPatch, author string, versions []int) (*Meeting, error)
+	Trash(id int) (bool, error)
+	Restore(id int) (bool, error)
+	TrashExpired() (int, error)
+	Delete(id int) error
+	GetByImportKey(key string) (*Meeting, error)
+	RestoreImported(key string) (*Meeting, error)
+	CreateImported(meeting Meeting, key, source string, segments []Segment) (*Meeting, error)
+}
+
+// TagRepository stores tags, meeting tags and meeting types
+type TagRepository interface {
+	GetAll() ([]Tag, error)
+	GetOrCreate(name string) (*Tag, error)
+	Rename(id int, name string) (*Tag, error)
+	Delete(id int) error
+	AddToMeeting(meetingID int, name, source string) (*MeetingTag, error)
+	RemoveFromMeeting(meetingID, tagID int) error
+	GetForMeeting(meetingID int) ([]MeetingTag, error)
+	SetMeetingType(meetingID int, meetingType string, onlyIfEmpty bool) error
+	Exists(id int) (bool, error)
+	Names() ([]string, error)
+	ApplyClassification(meetingID int, classification *MeetingClassification) error
+}
+
+// FolderRepository stores folders and which folder a meeting is in
+type FolderRepository interface {
+	GetAll() ([]Folder, error)
+	GetByID(id int) (*Folder, error)
+	Create(name string, parentID *int, defaults FolderDefaults) (*Folder, error)
+	Update(id int, name string, parentID *int, defaults FolderDefaults) (*Folder, error)
+	Delete(id int) error
+	Subtree(id int) ([]int, error)
+	EffectiveDefaults(id int) (FolderDefaults, error)
+	MoveMeeting(meetingID int, folderID *int) (bool, error)
+}
+
+// SegmentRepository stores the timestamped segments of transcripts
+type SegmentRepository interface {
+	Add(seg *Segment) error
+	GetByMeeting(meetingID int) ([]Segment, error)
+	LastEnd(meetingID int) (int64, error)
+	SetSpeaker(meetingID, segmentID int, speaker string) (bool, error)
+}
+
+// TaskRepository stores the action items of meetings
+type TaskRepository interface {
+	GetForMeeting(meetingID int) ([]Task, error)
+	GetOpen(meetingIDs []int) ([]Task, error)
+	AddExtracted(meetingID int, extracted []ExtractedTask) ([]Task, error)
+}
+
+// Repositories is the storage the API is wired to. Only meetings, tags,
+// folders, segments and tasks can be swapped. People and participants, note
+// revisions, the trash listing and purge, search and analytics have no
+// repository of their own and query the meetings in DB directly, so their
+// routes only work when DB holds the meetings, as with NewSQLRepositories.
+// Templates, digests and AI settings keep their own tables in DB.
+type Repositories struct {
+	DB       *sql.DB
+	Dialect  database.Dialect
+	Meetings MeetingRepository
+	Tags     TagRepository
+	Folders  FolderRepository
+	Segments SegmentRepository
+	Tasks    TaskRepository
+}
+
+// NewSQLRepositories returns repositories stored in db, a database of the given dialect
+func NewSQLRepositories(db *sql.DB, dialect database.Dialect) Repositories {
+	return Repositories{
+		DB:       db,
+		Dialect:  dialect,
+		Meetings: NewMeetingService(db, dialect),
+		Tags:     NewTagService(db),
+		Folders:  NewFolderService(db),
+		Segments: NewSegmentService(db),
+		Tasks:    NewTaskService(db),
+	}
+}
+
+// querier is the part of *sql.DB and *sql.Tx that helpers need, so they
+// work both inside and outside a transaction
+type querier interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
@@ -0,0 +1,289 @@
+package services_test
+
+import (
+	"backend/internal/database"
+	"backend/internal/services"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// repositoryImplementations open empty repositories of every implementation
+var repositoryImplementations = map[string]func(t *testing.T) services.Repositories{
+	"memory": func(t *testing.T) services.Repositories {
+		store, err := services.NewMemoryStore()
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { store.Close() })
+		return store.Repositories()
+	},
+	"sqlite": func(t *testing.T) services.Repositories {
+		db, err := database.OpenSQLite(filepath.Join(t.TempDir(), "echo.db"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { db.Close() })
+		return services.NewSQLRepositories(db, database.SQLite)
+	},
+}
+
+// forEachRepository runs test against every repository implementation
+func forEachRepository(t *testing.T, test func(t *testing.T, repos services.Repositories)) {
+	for name, open := range repositoryImplementations {
+		t.Run(name, func(t *testing.T) {
+			test(t, open(t))
+		})
+	}
+}
+
+func createMeeting(t *testing.T, repos services.Repositories, title string) *services.Meeting {
+	t.Helper()
+	m, err := repos.Meetings.Create(title, false, nil, services.FolderDefaults{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func meetingVersion(t *testing.T, repos services.Repositories, id int) int {
+	t.Helper()
+	m, err := repos.Meetings.GetByID(id)
+	if err != nil || m == nil {
+		t.Fatalf("expected meeting %d, got %v, %v", id, m, err)
+	}
+	return m.Version
+}
+
+func TestMeetingRepositoryVersions(t *testing.T) {
+	forEachRepository(t, func(t *testing.T, repos services.Repositories) {
+		m := createMeeting(t, repos, "Standup")
+		if m.Version != 1 {
+			t.Fatalf("expected a new meeting at version 1, got %d", m.Version)
+		}
+
+		claimed, err := repos.Meetings.ClaimVersion(m.ID, []int{1})
+		if err != nil || !claimed {
+			t.Fatalf("expected to claim version 1, got %v, %v", claimed, err)
+		}
+		if v := meetingVersion(t, repos, m.ID); v != 2 {
+			t.Errorf("expected version 2 after the claim, got %d", v)
+		}
+
+		// A second client still holding version 1 loses
+		if claimed, err := repos.Meetings.ClaimVersion(m.ID, []int{1}); err != nil || claimed {
+			t.Errorf("expected a stale claim to fail, got %v, %v", claimed, err)
+		}
+		if claimed, err := repos.Meetings.ClaimVersion(m.ID, []int{1, 2}); err != nil || !claimed {
+			t.Errorf("expected a claim listing the current version to succeed, got %v, %v", claimed, err)
+		}
+		if claimed, err := repos.Meetings.ClaimVersion(m.ID+100, []int{1}); err != nil || claimed {
+			t.Errorf("expected no claim on a missing meeting, got %v, %v", claimed, err)
+		}
+
+		if err := repos.Meetings.UpdateTitle(m.ID, "Daily"); err != nil {
+			t.Fatal(err)
+		}
+		if v := meetingVersion(t, repos, m.ID); v != 4 {
+			t.Errorf("expected edits to bump the version to 4, got %d", v)
+		}
+
+		// So do changes a client doesn't make through Patch
+		if err := repos.Meetings.SetTemplate(m.ID, 1); err != nil {
+			t.Fatal(err)
+		}
+		if v := meetingVersion(t, repos, m.ID); v != 5 {
+			t.Errorf("expected setting the template to bump the version to 5, got %d", v)
+		}
+		if err := repos.Meetings.FinishRecording(m.ID, "/audio/1.webm", 60); err != nil {
+			t.Fatal(err)
+		}
+		if v := meetingVersion(t, repos, m.ID); v != 6 {
+			t.Errorf("expected finishing the recording to bump the version to 6, got %d", v)
+		}
+	})
+}
+
+func TestMeetingRepositoryPaging(t *testing.T) {
+	forEachRepository(t, func(t *testing.T, repos services.Repositories) {
+		for _, title := range []string{"d", "b", "e", "a", "c"} {
+			createMeeting(t, repos, title)
+		}
+
+		var titles []string
+		filter := services.MeetingFilter{Sort: "title", Ascending: true, Limit: 2}
+		for pages := 1; ; pages++ {
+			page, err := repos.Meetings.List(filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, m := range page.Meetings {
+				titles = append(titles, m.Title)
+			}
+			if page.NextCursor == "" {
+				if pages != 3 {
+					t.Errorf("expected 3 pages, got %d", pages)
+				}
+				break
+			}
+			if pages == 3 {
+				t.Fatal("expected the third page to be the last")
+			}
+			filter.Cursor = page.NextCursor
+		}
+		if got := strings.Join(titles, ","); got != "a,b,c,d,e" {
+			t.Errorf("expected a,b,c,d,e across pages, got %s", got)
+		}
+
+		// Meetings created within the same second page by ID
+		page, err := repos.Meetings.List(services.MeetingFilter{Limit: 4})
+		if err != nil {
+			t.Fatal(err)
+		}
+		rest, err := repos.Meetings.List(services.MeetingFilter{Limit: 4, Cursor: page.NextCursor})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(page.Meetings) != 4 || len(rest.Meetings) != 1 || rest.NextCursor != "" {
+			t.Errorf("expected pages of 4 and 1 by creation time, got %d and %d", len(page.Meetings), len(rest.Meetings))
+		}
+
+		_, err = repos.Meetings.List(services.MeetingFilter{Sort: "duration", Cursor: page.NextCursor})
+		if !errors.Is(err, services.ErrInvalidCursor) {
+			t.Errorf("expected ErrInvalidCursor for a cursor of another sort, got %v", err)
+		}
+	})
+}
+
+func TestMeetingRepositoryTrash(t *testing.T) {
+	forEachRepository(t, func(t *testing.T, repos services.Repositories) {
+		m := createMeeting(t, repos, "Retro")
+		createMeeting(t, repos, "Planning")
+
+		if trashed, err := repos.Meetings.Trash(m.ID); err != nil || !trashed {
+			t.Fatalf("expected the meeting to be trashed, got %v, %v", trashed, err)
+		}
+		if got, err := repos.Meetings.GetByID(m.ID); err != nil || got != nil {
+			t.Errorf("expected trashed meetings to be hidden, got %v, %v", got, err)
+		}
+		page, err := repos.Meetings.List(services.MeetingFilter{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(page.Meetings) != 1 || page.Meetings[0].Title != "Planning" {
+			t.Errorf("expected only Planning listed, got %+v", page.Meetings)
+		}
+		if trashed, err := repos.Meetings.Trash(m.ID); err != nil || trashed {
+			t.Errorf("expected a second trash to do nothing, got %v, %v", trashed, err)
+		}
+		if claimed, err := repos.Meetings.ClaimVersion(m.ID, []int{1}); err != nil || claimed {
+			t.Errorf("expected no claim on a trashed meeting, got %v, %v", claimed, err)
+		}
+
+		if restored, err := repos.Meetings.Restore(m.ID); err != nil || !restored {
+			t.Fatalf("expected the meeting to be restored, got %v, %v", restored, err)
+		}
+		if got, err := repos.Meetings.GetByID(m.ID); err != nil || got == nil || got.Title != "Retro" {
+			t.Errorf("expected the restored meeting, got %v, %v", got, err)
+		}
+		if restored, err := repos.Meetings.Restore(m.ID); err != nil || restored {
+			t.Errorf("expected restoring a live meeting to do nothing, got %v, %v", restored, err)
+		}
+	})
+}
+
+func TestSegmentRepository(t *testing.T) {
+	forEachRepository(t, func(t *testing.T, repos services.Repositories) {
+		m := createMeeting(t, repos, "Interview")
+
+		for _, seg := range []services.Segment{
+			{MeetingID: m.ID, StartMs: 5000, EndMs: 9000, Text: "second"},
+			{MeetingID: m.ID, Speaker: "Ana", StartMs: 0, EndMs: 5000, Text: "first"},
+		} {
+			if err := repos.Segments.Add(&seg); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		segments, err := repos.Segments.GetByMeeting(m.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(segments) != 2 || segments[0].Text != "first" || segments[1].Speaker != services.UnknownSpeaker {
+			t.Fatalf("expected segments in time order with a default speaker, got %+v", segments)
+		}
+		if end, err := repos.Segments.LastEnd(m.ID); err != nil || end != 9000 {
+			t.Errorf("expected the last segment to end at 9000, got %d, %v", end, err)
+		}
+
+		if found, err := repos.Segments.SetSpeaker(m.ID, segments[1].ID, "Ben"); err != nil || !found {
+			t.Fatalf("expected the speaker to be set, got %v, %v", found, err)
+		}
+		if v := meetingVersion(t, repos, m.ID); v != 2 {
+			t.Errorf("expected a speaker change to bump the version to 2, got %d", v)
+		}
+		if found, err := repos.Segments.SetSpeaker(m.ID+100, segments[1].ID, "Ben"); err != nil || found {
+			t.Errorf("expected no segment of another meeting, got %v, %v", found, err)
+		}
+	})
+}
+
+func TestTaskRepository(t *testing.T) {
+	forEachRepository(t, func(t *testing.T, repos services.Repositories) {
+		m := createMeeting(t, repos, "Planning")
+		other := createMeeting(t, repos, "Retro")
+
+		added, err := repos.Tasks.AddExtracted(m.ID, []services.ExtractedTask{
+			{Content: "Send the budget", Assignee: "Ana", DueDate: "2026-03-06", Priority: "high"},
+			{Content: "Book a room", Priority: "medium"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(added) != 2 || added[0].ID == 0 || added[0].Assignee != "Ana" || added[0].DueDate != "2026-03-06" {
+			t.Fatalf("expected both tasks stored with their details, got %+v", added)
+		}
+
+		// Extracting overlapping notes again only adds what is new
+		added, err = repos.Tasks.AddExtracted(m.ID, []services.ExtractedTask{
+			{Content: "send  the Budget", Priority: "low"},
+			{Content: "Share the slides", Priority: "medium"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(added) != 1 || added[0].Content != "Share the slides" {
+			t.Errorf("expected only the new task added, got %+v", added)
+		}
+		if _, err := repos.Tasks.AddExtracted(other.ID, []services.ExtractedTask{{Content: "Book a room", Priority: "low"}}); err != nil {
+			t.Fatal(err)
+		}
+
+		tasks, err := repos.Tasks.GetForMeeting(m.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(tasks) != 3 || tasks[0].Content != "Send the budget" || tasks[0].Priority != "high" {
+			t.Errorf("expected the meeting's 3 tasks in order, got %+v", tasks)
+		}
+
+		open, err := repos.Tasks.GetOpen([]int{m.ID, other.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(open) != 4 {
+			t.Errorf("expected 4 open tasks across both meetings, got %+v", open)
+		}
+		if open, err := repos.Tasks.GetOpen(nil); err != nil || len(open) != 0 {
+			t.Errorf("expected no tasks without meetings, got %v, %v", open, err)
+		}
+
+		if err := repos.Meetings.Delete(m.ID); err != nil {
+			t.Fatal(err)
+		}
+		if tasks, err := repos.Tasks.GetForMeeting(m.ID); err != nil || len(tasks) != 0 {
+			t.Errorf("expected a deleted meeting's tasks to go with it, got %v, %v", tasks, err)
+		}
+	})
+}
@@ -0,0 +1,215 @@
+package services
+
+import (
+	"database/sql"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	NoteSourceManual   = "manual"
+	NoteSourceRestore  = "restore"
+	NoteSourceTemplate = "template"
+
+	// Manual saves by the same author coalesce into the previous revision while
+	// they keep coming within revisionIdleWindow, for up to revisionMaxSpan
+	revisionIdleWindow = 2 * time.Minute
+	revisionMaxSpan    = 10 * time.Minute
+
+	// maxDiffCells bounds the line diff's table; larger diffs replace every line
+	maxDiffCells = 4_000_000
+)
+
+// blockBreaks match the editor's HTML that ends a line of text
+var blockBreaks = regexp.MustCompile(`(?i)<br\s*/?>|</(p|li|h[1-6]|div|blockquote|pre|tr)>`)
+
+// NoteRevision is one saved version of a meeting's notes. Notes is omitted in listings.
+type NoteRevision struct {
+	ID        int       `json:"id"`
+	MeetingID int       `json:"meeting_id"`
+	Author    string    `json:"author"`
+	Source    string    `json:"source"` // manual, restore, template, or the AI action, e.g. beautify
+	Notes     string    `json:"notes,omitempty"`
+	Size      int       `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"` // Later than CreatedAt when autosaves were coalesced
+}
+
+// DiffLine is one line of a diff between two revisions
+type DiffLine struct {
+	Op   string `json:"op"` // equal, insert or delete
+	Text string `json:"text"`
+}
+
+type NoteRevisionService struct {
+	db *sql.DB
+}
+
+func NewNoteRevisionService(db *sql.DB) *NoteRevisionService {
+	return &NoteRevisionService{db: db}
+}
+
+// ValidNoteSource reports whether clients may label a notes save with source:
+// a manual edit or the name of an AI action
+func ValidNoteSource(source string) bool {
+	if source == NoteSourceManual {
+		return true
+	}
+	_, ok := defaultAISettings[source]
+	return ok
+}
+
+// List returns a meeting's revisions without their notes, newest first
+func (s *NoteRevisionService) List(meetingID int) ([]NoteRevision, error) {
+	rows, err := s.db.Query(`
+		SELECT id, meeting_id, author, source, length(notes), created_at, updated_at
+		FROM note_revisions WHERE meeting_id = ?
+		ORDER BY id DESC
+	`, meetingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := []NoteRevision{}
+	for rows.Next() {
+		var r NoteRevision
+		var createdAt, updatedAt int64
+		if err := rows.Scan(&r.ID, &r.MeetingID, &r.Author, &r.Source, &r.Size, &createdAt, &updatedAt); err != nil {
+			return nil, err
+		}
+		r.CreatedAt = time.Unix(createdAt, 0)
+		r.UpdatedAt = time.Unix(updatedAt, 0)
+		revisions = append(revisions, r)
+	}
+
+	return revisions, rows.Err()
+}
+
+// GetByID returns a revision of a meeting's notes, or nil if it doesn't exist
+func (s *NoteRevisionService) GetByID(meetingID, id int) (*NoteRevision, error) {
+	var r NoteRevision
+	var createdAt, updatedAt int64
+	err := s.db.QueryRow(`
+		SELECT id, meeting_id, author, source, notes, created_at, updated_at
+		FROM note_revisions WHERE id = ? AND meeting_id = ?
+	`, id, meetingID).Scan(&r.ID, &r.MeetingID, &r.Author, &r.Source, &r.Notes, &createdAt, &updatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	r.Size = len(r.Notes)
+	r.CreatedAt = time.Unix(createdAt, 0)
+	r.UpdatedAt = time.Unix(updatedAt, 0)
+	return &r, nil
+}
+
+// recordRevision stores notes as a new revision, or folds a rapid manual
+// autosave into the previous revision. Saves that change nothing are skipped.
+func recordRevision(tx *sql.Tx, meetingID int, notes, author, source string) error {
+	var latest struct {
+		id                   int
+		notes, author, from  string
+		createdAt, updatedAt int64
+	}
+	err := tx.QueryRow(`
+		SELECT id, notes, author, source, created_at, updated_at
+		FROM note_revisions WHERE meeting_id = ?
+		ORDER BY id DESC LIMIT 1
+	`, meetingID).Scan(&latest.id, &latest.notes, &latest.author, &latest.from, &latest.createdAt, &latest.updatedAt)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	now := time.Now()
+	if err == nil {
+		if latest.notes == notes {
+			return nil
+		}
+
+		coalesce := source == NoteSourceManual && latest.from == NoteSourceManual && latest.author == author &&
+			now.Sub(time.Unix(latest.updatedAt, 0)) < revisionIdleWindow &&
+			now.Sub(time.Unix(latest.createdAt, 0)) < revisionMaxSpan
+		if coalesce {
+			_, err := tx.Exec("UPDATE note_revisions SET notes = ?, updated_at = ? WHERE id = ?", notes, now.Unix(), latest.id)
+			return err
+		}
+	}
+
+	_, err = tx.Exec(
+		"INSERT INTO note_revisions (meeting_id, notes, author, source, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)",
+		meetingID, notes, author, source, now.Unix(), now.Unix(),
+	)
+	return err
+}
+
+// DiffNotes compares two versions of notes line by line, as plain text
+func DiffNotes(from, to string) []DiffLine {
+	a, b := noteLines(from), noteLines(to)
+
+	if len(a)*len(b) > maxDiffCells {
+		diff := make([]DiffLine, 0, len(a)+len(b))
+		for _, line := range a {
+			diff = append(diff, DiffLine{Op: "delete", Text: line})
+		}
+		for _, line := range b {
+			diff = append(diff, DiffLine{Op: "insert", Text: line})
+		}
+		return diff
+	}
+
+	// lcs[i][j] is the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	diff := []DiffLine{}
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			diff = append(diff, DiffLine{Op: "equal", Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, DiffLine{Op: "delete", Text: a[i]})
+			i++
+		default:
+			diff = append(diff, DiffLine{Op: "insert", Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		diff = append(diff, DiffLine{Op: "delete", Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		diff = append(diff, DiffLine{Op: "insert", Text: b[j]})
+	}
+
+	return diff
+}
+
+// noteLines splits the editor's HTML into non-empty lines of plain text
+func noteLines(notes string) []string {
+	var lines []string
+	for _, line := range strings.Split(blockBreaks.ReplaceAllString(notes, "\n"), "\n") {
+		if text := plainText(line); text != "" {
+			lines = append(lines, text)
+		}
+	}
+	return lines
+}
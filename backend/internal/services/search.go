@@ -0,0 +1,290 @@
+package services
+
+import (
+	"backend/internal/database"
+	"database/sql"
+	"errors"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ErrInvalidQuery is returned when a search query isn't valid FTS5 syntax
+var ErrInvalidQuery = errors.New("invalid search query")
+
+// Search columns of meeting_search, in table order
+var searchFields = []string{"title", "description", "notes", "transcript"}
+
+const (
+	highlightStart = "<mark>"
+	highlightEnd   = "</mark>"
+	snippetTokens  = 16
+	// maxSegmentHits caps the timestamped transcript hits returned per meeting
+	maxSegmentHits = 3
+)
+
+var (
+	htmlTags   = regexp.MustCompile(`<[^>]*>`)
+	whitespace = regexp.MustCompile(`\s+`)
+)
+
+// SearchMatch is a highlighted snippet from one field of a meeting. StartMs
+// and EndMs are set when the hit is in a stored transcript segment.
+type SearchMatch struct {
+	Field     string `json:"field"`
+	Snippet   string `json:"snippet"`
+	SegmentID int    `json:"segment_id,omitempty"`
+	Speaker   string `json:"speaker,omitempty"`
+	StartMs   *int64 `json:"start_ms,omitempty"`
+	EndMs     *int64 `json:"end_ms,omitempty"`
+}
+
+type SearchHit struct {
+	MeetingID int           `json:"meeting_id"`
+	Title     string        `json:"title"`
+	CreatedAt time.Time     `json:"created_at"`
+	Score     float64       `json:"score"` // Lower is better, as returned by bm25
+	Matches   []SearchMatch `json:"matches"`
+}
+
+type SearchService struct {
+	db      *sql.DB
+	dialect database.Dialect
+}
+
+func NewSearchService(db *sql.DB, dialect database.Dialect) *SearchService {
+	return &SearchService{db: db, dialect: dialect}
+}
+
+// Search runs an FTS5 query over titles, descriptions, notes and transcripts.
+// Queries support "phrases", prefix* terms, AND / OR / NOT and column filters like title:budget,
+// on PostgreSQL they are translated to a tsquery with the same meaning.
+// A non-nil folderIDs limits results to meetings in those folders.
+func (s *SearchService) Search(query string, limit int, folderIDs []int) ([]SearchHit, error) {
+	folderClause := ""
+	var folderArgs []interface{}
+	if folderIDs != nil {
+		folderClause = " AND m.folder_id IN (" + placeholders(len(folderIDs)) + ")"
+		for _, id := range folderIDs {
+			folderArgs = append(folderArgs, id)
+		}
+	}
+
+	sqlQuery, args, err := meetingSearchQuery(s.dialect, query, folderClause)
+	if err != nil {
+		return nil, err
+	}
+	args = append(append(args, folderArgs...), limit)
+
+	rows, err := s.db.Query(sqlQuery, args...)
+	if err != nil {
+		return nil, searchError(err)
+	}
+
+	hits := []SearchHit{}
+	for rows.Next() {
+		var hit SearchHit
+		var createdAt timestamp
+		snippets := make([]string, len(searchFields))
+		if err := rows.Scan(&hit.MeetingID, &hit.Title, &createdAt, &hit.Score, &snippets[0], &snippets[1], &snippets[2], &snippets[3]); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		hit.CreatedAt = createdAt.Time
+
+		// A column without a hit still yields a snippet, just without highlights
+		for i, snippet := range snippets {
+			if strings.Contains(snippet, highlightStart) {
+				hit.Matches = append(hit.Matches, SearchMatch{Field: searchFields[i], Snippet: snippet})
+			}
+		}
+		hits = append(hits, hit)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, searchError(err)
+	}
+	rows.Close()
+
+	for i := range hits {
+		if err := s.addSegmentMatches(&hits[i], query); err != nil {
+			return nil, err
+		}
+	}
+
+	return hits, nil
+}
+
+// meetingSearchQuery returns the search over meeting_search for a database of
+// the given dialect, selecting id, title, created_at, a score where lower is better
+// and a snippet per search field. The folder clause and LIMIT take the
+// remaining arguments.
+func meetingSearchQuery(dialect database.Dialect, query, folderClause string) (string, []interface{}, error) {
+	if dialect == database.Postgres {
+		return postgresMeetingSearchQuery(query, folderClause)
+	}
+
+	return `
+		SELECT m.id, m.title, m.created_at,
+			bm25(meeting_search, 10.0, 4.0, 2.0, 1.0) AS score,
+			snippet(meeting_search, 0, ?, ?, '…', ?),
+			snippet(meeting_search, 1, ?, ?, '…', ?),
+			snippet(meeting_search, 2, ?, ?, '…', ?),
+			snippet(meeting_search, 3, ?, ?, '…', ?)
+		FROM meeting_search JOIN meetings m ON m.id = meeting_search.rowid
+		WHERE meeting_search MATCH ? AND m.deleted_at IS NULL` + folderClause + `
+		ORDER BY score
+		LIMIT ?
+	`, []interface{}{
+		highlightStart, highlightEnd, snippetTokens,
+		highlightStart, highlightEnd, snippetTokens,
+		highlightStart, highlightEnd, snippetTokens,
+		highlightStart, highlightEnd, snippetTokens,
+		query,
+	}, nil
+}
+
+// addSegmentMatches replaces the whole-transcript snippet with timestamped
+// segment snippets when individual segments match the query
+func (s *SearchService) addSegmentMatches(hit *SearchHit, query string) error {
+	sqlQuery, args, err := segmentSearchQuery(s.dialect, query, hit.MeetingID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := s.db.Query(sqlQuery, args...)
+	if err != nil {
+		// Column filters like title:budget are valid for meetings but not segments
+		if errors.Is(searchError(err), ErrInvalidQuery) {
+			return nil
+		}
+		return err
+	}
+	defer rows.Close()
+
+	var segmentMatches []SearchMatch
+	for rows.Next() {
+		m := SearchMatch{Field: "transcript", StartMs: new(int64), EndMs: new(int64)}
+		if err := rows.Scan(&m.SegmentID, &m.Speaker, m.StartMs, m.EndMs, &m.Snippet); err != nil {
+			return err
+		}
+		segmentMatches = append(segmentMatches, m)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(segmentMatches) == 0 {
+		return nil
+	}
+
+	var matches []SearchMatch
+	for _, m := range hit.Matches {
+		if m.Field != "transcript" {
+			matches = append(matches, m)
+		}
+	}
+	hit.Matches = append(matches, segmentMatches...)
+	return nil
+}
+
+// segmentSearchQuery returns the best matching segments of a meeting for a
+// database of the given dialect, selecting id, speaker, start_ms, end_ms and a snippet
+func segmentSearchQuery(dialect database.Dialect, query string, meetingID int) (string, []interface{}, error) {
+	if dialect == database.Postgres {
+		return postgresSegmentSearchQuery(query, meetingID)
+	}
+
+	return `
+		SELECT ts.id, ts.speaker, ts.start_ms, ts.end_ms, snippet(segment_search, 0, ?, ?, '…', ?)
+		FROM segment_search JOIN transcript_segments ts ON ts.id = segment_search.rowid
+		WHERE segment_search MATCH ? AND segment_search.meeting_id = ?
+		ORDER BY rank
+		LIMIT ?
+	`, []interface{}{highlightStart, highlightEnd, snippetTokens, query, meetingID, maxSegmentHits}, nil
+}
+
+// EnsureIndexed indexes meetings and segments missing from the search index,
+// e.g. those created before search existed
+func (s *SearchService) EnsureIndexed() (int, error) {
+	rows, err := s.db.Query("SELECT id FROM meetings WHERE id NOT IN (SELECT rowid FROM meeting_search)")
+	if err != nil {
+		return 0, err
+	}
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+
+	for _, id := range ids {
+		if err := indexMeeting(s.db, id); err != nil {
+			return 0, err
+		}
+	}
+
+	_, err = s.db.Exec(`
+		INSERT INTO segment_search (rowid, text, meeting_id)
+		SELECT id, text, meeting_id FROM transcript_segments
+		WHERE id NOT IN (SELECT rowid FROM segment_search)
+	`)
+	return len(ids), err
+}
+
+// indexMeeting (re)writes a meeting's row in the search index
+func indexMeeting(q querier, id int) error {
+	var title, description, notes, transcript string
+	err := q.QueryRow(
+		"SELECT title, description, notes, transcript FROM meetings WHERE id = ?", id,
+	).Scan(&title, &description, &notes, &transcript)
+	if err != nil {
+		return err
+	}
+
+	if _, err := q.Exec("DELETE FROM meeting_search WHERE rowid = ?", id); err != nil {
+		return err
+	}
+	_, err = q.Exec(
+		"INSERT INTO meeting_search (rowid, title, description, notes, transcript) VALUES (?, ?, ?, ?, ?)",
+		id, title, description, plainText(notes), strings.TrimSpace(transcript),
+	)
+	return err
+}
+
+// unindexMeeting removes a meeting and its segments from the search index
+func unindexMeeting(q querier, id int) error {
+	if _, err := q.Exec("DELETE FROM meeting_search WHERE rowid = ?", id); err != nil {
+		return err
+	}
+	_, err := q.Exec("DELETE FROM segment_search WHERE meeting_id = ?", id)
+	return err
+}
+
+// indexSegment adds a transcript segment to the search index
+func indexSegment(q querier, seg *Segment) error {
+	_, err := q.Exec(
+		"INSERT INTO segment_search (rowid, text, meeting_id) VALUES (?, ?, ?)",
+		seg.ID, seg.Text, seg.MeetingID,
+	)
+	return err
+}
+
+// plainText strips the editor's HTML from notes so tags aren't indexed
+func plainText(s string) string {
+	return strings.TrimSpace(whitespace.ReplaceAllString(html.UnescapeString(htmlTags.ReplaceAllString(s, " ")), " "))
+}
+
+// searchError turns FTS5 syntax errors into ErrInvalidQuery
+func searchError(err error) error {
+	msg := err.Error()
+	if strings.Contains(msg, "fts5") || strings.Contains(msg, "syntax error") || strings.Contains(msg, "no such column") {
+		return fmt.Errorf("%w: %v", ErrInvalidQuery, err)
+	}
+	return err
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// searchWeights are the tsvector weights of the meeting_search columns on PostgreSQL
+var searchWeights = map[string]string{
+	"title":       "A",
+	"description": "B",
+	"notes":       "C",
+	"transcript":  "D",
+}
+
+// headlineOptions make ts_headline mark hits like the FTS5 snippets
+var headlineOptions = fmt.Sprintf("StartSel=%s, StopSel=%s, MaxWords=%d, MinWords=%d",
+	highlightStart, highlightEnd, snippetTokens, snippetTokens/2)
+
+func postgresMeetingSearchQuery(query, folderClause string) (string, []interface{}, error) {
+	tsquery, err := tsQuery(query)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// ts_rank is higher for better matches, the score is lower like bm25
+	return `
+		SELECT m.id, m.title, m.created_at,
+			-ts_rank(s.document, q)::float8 AS score,
+			ts_headline('english', s.title, q, ?),
+			ts_headline('english', s.description, q, ?),
+			ts_headline('english', s.notes, q, ?),
+			ts_headline('english', s.transcript, q, ?)
+		FROM meeting_search s JOIN meetings m ON m.id = s.rowid, to_tsquery('english', ?) q
+		WHERE s.document @@ q AND m.deleted_at IS NULL` + folderClause + `
+		ORDER BY score
+		LIMIT ?
+	`, []interface{}{headlineOptions, headlineOptions, headlineOptions, headlineOptions, tsquery}, nil
+}
+
+func postgresSegmentSearchQuery(query string, meetingID int) (string, []interface{}, error) {
+	tsquery, err := tsQuery(query)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return `
+		SELECT ts.id, ts.speaker, ts.start_ms, ts.end_ms, ts_headline('english', s.text, q, ?)
+		FROM segment_search s JOIN transcript_segments ts ON ts.id = s.rowid, to_tsquery('english', ?) q
+		WHERE s.document @@ q AND s.meeting_id = ?
+		ORDER BY ts_rank(s.document, q) DESC
+		LIMIT ?
+	`, []interface{}{headlineOptions, tsquery, meetingID, maxSegmentHits}, nil
+}
+
+// tsQuery translates the FTS5 syntax Search accepts into a to_tsquery
+// expression. Adjacent terms are ANDed, "phrases" must be consecutive,
+// a trailing * matches prefixes and column filters like title:budget
+// match the weight the column is indexed with.
+func tsQuery(query string) (string, error) {
+	invalid := func(reason string) (string, error) {
+		return "", fmt.Errorf("%w: %s", ErrInvalidQuery, reason)
+	}
+
+	var out []string
+	depth := 0
+	afterTerm := false // the last token ends a term or group
+	for i := 0; i < len(query); {
+		switch query[i] {
+		case ' ', '\t', '\r', '\n':
+			i++
+			continue
+		case '(':
+			if afterTerm {
+				out = append(out, "&")
+			}
+			out = append(out, "(")
+			depth++
+			afterTerm = false
+			i++
+			continue
+		case ')':
+			if !afterTerm || depth == 0 {
+				return invalid("unbalanced parentheses")
+			}
+			out = append(out, ")")
+			depth--
+			i++
+			continue
+		}
+
+		// A bare word runs up to the next space, parenthesis or quote
+		start := i
+		for i < len(query) && !strings.ContainsRune(" \t\r\n()\"", rune(query[i])) {
+			i++
+		}
+		text := query[start:i]
+
+		if op, ok := map[string]string{"AND": "&", "OR": "|", "NOT": "& !"}[text]; ok {
+			if !afterTerm {
+				return invalid(text + " needs a term on both sides")
+			}
+			out = append(out, op)
+			afterTerm = false
+			continue
+		}
+
+		weight := ""
+		if column, rest, ok := strings.Cut(text, ":"); ok {
+			if weight, ok = searchWeights[column]; !ok {
+				return invalid("unknown column " + column)
+			}
+			text = rest
+		}
+
+		// A phrase, where "" stands for a quote
+		if text == "" && i < len(query) && query[i] == '"' {
+			end := i + 1
+			for {
+				j := strings.IndexByte(query[end:], '"')
+				if j < 0 {
+					return invalid("unterminated phrase")
+				}
+				end += j
+				if end+1 < len(query) && query[end+1] == '"' {
+					end += 2
+					continue
+				}
+				break
+			}
+			text = strings.ReplaceAll(query[i+1:end], `""`, `"`)
+			i = end + 1
+			if i < len(query) && query[i] == '*' {
+				text += "*"
+				i++
+			}
+		}
+
+		lexemes := tsLexemes(text, weight)
+		if lexemes == "" {
+			return invalid("empty term")
+		}
+		if afterTerm {
+			out = append(out, "&")
+		}
+		out = append(out, lexemes)
+		afterTerm = true
+	}
+
+	if !afterTerm || depth != 0 {
+		return invalid("incomplete query")
+	}
+	return strings.Join(out, " "), nil
+}
+
+// tsLexemes quotes the words of a term or phrase for to_tsquery, followed by
+// one another and labelled with weight. A trailing * makes the last word a prefix.
+func tsLexemes(text, weight string) string {
+	prefix := strings.HasSuffix(text, "*")
+	words := strings.FieldsFunc(strings.TrimSuffix(text, "*"), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '_'
+	})
+
+	for i, word := range words {
+		label := weight
+		if prefix && i == len(words)-1 {
+			label = "*" + label
+		}
+		if label != "" {
+			label = ":" + label
+		}
+		words[i] = "'" + word + "'" + label
+	}
+
+	if len(words) > 1 {
+		return "(" + strings.Join(words, " <-> ") + ")"
+	}
+	return strings.Join(words, "")
+}
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTSQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"budget", "'budget'"},
+		{"budget review", "'budget' & 'review'"},
+		{`"budget review"`, "('budget' <-> 'review')"},
+		{`"say ""hi"""`, "('say' <-> 'hi')"},
+		{"budg*", "'budg':*"},
+		{`"quarterly budg"*`, "('quarterly' <-> 'budg':*)"},
+		{"title:budget", "'budget':A"},
+		{"notes:budg*", "'budg':*C"},
+		{`transcript:"next steps"`, "('next':D <-> 'steps':D)"},
+		{"budget OR plan", "'budget' | 'plan'"},
+		{"budget NOT plan", "'budget' & ! 'plan'"},
+		{"(budget OR plan) review", "( 'budget' | 'plan' ) & 'review'"},
+		{"café", "'café'"},
+	}
+	for _, tt := range tests {
+		got, err := tsQuery(tt.query)
+		if err != nil {
+			t.Errorf("tsQuery(%q) failed: %v", tt.query, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("tsQuery(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+
+	for _, query := range []string{"", "(budget", "budget)", "OR budget", "budget AND", "speaker:ana", `"open phrase`, "***", "()"} {
+		if _, err := tsQuery(query); !errors.Is(err, ErrInvalidQuery) {
+			t.Errorf("tsQuery(%q): expected ErrInvalidQuery, got %v", query, err)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"database/sql"
+	"strings"
+)
+
+// UnknownSpeaker labels segments whose speaker hasn't been identified
+const UnknownSpeaker = "Unknown"
+
+// Segment is a timestamped piece of a meeting's transcript. StartMs and EndMs
+// are offsets from the start of the recording.
+type Segment struct {
+	ID        int    `json:"id"`
+	MeetingID int    `json:"meeting_id"`
+	Speaker   string `json:"speaker"`
+	StartMs   int64  `json:"start_ms"`
+	EndMs     int64  `json:"end_ms"`
+	Text      string `json:"text"`
+}
+
+// SegmentService is the SegmentRepository stored in SQLite or PostgreSQL
+type SegmentService struct {
+	db *sql.DB
+}
+
+func NewSegmentService(db *sql.DB) *SegmentService {
+	return &SegmentService{db: db}
+}
+
+// Add stores a transcript segment
+func (s *SegmentService) Add(seg *Segment) error {
+	if strings.TrimSpace(seg.Speaker) == "" {
+		seg.Speaker = UnknownSpeaker
+	}
+
+	err := s.db.QueryRow(
+		"INSERT INTO transcript_segments (meeting_id, speaker, start_ms, end_ms, text) VALUES (?, ?, ?, ?, ?) RETURNING id",
+		seg.MeetingID, seg.Speaker, seg.StartMs, seg.EndMs, seg.Text,
+	).Scan(&seg.ID)
+	if err != nil {
+		return err
+	}
+
+	return indexSegment(s.db, seg)
+}
+
+// GetByMeeting returns a meeting's segments in chronological order
+func (s *SegmentService) GetByMeeting(meetingID int) ([]Segment, error) {
+	rows, err := s.db.Query(
+		"SELECT id, meeting_id, speaker, start_ms, end_ms, text FROM transcript_segments WHERE meeting_id = ? ORDER BY start_ms, id",
+		meetingID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var segments []Segment
+	for rows.Next() {
+		var seg Segment
+		if err := rows.Scan(&seg.ID, &seg.MeetingID, &seg.Speaker, &seg.StartMs, &seg.EndMs, &seg.Text); err != nil {
+			return nil, err
+		}
+		segments = append(segments, seg)
+	}
+
+	return segments, rows.Err()
+}
+
+// LastEnd returns where the previous segment of a meeting ended, 0 if there is none
+func (s *SegmentService) LastEnd(meetingID int) (int64, error) {
+	var end sql.NullInt64
+	err := s.db.QueryRow("SELECT MAX(end_ms) FROM transcript_segments WHERE meeting_id = ?", meetingID).Scan(&end)
+	return end.Int64, err
+}
+
+// SetSpeaker labels a segment with its speaker
+func (s *SegmentService) SetSpeaker(meetingID, segmentID int, speaker string) (bool, error) {
+	if strings.TrimSpace(speaker) == "" {
+		speaker = UnknownSpeaker
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		"UPDATE transcript_segments SET speaker = ? WHERE id = ? AND meeting_id = ?",
+		strings.TrimSpace(speaker), segmentID, meetingID,
+	)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil || affected == 0 {
+		return false, err
+	}
+
+	// Speaker labels are part of the transcript, so they count as a meeting edit
+	if _, err := tx.Exec("UPDATE meetings SET version = version + 1 WHERE id = ?", meetingID); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
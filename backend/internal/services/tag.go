@@ -0,0 +1,314 @@
+package services
+
+import (
+	"backend/internal/database"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// MeetingTypes are the values accepted for Meeting.MeetingType
+var MeetingTypes = []string{
+	"one-on-one", "standup", "client-call", "retro", "planning",
+	"interview", "incident-review", "all-hands", "brainstorm", "other",
+}
+
+// Tag sources: added by a user or suggested from the transcript
+const (
+	TagSourceManual = "manual"
+	TagSourceAuto   = "auto"
+)
+
+var tagSeparators = regexp.MustCompile(`[\s_]+`)
+
+type Tag struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	MeetingCount int    `json:"meeting_count"`
+}
+
+type MeetingTag struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Source string `json:"source"`
+}
+
+// TagService is the TagRepository stored in SQLite or PostgreSQL
+type TagService struct {
+	db *sql.DB
+}
+
+func NewTagService(db *sql.DB) *TagService {
+	return &TagService{db: db}
+}
+
+// NormalizeTagName lowercases a tag and joins words with dashes, so
+// "Incident Review" and "incident-review" are the same tag
+func NormalizeTagName(name string) string {
+	return tagSeparators.ReplaceAllString(strings.ToLower(strings.TrimSpace(name)), "-")
+}
+
+// ValidMeetingType reports whether t is empty or one of MeetingTypes
+func ValidMeetingType(t string) bool {
+	return t == "" || contains(MeetingTypes, t)
+}
+
+// GetAll lists every tag with the number of meetings using it
+func (s *TagService) GetAll() ([]Tag, error) {
+	rows, err := s.db.Query(`
+		SELECT t.id, t.name, COUNT(m.id)
+		FROM tags t
+		LEFT JOIN meeting_tags mt ON mt.tag_id = t.id
+		LEFT JOIN meetings m ON m.id = mt.meeting_id AND m.deleted_at IS NULL
+		GROUP BY t.id ORDER BY t.name
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []Tag
+	for rows.Next() {
+		var t Tag
+		if err := rows.Scan(&t.ID, &t.Name, &t.MeetingCount); err != nil {
+			return nil, err
+		}
+		tags = append(tags, t)
+	}
+
+	return tags, rows.Err()
+}
+
+// GetOrCreate returns the tag with this name, creating it if needed
+func (s *TagService) GetOrCreate(name string) (*Tag, error) {
+	name = NormalizeTagName(name)
+	if name == "" {
+		return nil, fmt.Errorf("tag name must not be empty")
+	}
+	if len(name) > maxTagLength {
+		return nil, fmt.Errorf("tag name must be at most %d characters", maxTagLength)
+	}
+
+	if _, err := s.db.Exec("INSERT INTO tags (name) VALUES (?) ON CONFLICT(name) DO NOTHING", name); err != nil {
+		return nil, err
+	}
+
+	var t Tag
+	if err := s.db.QueryRow("SELECT id, name FROM tags WHERE name = ?", name).Scan(&t.ID, &t.Name); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// Rename changes a tag's name
+func (s *TagService) Rename(id int, name string) (*Tag, error) {
+	name = NormalizeTagName(name)
+	if name == "" {
+		return nil, fmt.Errorf("tag name must not be empty")
+	}
+
+	result, err := s.db.Exec("UPDATE tags SET name = ? WHERE id = ?", name, id)
+	if err != nil {
+		if database.IsUniqueViolation(err) {
+			return nil, fmt.Errorf("a tag named %q already exists", name)
+		}
+		return nil, err
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return nil, nil
+	}
+	return &Tag{ID: id, Name: name}, nil
+}
+
+// Delete removes a tag from every meeting and deletes it
+func (s *TagService) Delete(id int) error {
+	if _, err := s.db.Exec("DELETE FROM meeting_tags WHERE tag_id = ?", id); err != nil {
+		return err
+	}
+	_, err := s.db.Exec("DELETE FROM tags WHERE id = ?", id)
+	return err
+}
+
+// AddToMeeting tags a meeting. A manual tag overrides an earlier auto suggestion.
+func (s *TagService) AddToMeeting(meetingID int, name, source string) (*MeetingTag, error) {
+	tag, err := s.GetOrCreate(name)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.db.Exec(`
+		INSERT INTO meeting_tags (meeting_id, tag_id, source) VALUES (?, ?, ?)
+		ON CONFLICT(meeting_id, tag_id) DO UPDATE SET source = CASE WHEN excluded.source = 'manual' THEN 'manual' ELSE meeting_tags.source END
+	`, meetingID, tag.ID, source)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MeetingTag{ID: tag.ID, Name: tag.Name, Source: source}, nil
+}
+
+// RemoveFromMeeting untags a meeting
+func (s *TagService) RemoveFromMeeting(meetingID, tagID int) error {
+	_, err := s.db.Exec("DELETE FROM meeting_tags WHERE meeting_id = ? AND tag_id = ?", meetingID, tagID)
+	return err
+}
+
+// GetForMeeting lists a meeting's tags
+func (s *TagService) GetForMeeting(meetingID int) ([]MeetingTag, error) {
+	rows, err := s.db.Query(`
+		SELECT t.id, t.name, mt.source FROM meeting_tags mt JOIN tags t ON t.id = mt.tag_id
+		WHERE mt.meeting_id = ? ORDER BY t.name
+	`, meetingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []MeetingTag{}
+	for rows.Next() {
+		var t MeetingTag
+		if err := rows.Scan(&t.ID, &t.Name, &t.Source); err != nil {
+			return nil, err
+		}
+		tags = append(tags, t)
+	}
+
+	return tags, rows.Err()
+}
+
+// SetMeetingType sets a meeting's type. With onlyIfEmpty the type is only set
+// when none was chosen yet, so suggestions never override a user's choice.
+func (s *TagService) SetMeetingType(meetingID int, meetingType string, onlyIfEmpty bool) error {
+	if !ValidMeetingType(meetingType) {
+		return fmt.Errorf("unknown meeting type %q, use one of %s", meetingType, strings.Join(MeetingTypes, ", "))
+	}
+
+	query := "UPDATE meetings SET meeting_type = ?, updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = ?"
+	if onlyIfEmpty {
+		query += " AND meeting_type = ''"
+	}
+
+	_, err := s.db.Exec(query, meetingType, meetingID)
+	return err
+}
+
+// setMeetingTags makes names the meeting's complete tag list, creating tags
+// as needed. Listed tags count as manual, so a confirmed suggestion sticks.
+func setMeetingTags(tx *sql.Tx, meetingID int, names []string) error {
+	var tagIDs []interface{}
+	for _, name := range names {
+		name = NormalizeTagName(name)
+		if _, err := tx.Exec("INSERT INTO tags (name) VALUES (?) ON CONFLICT(name) DO NOTHING", name); err != nil {
+			return err
+		}
+
+		var tagID int
+		if err := tx.QueryRow("SELECT id FROM tags WHERE name = ?", name).Scan(&tagID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`
+			INSERT INTO meeting_tags (meeting_id, tag_id, source) VALUES (?, ?, ?)
+			ON CONFLICT(meeting_id, tag_id) DO UPDATE SET source = excluded.source
+		`, meetingID, tagID, TagSourceManual); err != nil {
+			return err
+		}
+		tagIDs = append(tagIDs, tagID)
+	}
+
+	query := "DELETE FROM meeting_tags WHERE meeting_id = ?"
+	if len(tagIDs) > 0 {
+		query += " AND tag_id NOT IN (" + placeholders(len(tagIDs)) + ")"
+	}
+	_, err := tx.Exec(query, append([]interface{}{meetingID}, tagIDs...)...)
+	return err
+}
+
+// tagNamesForMeetings loads tag names for several meetings in one query
+func tagNamesForMeetings(q querier, ids []int) (map[int][]string, error) {
+	names := map[int][]string{}
+	if len(ids) == 0 {
+		return names, nil
+	}
+
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+
+	rows, err := q.Query(`
+		SELECT mt.meeting_id, t.name FROM meeting_tags mt JOIN tags t ON t.id = mt.tag_id
+		WHERE mt.meeting_id IN (`+placeholders(len(ids))+`) ORDER BY t.name
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return nil, err
+		}
+		names[id] = append(names[id], name)
+	}
+
+	return names, rows.Err()
+}
+
+// placeholders returns n comma separated SQL placeholders
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
+
+// Exists reports whether a tag with this ID exists
+func (s *TagService) Exists(id int) (bool, error) {
+	var found int
+	err := s.db.QueryRow("SELECT 1 FROM tags WHERE id = ?", id).Scan(&found)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Names lists the names of all tags
+func (s *TagService) Names() ([]string, error) {
+	return tagNames(s)
+}
+
+func tagNames(tags TagRepository) ([]string, error) {
+	all, err := tags.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(all))
+	for i, t := range all {
+		names[i] = t.Name
+	}
+	return names, nil
+}
+
+// ApplyClassification adds suggested tags as auto tags and sets the meeting
+// type unless one was already chosen
+func (s *TagService) ApplyClassification(meetingID int, classification *MeetingClassification) error {
+	return applyClassification(s, meetingID, classification)
+}
+
+func applyClassification(tags TagRepository, meetingID int, classification *MeetingClassification) error {
+	if err := tags.SetMeetingType(meetingID, classification.MeetingType, true); err != nil {
+		return err
+	}
+
+	for _, name := range classification.Tags {
+		if NormalizeTagName(name) == classification.MeetingType {
+			continue
+		}
+		if _, err := tags.AddToMeeting(meetingID, name, TagSourceAuto); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// Task is an action item of a meeting
+type Task struct {
+	ID        int       `json:"id"`
+	MeetingID int       `json:"meeting_id"`
+	Content   string    `json:"content"`
+	Assignee  string    `json:"assignee"`
+	DueDate   string    `json:"due_date"` // YYYY-MM-DD, empty if not known
+	Priority  string    `json:"priority"` // low, medium or high
+	Completed bool      `json:"completed"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TaskService is the TaskRepository stored in SQLite or PostgreSQL
+type TaskService struct {
+	db *sql.DB
+}
+
+func NewTaskService(db *sql.DB) *TaskService {
+	return &TaskService{db: db}
+}
+
+const taskColumns = "id, meeting_id, content, assignee, due_date, priority, completed, created_at"
+
+func scanTasks(rows *sql.Rows) ([]Task, error) {
+	defer rows.Close()
+
+	tasks := []Task{}
+	for rows.Next() {
+		var t Task
+		var createdAt timestamp
+		if err := rows.Scan(&t.ID, &t.MeetingID, &t.Content, &t.Assignee, &t.DueDate, &t.Priority, &t.Completed, &createdAt); err != nil {
+			return nil, err
+		}
+		t.CreatedAt = createdAt.Time
+		tasks = append(tasks, t)
+	}
+	return tasks, rows.Err()
+}
+
+// GetForMeeting returns a meeting's tasks, open ones first
+func (s *TaskService) GetForMeeting(meetingID int) ([]Task, error) {
+	rows, err := s.db.Query("SELECT "+taskColumns+" FROM tasks WHERE meeting_id = ? ORDER BY completed, id", meetingID)
+	if err != nil {
+		return nil, err
+	}
+	return scanTasks(rows)
+}
+
+// GetOpen returns the tasks of the given meetings that aren't completed, oldest first
+func (s *TaskService) GetOpen(meetingIDs []int) ([]Task, error) {
+	if len(meetingIDs) == 0 {
+		return []Task{}, nil
+	}
+
+	args := make([]interface{}, len(meetingIDs))
+	for i, id := range meetingIDs {
+		args[i] = id
+	}
+
+	rows, err := s.db.Query(
+		"SELECT "+taskColumns+" FROM tasks WHERE completed = FALSE AND meeting_id IN ("+placeholders(len(meetingIDs))+") ORDER BY id",
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return scanTasks(rows)
+}
+
+// AddExtracted stores tasks extracted from a meeting's notes and returns
+// the ones added. Extracting the same notes again doesn't duplicate tasks:
+// those whose content the meeting already has are skipped.
+func (s *TaskService) AddExtracted(meetingID int, extracted []ExtractedTask) ([]Task, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query("SELECT content FROM tasks WHERE meeting_id = ?", meetingID)
+	if err != nil {
+		return nil, err
+	}
+	existing := map[string]bool{}
+	for rows.Next() {
+		var content string
+		if err := rows.Scan(&content); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		existing[taskKey(content)] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	added := []Task{}
+	for _, e := range extracted {
+		if existing[taskKey(e.Content)] {
+			continue
+		}
+		existing[taskKey(e.Content)] = true
+
+		t := Task{MeetingID: meetingID, Content: e.Content, Assignee: e.Assignee, DueDate: e.DueDate, Priority: e.Priority}
+		var createdAt timestamp
+		if err := tx.QueryRow(
+			"INSERT INTO tasks (meeting_id, content, assignee, due_date, priority) VALUES (?, ?, ?, ?, ?) RETURNING id, created_at",
+			t.MeetingID, t.Content, t.Assignee, t.DueDate, t.Priority,
+		).Scan(&t.ID, &createdAt); err != nil {
+			return nil, err
+		}
+		t.CreatedAt = createdAt.Time
+		added = append(added, t)
+	}
+
+	return added, tx.Commit()
+}
+
+// taskKey identifies a task by its content, ignoring case and spacing
+func taskKey(content string) string {
+	return strings.ToLower(strings.Join(strings.Fields(content), " "))
+}
+
+// taskDetails describes who should do a task and by when, e.g. "Ana, due 2026-03-06"
+func taskDetails(assignee, dueDate string) string {
+	var details []string
+	if assignee != "" {
+		details = append(details, assignee)
+	}
+	if dueDate != "" {
+		details = append(details, "due "+dueDate)
+	}
+	return strings.Join(details, ", ")
+}
@@ -0,0 +1,366 @@
+package services
+
+import (
+	"backend/internal/database"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	maxTemplateSections = 20
+	maxTemplateAgenda   = 30
+)
+
+// titlePlaceholders are replaced in title patterns with the creation time in these layouts
+var titlePlaceholders = map[string]string{
+	"{date}":    "2006-01-02",
+	"{time}":    "15:04",
+	"{weekday}": "Monday",
+}
+
+var (
+	titlePlaceholder = regexp.MustCompile(`\{[^{}]*\}`)
+	noteHeading      = regexp.MustCompile(`(?is)<h([1-6])[^>]*>(.*?)</h[1-6]>`)
+)
+
+// ErrTemplateExists is returned when a template name is already taken
+var ErrTemplateExists = errors.New("a template with this name already exists")
+
+// TemplateSection is a named heading of a template's notes skeleton
+type TemplateSection struct {
+	Name string `json:"name"`
+	Hint string `json:"hint"` // What belongs in the section, guides auto-fill
+}
+
+// MeetingTemplate lays out meetings that follow a fixed structure, like standups or retros
+type MeetingTemplate struct {
+	ID           int               `json:"id"`
+	Name         string            `json:"name"`
+	TitlePattern string            `json:"title_pattern"` // May use {date}, {time} and {weekday}
+	Agenda       []string          `json:"agenda"`
+	Sections     []TemplateSection `json:"sections"`
+	Tags         []string          `json:"tags"`   // Added to meetings created from the template
+	Prompt       string            `json:"prompt"` // Becomes the meeting's prompt template
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+}
+
+// TemplateInput is a template as clients create or replace it
+type TemplateInput struct {
+	Name         string            `json:"name" binding:"required"`
+	TitlePattern string            `json:"title_pattern"`
+	Agenda       []string          `json:"agenda"`
+	Sections     []TemplateSection `json:"sections"`
+	Tags         []string          `json:"tags"`
+	Prompt       string            `json:"prompt"`
+}
+
+type TemplateService struct {
+	db *sql.DB
+}
+
+func NewTemplateService(db *sql.DB) *TemplateService {
+	return &TemplateService{db: db}
+}
+
+const templateColumns = "id, name, title_pattern, agenda, sections, tags, prompt, created_at, updated_at"
+
+func scanTemplate(row rowScanner) (*MeetingTemplate, error) {
+	var t MeetingTemplate
+	var agenda, sections, tags string
+	var createdAt, updatedAt int64
+	if err := row.Scan(&t.ID, &t.Name, &t.TitlePattern, &agenda, &sections, &tags, &t.Prompt, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(agenda), &t.Agenda); err != nil {
+		return nil, fmt.Errorf("invalid agenda in template %d: %w", t.ID, err)
+	}
+	if err := json.Unmarshal([]byte(sections), &t.Sections); err != nil {
+		return nil, fmt.Errorf("invalid sections in template %d: %w", t.ID, err)
+	}
+	if err := json.Unmarshal([]byte(tags), &t.Tags); err != nil {
+		return nil, fmt.Errorf("invalid tags in template %d: %w", t.ID, err)
+	}
+	t.CreatedAt = time.Unix(createdAt, 0)
+	t.UpdatedAt = time.Unix(updatedAt, 0)
+	return &t, nil
+}
+
+// GetAll lists every template by name
+func (s *TemplateService) GetAll() ([]MeetingTemplate, error) {
+	rows, err := s.db.Query("SELECT " + templateColumns + " FROM meeting_templates ORDER BY LOWER(name)")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	templates := []MeetingTemplate{}
+	for rows.Next() {
+		t, err := scanTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, *t)
+	}
+	return templates, rows.Err()
+}
+
+// GetByID returns a template, or nil if it doesn't exist
+func (s *TemplateService) GetByID(id int) (*MeetingTemplate, error) {
+	t, err := scanTemplate(s.db.QueryRow("SELECT "+templateColumns+" FROM meeting_templates WHERE id = ?", id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return t, err
+}
+
+// Create adds a template
+func (s *TemplateService) Create(input TemplateInput) (*MeetingTemplate, error) {
+	agenda, sections, tags, err := normalizeTemplate(&input)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	var id int
+	err = s.db.QueryRow(
+		"INSERT INTO meeting_templates (name, title_pattern, agenda, sections, tags, prompt, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?) RETURNING id",
+		input.Name, input.TitlePattern, agenda, sections, tags, input.Prompt, now, now,
+	).Scan(&id)
+	if database.IsUniqueViolation(err) {
+		return nil, ErrTemplateExists
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.GetByID(id)
+}
+
+// Update replaces a template. Meetings created from it keep their notes.
+func (s *TemplateService) Update(id int, input TemplateInput) (*MeetingTemplate, error) {
+	agenda, sections, tags, err := normalizeTemplate(&input)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.db.Exec(
+		"UPDATE meeting_templates SET name = ?, title_pattern = ?, agenda = ?, sections = ?, tags = ?, prompt = ?, updated_at = ? WHERE id = ?",
+		input.Name, input.TitlePattern, agenda, sections, tags, input.Prompt, time.Now().Unix(), id,
+	)
+	if database.IsUniqueViolation(err) {
+		return nil, ErrTemplateExists
+	}
+	if err != nil {
+		return nil, err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return nil, nil
+	}
+	return s.GetByID(id)
+}
+
+// Delete removes a template. Meetings created from it keep their notes but
+// can no longer be auto-filled.
+func (s *TemplateService) Delete(id int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("UPDATE meetings SET template_id = NULL, version = version + 1 WHERE template_id = ?", id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM meeting_templates WHERE id = ?", id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// normalizeTemplate trims and validates input, returning its lists as JSON
+func normalizeTemplate(input *TemplateInput) (agenda, sections, tags string, err error) {
+	input.Name = strings.TrimSpace(input.Name)
+	input.TitlePattern = strings.TrimSpace(input.TitlePattern)
+	input.Prompt = strings.TrimSpace(input.Prompt)
+
+	if input.Name == "" {
+		return "", "", "", fmt.Errorf("template name must not be empty")
+	}
+	if len(input.Name) > 100 {
+		return "", "", "", fmt.Errorf("template name must be at most 100 characters")
+	}
+	for _, placeholder := range titlePlaceholder.FindAllString(input.TitlePattern, -1) {
+		if _, ok := titlePlaceholders[placeholder]; !ok {
+			return "", "", "", fmt.Errorf("unknown title placeholder %s, use {date}, {time} or {weekday}", placeholder)
+		}
+	}
+
+	items := []string{}
+	for _, item := range input.Agenda {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	if len(items) > maxTemplateAgenda {
+		return "", "", "", fmt.Errorf("a template has at most %d agenda items", maxTemplateAgenda)
+	}
+	input.Agenda = items
+
+	seen := map[string]bool{}
+	named := []TemplateSection{}
+	for _, section := range input.Sections {
+		section.Name = strings.Join(strings.Fields(section.Name), " ")
+		section.Hint = strings.TrimSpace(section.Hint)
+		if section.Name == "" {
+			return "", "", "", fmt.Errorf("section names must not be empty")
+		}
+		key := strings.ToLower(section.Name)
+		if seen[key] || (key == "agenda" && len(items) > 0) {
+			return "", "", "", fmt.Errorf("section %q appears twice", section.Name)
+		}
+		seen[key] = true
+		named = append(named, section)
+	}
+	if len(named) > maxTemplateSections {
+		return "", "", "", fmt.Errorf("a template has at most %d sections", maxTemplateSections)
+	}
+	input.Sections = named
+
+	names := []string{}
+	for _, tag := range input.Tags {
+		if tag = NormalizeTagName(tag); tag != "" && !contains(names, tag) {
+			names = append(names, tag)
+		}
+	}
+	input.Tags = names
+
+	// Marshalling strings and TemplateSections can't fail
+	encodedAgenda, _ := json.Marshal(input.Agenda)
+	encodedSections, _ := json.Marshal(input.Sections)
+	encodedTags, _ := json.Marshal(input.Tags)
+	return string(encodedAgenda), string(encodedSections), string(encodedTags), nil
+}
+
+// Title fills in the title pattern for a meeting created at now, falling
+// back to the default title without a pattern
+func (t *MeetingTemplate) Title(now time.Time) string {
+	title := titlePlaceholder.ReplaceAllStringFunc(t.TitlePattern, func(placeholder string) string {
+		return now.Format(titlePlaceholders[placeholder])
+	})
+	if title = strings.TrimSpace(title); title == "" {
+		return DefaultMeetingTitle
+	}
+	return title
+}
+
+// Skeleton renders the notes a meeting created from the template starts
+// with: the agenda as a list, then an empty heading per section
+func (t *MeetingTemplate) Skeleton() string {
+	var b strings.Builder
+	if len(t.Agenda) > 0 {
+		b.WriteString("<h2>Agenda</h2>")
+		b.WriteString(htmlList(t.Agenda))
+	}
+	for _, section := range t.Sections {
+		b.WriteString("<h2>" + html.EscapeString(section.Name) + "</h2><p></p>")
+	}
+	return b.String()
+}
+
+// htmlList renders items as a bullet list in the editor's HTML
+func htmlList(items []string) string {
+	var b strings.Builder
+	b.WriteString("<ul>")
+	for _, item := range items {
+		b.WriteString("<li><p>" + html.EscapeString(item) + "</p></li>")
+	}
+	b.WriteString("</ul>")
+	return b.String()
+}
+
+// noteSection is the content under a heading of a meeting's notes, up to
+// the next heading of the same or a higher level
+type noteSection struct {
+	Name       string
+	Start, End int // Byte offsets of the content in the notes
+}
+
+// Blank reports whether the section has no text in notes
+func (s noteSection) Blank(notes string) bool {
+	return plainText(notes[s.Start:s.End]) == ""
+}
+
+// noteSections finds the headed sections of notes in the editor's HTML. A
+// heading that repeats an earlier one's name is treated as its content.
+func noteSections(notes string) []noteSection {
+	matches := noteHeading.FindAllStringSubmatchIndex(notes, -1)
+
+	var sections []noteSection
+	seen := map[string]bool{}
+	for i, m := range matches {
+		name := plainText(notes[m[4]:m[5]])
+		if seen[strings.ToLower(name)] {
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+
+		// Levels are a single digit, so they compare as strings
+		end := len(notes)
+		for _, next := range matches[i+1:] {
+			if notes[next[2]:next[3]] <= notes[m[2]:m[3]] {
+				end = next[0]
+				break
+			}
+		}
+		sections = append(sections, noteSection{Name: name, Start: m[1], End: end})
+	}
+	return sections
+}
+
+// EmptySections returns the template's sections that are still blank in
+// notes. Sections whose heading was removed are left out.
+func (t *MeetingTemplate) EmptySections(notes string) []TemplateSection {
+	var empty []TemplateSection
+	found := noteSections(notes)
+	for _, section := range t.Sections {
+		i := slices.IndexFunc(found, func(s noteSection) bool { return strings.EqualFold(s.Name, section.Name) })
+		if i >= 0 && found[i].Blank(notes) {
+			empty = append(empty, section)
+		}
+	}
+	return empty
+}
+
+// FillSections writes items under the named headings of notes that are
+// still blank, leaving every other part of the notes as it is. It returns
+// the new notes and the names of the sections it filled.
+func FillSections(notes string, items map[string][]string) (string, []string) {
+	filled := []string{}
+	sections := noteSections(notes)
+
+	// Back to front, so the offsets of earlier sections stay valid
+	for i := len(sections) - 1; i >= 0; i-- {
+		section := sections[i]
+		if !section.Blank(notes) {
+			continue
+		}
+		for name, content := range items {
+			if strings.EqualFold(name, section.Name) && len(content) > 0 {
+				notes = notes[:section.Start] + htmlList(content) + notes[section.End:]
+				filled = append(filled, section.Name)
+				break
+			}
+		}
+	}
+
+	slices.Reverse(filled)
+	return notes, filled
+}
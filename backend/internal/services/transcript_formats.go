@@ -0,0 +1,428 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Transcript formats the importer reads
+const (
+	ImportFormatVTT   = "vtt"   // WebVTT, e.g. from Zoom, Google Meet or Teams
+	ImportFormatSRT   = "srt"   // SubRip subtitles
+	ImportFormatTeams = "teams" // Microsoft Teams transcript .docx
+	ImportFormatOtter = "otter" // Otter.ai plain-text export
+)
+
+// ImportFormats are the values accepted as an explicit import format
+var ImportFormats = []string{ImportFormatVTT, ImportFormatSRT, ImportFormatTeams, ImportFormatOtter}
+
+// ValidImportFormat reports whether format is one of ImportFormats
+func ValidImportFormat(format string) bool {
+	return contains(ImportFormats, format)
+}
+
+// ErrInvalidTranscript is returned for files that can't be read as a transcript
+var ErrInvalidTranscript = errors.New("invalid transcript")
+
+// byteOrderMark starts some exported UTF-8 files
+const byteOrderMark = "\ufeff"
+
+// maxTranscriptXML bounds the document of a .docx, which is compressed
+const maxTranscriptXML = 50 << 20
+
+// ImportedTranscript is a transcript read from another tool's export. Title
+// and Date are set when the file itself names them.
+type ImportedTranscript struct {
+	Title    string
+	Date     *time.Time
+	Segments []Segment
+}
+
+var (
+	cueTiming      = regexp.MustCompile(`^\s*([\d:.,]+)\s+-->\s+([\d:.,]+)`)
+	voiceTag       = regexp.MustCompile(`^<v(?:\.[^\s>]*)?\s+([^>]+)>`)
+	cueTags        = regexp.MustCompile(`</?[a-zA-Z][^>]*>`)
+	speakerPrefix  = regexp.MustCompile(`^([^:]{1,40}?):\s+(.+)$`)
+	personName     = regexp.MustCompile(`^\p{Lu}[\p{L}'’.-]*(?:\s+[\p{L}'’.-]+){0,3}$`) // up to 4 words, the first capitalized
+	speakerHeading = regexp.MustCompile(`^(\S.{0,60}?)\s+(\d{1,2}:\d{2}(?::\d{2})?)$`)
+	genericSpeaker = regexp.MustCompile(`(?i)^(speaker|spk|participant|unknown speaker)\s*\d*$`)
+	fileDate       = regexp.MustCompile(`(\d{4})-?(\d{2})-?(\d{2})(?:[ _T-]?(\d{2})[.:-]?(\d{2})(?:[.:-]?(\d{2}))?)?`)
+)
+
+// Date layouts found in transcript headers, e.g. Teams' "October 3, 2024, 2:00PM"
+var transcriptDateLayouts = []string{
+	"January 2, 2006, 3:04PM",
+	"January 2, 2006, 3:04 PM",
+	"January 2, 2006 3:04PM",
+	"January 2, 2006 3:04 PM",
+	"Jan 2, 2006, 3:04PM",
+	"Jan 2, 2006, 3:04 PM",
+	"January 2, 2006",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// DetectImportFormat guesses a file's transcript format from its name and
+// content, or returns "" if it is none of ImportFormats
+func DetectImportFormat(filename string, data []byte) string {
+	text := strings.TrimPrefix(string(data[:min(len(data), 512)]), byteOrderMark)
+	switch {
+	case strings.HasPrefix(text, "WEBVTT"):
+		return ImportFormatVTT
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		return ImportFormatTeams
+	}
+
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".vtt":
+		return ImportFormatVTT
+	case ".srt":
+		return ImportFormatSRT
+	case ".docx":
+		return ImportFormatTeams
+	case ".txt":
+		// SRT files are sometimes saved as .txt
+		lines := strings.SplitN(strings.ReplaceAll(text, "\r\n", "\n"), "\n", 3)
+		if len(lines) > 1 && cueTiming.MatchString(lines[1]) {
+			return ImportFormatSRT
+		}
+		return ImportFormatOtter
+	}
+	return ""
+}
+
+// ParseTranscript reads a transcript in one of ImportFormats
+func ParseTranscript(format string, data []byte) (*ImportedTranscript, error) {
+	var transcript *ImportedTranscript
+	var err error
+	switch format {
+	case ImportFormatVTT:
+		transcript, err = parseCues(data, true)
+	case ImportFormatSRT:
+		transcript, err = parseCues(data, false)
+	case ImportFormatTeams:
+		transcript, err = parseTeamsDOCX(data)
+	case ImportFormatOtter:
+		transcript = parseSpeakerLines(transcriptLines(string(data)))
+	default:
+		return nil, fmt.Errorf("%w: unsupported format %q", ErrInvalidTranscript, format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(transcript.Segments) == 0 {
+		return nil, fmt.Errorf("%w: no transcript found in the file", ErrInvalidTranscript)
+	}
+	return transcript, nil
+}
+
+// parseCues reads WebVTT (requiring its header) or SRT. Speakers come from
+// WebVTT voice tags or a "Name: " prefix, as Zoom writes them. Zoom prefixes
+// every cue, so prefixes only count as speakers in a WebVTT file where most
+// cues have one; otherwise "Agenda: budget" stays text.
+func parseCues(data []byte, vtt bool) (*ImportedTranscript, error) {
+	text := strings.TrimPrefix(strings.ReplaceAll(string(data), "\r\n", "\n"), byteOrderMark)
+	blocks := strings.Split(strings.ReplaceAll(text, "\r", "\n"), "\n\n")
+	if vtt && !strings.HasPrefix(blocks[0], "WEBVTT") {
+		return nil, fmt.Errorf("%w: missing WEBVTT header", ErrInvalidTranscript)
+	}
+
+	transcript := &ImportedTranscript{}
+	var prefixed []int // segments whose text starts with a name-like prefix
+	for _, block := range blocks {
+		lines := strings.Split(strings.Trim(block, "\n"), "\n")
+		timing := -1
+		for i, line := range lines {
+			if cueTiming.MatchString(line) {
+				timing = i
+				break
+			}
+		}
+		if timing < 0 {
+			continue
+		}
+
+		m := cueTiming.FindStringSubmatch(lines[timing])
+		start, err := parseCueTime(m[1])
+		if err != nil {
+			return nil, err
+		}
+		end, err := parseCueTime(m[2])
+		if err != nil {
+			return nil, err
+		}
+
+		content := strings.TrimSpace(strings.Join(lines[timing+1:], " "))
+		speaker := ""
+		if v := voiceTag.FindStringSubmatch(content); v != nil {
+			speaker = strings.TrimSpace(v[1])
+		}
+		content = whitespace.ReplaceAllString(strings.TrimSpace(cueTags.ReplaceAllString(content, "")), " ")
+		if content == "" {
+			continue
+		}
+		if p := speakerPrefix.FindStringSubmatch(content); vtt && speaker == "" && p != nil && personName.MatchString(strings.TrimSpace(p[1])) {
+			prefixed = append(prefixed, len(transcript.Segments))
+		}
+
+		transcript.Segments = append(transcript.Segments, Segment{
+			Speaker: speaker,
+			StartMs: start,
+			EndMs:   max(end, start),
+			Text:    content,
+		})
+	}
+
+	if len(prefixed)*2 >= len(transcript.Segments) {
+		for _, i := range prefixed {
+			seg := &transcript.Segments[i]
+			p := speakerPrefix.FindStringSubmatch(seg.Text)
+			seg.Speaker, seg.Text = strings.TrimSpace(p[1]), p[2]
+		}
+	}
+	return transcript, nil
+}
+
+// parseCueTime parses cue and heading timestamps: hh:mm:ss.mmm, mm:ss.mmm,
+// SRT's comma decimals and Teams' unpadded h:m:s.f
+func parseCueTime(value string) (int64, error) {
+	clock, fraction, _ := strings.Cut(strings.ReplaceAll(value, ",", "."), ".")
+	parts := strings.Split(clock, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("%w: bad timestamp %q", ErrInvalidTranscript, value)
+	}
+
+	var seconds int64
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("%w: bad timestamp %q", ErrInvalidTranscript, value)
+		}
+		seconds = seconds*60 + int64(n)
+	}
+
+	var ms int64
+	if fraction != "" {
+		fraction = (fraction + "00")[:3]
+		n, err := strconv.Atoi(fraction)
+		if err != nil {
+			return 0, fmt.Errorf("%w: bad timestamp %q", ErrInvalidTranscript, value)
+		}
+		ms = int64(n)
+	}
+	return seconds*1000 + ms, nil
+}
+
+// parseTeamsDOCX reads the paragraphs of a Teams transcript document
+func parseTeamsDOCX(data []byte) (*ImportedTranscript, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: not a .docx file", ErrInvalidTranscript)
+	}
+	var document *zip.File
+	for _, f := range archive.File {
+		if f.Name == "word/document.xml" {
+			document = f
+		}
+	}
+	if document == nil {
+		return nil, fmt.Errorf("%w: not a .docx file", ErrInvalidTranscript)
+	}
+
+	r, err := document.Open()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTranscript, err)
+	}
+	defer r.Close()
+
+	var text strings.Builder
+	decoder := xml.NewDecoder(io.LimitReader(r, maxTranscriptXML))
+	inText := false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidTranscript, err)
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "br", "cr":
+				text.WriteString("\n")
+			case "tab":
+				text.WriteString(" ")
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				text.WriteString("\n")
+			}
+		case xml.CharData:
+			if inText {
+				text.Write(t)
+			}
+		}
+	}
+
+	return parseSpeakerLines(transcriptLines(text.String())), nil
+}
+
+func transcriptLines(text string) []string {
+	text = strings.TrimPrefix(strings.ReplaceAll(text, "\r\n", "\n"), byteOrderMark)
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		lines = append(lines, strings.TrimSpace(line))
+	}
+	return lines
+}
+
+// parseSpeakerLines reads transcripts laid out as headings followed by what
+// was said. Headings are either a "Speaker  1:23" line, as written by Otter
+// and current Teams, or a "0:0:1.2 --> 0:0:5.9" line followed by the speaker,
+// as written by older Teams. Lines before the first heading are a header with
+// the title and date; "Transcribed by" footers are dropped.
+func parseSpeakerLines(lines []string) *ImportedTranscript {
+	transcript := &ImportedTranscript{}
+	var header []string
+	var current *Segment
+	var texts []string
+	explicitEnd := false
+	flush := func() {
+		if current != nil && len(texts) > 0 {
+			current.Text = strings.Join(texts, " ")
+			transcript.Segments = append(transcript.Segments, *current)
+		}
+		current, texts = nil, nil
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if line == "" {
+			continue
+		}
+
+		if m := cueTiming.FindStringSubmatch(line); m != nil {
+			start, err1 := parseCueTime(m[1])
+			end, err2 := parseCueTime(m[2])
+			if err1 == nil && err2 == nil {
+				flush()
+				current = &Segment{StartMs: start, EndMs: max(end, start)}
+				explicitEnd = true
+				// The speaker is on the next line, unless it is blank
+				if i+1 < len(lines) && lines[i+1] != "" {
+					i++
+					current.Speaker = lines[i]
+				}
+				continue
+			}
+		}
+
+		if m := speakerHeading.FindStringSubmatch(line); m != nil && len(strings.Fields(m[1])) <= 5 {
+			if start, err := parseCueTime(m[2]); err == nil {
+				flush()
+				current = &Segment{Speaker: m[1], StartMs: start}
+				explicitEnd = false
+				continue
+			}
+		}
+
+		if current == nil {
+			header = append(header, line)
+			continue
+		}
+		if strings.HasPrefix(line, "Transcribed by ") {
+			continue
+		}
+		texts = append(texts, line)
+	}
+	flush()
+
+	// Headings only give start times, so segments end where the next begins
+	// and the last one lasts as long as it takes to say, at 150 words a minute
+	if !explicitEnd {
+		for i := range transcript.Segments {
+			seg := &transcript.Segments[i]
+			if i+1 < len(transcript.Segments) {
+				seg.EndMs = max(transcript.Segments[i+1].StartMs, seg.StartMs)
+			} else {
+				seg.EndMs = seg.StartMs + int64(len(strings.Fields(seg.Text)))*400
+			}
+		}
+	}
+
+	for _, line := range header {
+		if date := parseTranscriptDate(line); date != nil {
+			if transcript.Date == nil {
+				transcript.Date = date
+			}
+		} else if transcript.Title == "" {
+			transcript.Title = line
+		}
+	}
+	return transcript
+}
+
+// parseTranscriptDate reads a header line as a date, or returns nil
+func parseTranscriptDate(line string) *time.Time {
+	for _, layout := range transcriptDateLayouts {
+		if t, err := time.ParseInLocation(layout, line, time.Local); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
+
+// importNameDetails derives a title and date from an export's file name,
+// e.g. "GMT20240305-150000_Recording.transcript.vtt" or "2024-03-05 Standup.txt"
+func importNameDetails(filename string) (string, *time.Time) {
+	name := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	name = strings.TrimSuffix(name, ".transcript")
+
+	var date *time.Time
+	if m := fileDate.FindStringSubmatchIndex(name); m != nil {
+		part := func(n int) int {
+			if m[2*n] < 0 {
+				return 0
+			}
+			v, _ := strconv.Atoi(name[m[2*n]:m[2*n+1]])
+			return v
+		}
+		loc := time.Local
+		// Zoom names recordings after their start in UTC
+		if m[0] >= 3 && name[m[0]-3:m[0]] == "GMT" {
+			loc = time.UTC
+			m[0] -= 3
+		}
+		t := time.Date(part(1), time.Month(part(2)), part(3), part(4), part(5), part(6), 0, loc)
+		if t.Month() == time.Month(part(2)) && part(2) >= 1 && part(4) < 24 && part(5) < 60 {
+			date = &t
+			name = name[:m[0]] + " " + name[m[1]:]
+		}
+	}
+
+	title := strings.Join(strings.Fields(strings.NewReplacer("_", " ", "-", " ").Replace(name)), " ")
+	return title, date
+}
+
+// realSpeaker reports whether a speaker label names someone, rather than
+// being a placeholder like "Speaker 1"
+func realSpeaker(speaker string) bool {
+	speaker = strings.TrimSpace(speaker)
+	return speaker != "" && speaker != UnknownSpeaker && !genericSpeaker.MatchString(speaker)
+}
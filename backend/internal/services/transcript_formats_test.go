@@ -0,0 +1,126 @@
+package services_test
+
+import (
+	"backend/internal/services"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseTranscript(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		data   string
+		want   []services.Segment
+	}{
+		{
+			name:   "zoom vtt",
+			format: services.ImportFormatVTT,
+			data: "WEBVTT\n\n1\n00:00:01.000 --> 00:00:04.500\nAna Souza: Let's start\nwith the budget.\n\n" +
+				"2\n00:00:05.000 --> 00:00:07.250\nBen: Agenda: budget first.\n",
+			want: []services.Segment{
+				{Speaker: "Ana Souza", StartMs: 1000, EndMs: 4500, Text: "Let's start with the budget."},
+				{Speaker: "Ben", StartMs: 5000, EndMs: 7250, Text: "Agenda: budget first."},
+			},
+		},
+		{
+			name:   "vtt voice tags",
+			format: services.ImportFormatVTT,
+			data:   "\ufeffWEBVTT\r\n\r\n00:01.000 --> 00:02.000\r\n<v.loud Ana>Hello <b>all</b></v>\r\n",
+			want:   []services.Segment{{Speaker: "Ana", StartMs: 1000, EndMs: 2000, Text: "Hello all"}},
+		},
+		{
+			name:   "vtt without speakers keeps labels in the text",
+			format: services.ImportFormatVTT,
+			data: "WEBVTT\n\n00:00:01.000 --> 00:00:02.000\nAgenda: budget\n\n" +
+				"00:00:02.000 --> 00:00:03.000\nthen hiring\n\n00:00:03.000 --> 00:00:04.000\nand the offsite\n",
+			want: []services.Segment{
+				{StartMs: 1000, EndMs: 2000, Text: "Agenda: budget"},
+				{StartMs: 2000, EndMs: 3000, Text: "then hiring"},
+				{StartMs: 3000, EndMs: 4000, Text: "and the offsite"},
+			},
+		},
+		{
+			name:   "srt",
+			format: services.ImportFormatSRT,
+			data:   "1\n00:00:01,500 --> 00:00:03,000\nNote: follow up\n\n2\n00:01:00,000 --> 00:01:02,000\nwith legal\n",
+			want: []services.Segment{
+				{StartMs: 1500, EndMs: 3000, Text: "Note: follow up"},
+				{StartMs: 60000, EndMs: 62000, Text: "with legal"},
+			},
+		},
+		{
+			name:   "otter",
+			format: services.ImportFormatOtter,
+			data: "Weekly sync\nMarch 5, 2024\n\nAna Souza  0:03\nWelcome everyone.\n\nSpeaker 2  1:10\nThanks, let's go.\n\n" +
+				"Transcribed by https://otter.ai\n",
+			want: []services.Segment{
+				{Speaker: "Ana Souza", StartMs: 3000, EndMs: 70000, Text: "Welcome everyone."},
+				{Speaker: "Speaker 2", StartMs: 70000, EndMs: 71200, Text: "Thanks, let's go."},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := services.ParseTranscript(tt.format, []byte(tt.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got.Segments) != len(tt.want) {
+				t.Fatalf("expected %d segments, got %+v", len(tt.want), got.Segments)
+			}
+			for i, want := range tt.want {
+				if got.Segments[i] != want {
+					t.Errorf("segment %d: expected %+v, got %+v", i, want, got.Segments[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseTranscriptHeader(t *testing.T) {
+	got, err := services.ParseTranscript(services.ImportFormatOtter, []byte("Weekly sync\nMarch 5, 2024\n\nAna  0:03\nHi.\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Title != "Weekly sync" || got.Date == nil || !got.Date.Equal(time.Date(2024, 3, 5, 0, 0, 0, 0, got.Date.Location())) {
+		t.Errorf("expected the title and date from the header, got %q, %v", got.Title, got.Date)
+	}
+}
+
+func TestParseTranscriptInvalid(t *testing.T) {
+	for _, tt := range []struct{ format, data string }{
+		{services.ImportFormatVTT, "00:00:01.000 --> 00:00:02.000\nno header\n"},
+		{services.ImportFormatSRT, "just some text\n"},
+		{services.ImportFormatOtter, "\n\n"},
+		{"pdf", "%PDF"},
+	} {
+		if _, err := services.ParseTranscript(tt.format, []byte(tt.data)); !errors.Is(err, services.ErrInvalidTranscript) {
+			t.Errorf("%s %q: expected ErrInvalidTranscript, got %v", tt.format, tt.data, err)
+		}
+	}
+}
+
+func TestDetectImportFormat(t *testing.T) {
+	tests := []struct {
+		filename string
+		data     string
+		want     string
+	}{
+		{"meeting.txt", "WEBVTT\n\n", services.ImportFormatVTT},
+		{"GMT20240305-150000_Recording.transcript.vtt", "", services.ImportFormatVTT},
+		{"captions.srt", "", services.ImportFormatSRT},
+		{"captions.txt", "1\n00:00:01,000 --> 00:00:02,000\nhi\n", services.ImportFormatSRT},
+		{"otter.txt", "Weekly sync\n\nAna  0:03\nhi\n", services.ImportFormatOtter},
+		{"teams.docx", "", services.ImportFormatTeams},
+		{"unknown.bin", "PK\x03\x04", services.ImportFormatTeams},
+		{"notes.pdf", "%PDF", ""},
+	}
+	for _, tt := range tests {
+		if got := services.DetectImportFormat(tt.filename, []byte(tt.data)); got != tt.want {
+			t.Errorf("DetectImportFormat(%q) = %q, want %q", tt.filename, got, tt.want)
+		}
+	}
+}
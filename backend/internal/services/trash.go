@@ -0,0 +1,159 @@
+package services
+
+import (
+	"database/sql"
+	"log"
+	"time"
+)
+
+// purgeInterval is how often the background purger runs
+const purgeInterval = time.Hour
+
+// TrashedMeeting is a meeting in the trash. PurgeAt is nil when trashed
+// meetings are kept until removed by hand.
+type TrashedMeeting struct {
+	ID              int        `json:"id"`
+	Title           string     `json:"title"`
+	CreatedAt       time.Time  `json:"created_at"`
+	DurationSeconds int        `json:"duration_seconds"`
+	HasAudio        bool       `json:"has_audio"`
+	DeletedAt       time.Time  `json:"deleted_at"`
+	PurgeAt         *time.Time `json:"purge_at"`
+}
+
+type TrashService struct {
+	db                 *sql.DB
+	MeetingService     MeetingRepository
+	AudioMergerService *AudioMergerService
+	RetentionDays      int // 0 disables automatic purging
+}
+
+func NewTrashService(db *sql.DB, meetingService MeetingRepository, audioMerger *AudioMergerService, retentionDays int) *TrashService {
+	return &TrashService{
+		db:                 db,
+		MeetingService:     meetingService,
+		AudioMergerService: audioMerger,
+		RetentionDays:      retentionDays,
+	}
+}
+
+// GetAll lists trashed meetings, most recently deleted first
+func (s *TrashService) GetAll() ([]TrashedMeeting, error) {
+	rows, err := s.db.Query(`
+		SELECT id, title, created_at, duration_seconds, audio_path != '', deleted_at
+		FROM meetings WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id DESC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	meetings := []TrashedMeeting{}
+	for rows.Next() {
+		var m TrashedMeeting
+		var createdAt timestamp
+		var deletedAt int64
+		if err := rows.Scan(&m.ID, &m.Title, &createdAt, &m.DurationSeconds, &m.HasAudio, &deletedAt); err != nil {
+			return nil, err
+		}
+
+		m.CreatedAt = createdAt.Time
+		m.DeletedAt = time.Unix(deletedAt, 0)
+		if s.RetentionDays > 0 {
+			purgeAt := m.DeletedAt.AddDate(0, 0, s.RetentionDays)
+			m.PurgeAt = &purgeAt
+		}
+		meetings = append(meetings, m)
+	}
+
+	return meetings, rows.Err()
+}
+
+// Purge permanently deletes a trashed meeting and its audio. It reports false
+// if the meeting isn't in the trash.
+func (s *TrashService) Purge(id int) (bool, error) {
+	var audioPath string
+	err := s.db.QueryRow("SELECT audio_path FROM meetings WHERE id = ? AND deleted_at IS NOT NULL", id).Scan(&audioPath)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if err := s.MeetingService.Delete(id); err != nil {
+		return false, err
+	}
+	s.AudioMergerService.CleanupMeeting(id, audioPath)
+	return true, nil
+}
+
+// Empty permanently deletes every trashed meeting and returns how many were removed
+func (s *TrashService) Empty() (int, error) {
+	return s.purgeWhere("deleted_at IS NOT NULL")
+}
+
+// PurgeExpired permanently deletes meetings trashed longer than the retention period
+func (s *TrashService) PurgeExpired() (int, error) {
+	if s.RetentionDays <= 0 {
+		return 0, nil
+	}
+	cutoff := time.Now().AddDate(0, 0, -s.RetentionDays).Unix()
+	return s.purgeWhere("deleted_at IS NOT NULL AND deleted_at < ?", cutoff)
+}
+
+func (s *TrashService) purgeWhere(condition string, args ...interface{}) (int, error) {
+	rows, err := s.db.Query("SELECT id FROM meetings WHERE "+condition, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, id := range ids {
+		ok, err := s.Purge(id)
+		if err != nil {
+			return purged, err
+		}
+		if ok {
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// StartPurger periodically trashes meetings past their own retention period
+// (set per folder) and purges meetings that sat in the trash too long
+func (s *TrashService) StartPurger() {
+	go func() {
+		for {
+			if trashed, err := s.MeetingService.TrashExpired(); err != nil {
+				log.Printf("⚠️  Failed to trash expired meetings: %v", err)
+			} else if trashed > 0 {
+				log.Printf("🗑️  Moved %d meetings past their retention period to the trash", trashed)
+			}
+
+			if purged, err := s.PurgeExpired(); err != nil {
+				log.Printf("⚠️  Failed to purge trash: %v", err)
+			} else if purged > 0 {
+				log.Printf("🗑️  Permanently deleted %d meetings from the trash", purged)
+			}
+
+			time.Sleep(purgeInterval)
+		}
+	}()
+}
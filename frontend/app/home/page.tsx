@@ -7,22 +7,22 @@ import {
   Trash2, MoreVertical, FileText, ArrowRight 
 } from 'lucide-react';
 import { useAuth } from '@/hooks/useAuth';
-import { meetingsApi, Meeting } from '@/lib/api';
+import { meetingsApi, MeetingSummary } from '@/lib/api';
 
 export default function HomePage() {
     const { loading: authLoading, logout} = useAuth();
     const router = useRouter();
     const [searchQuery, setSearchQuery] = useState('');
-    const [meetings, setMeetings] = useState<Meeting[]>([]);
+    const [meetings, setMeetings] = useState<MeetingSummary[]>([]);
     const [loading, setLoading] = useState(true);
 
     // Fetch meetings
     useEffect(() => {
         const fetchMeetings = async () => {
             try {
-                const { data } = await meetingsApi.getAll();
+                const { data } = await meetingsApi.getAll({ limit: 200 });
                 // Sort by newest first
-                const sorted = (data.meetings || []).sort((a: Meeting, b: Meeting) => 
+                const sorted = (data.meetings || []).sort((a: MeetingSummary, b: MeetingSummary) => 
                     new Date(b.created_at).getTime() - new Date(a.created_at).getTime()
                 );
                 setMeetings(sorted);
@@ -196,7 +196,7 @@ export default function HomePage() {
                                             {meeting.title || 'Untitled Meeting'}
                                         </h3>
                                         <p className="text-slate-500 text-sm leading-relaxed line-clamp-4">
-                                            {meeting.transcript || meeting.notes || "No content available. Click to start writing or recording..."}
+                                            {meeting.snippet || "No content available. Click to start writing or recording..."}
                                         </p>
                                     </div>
 
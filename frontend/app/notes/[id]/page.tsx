@@ -22,6 +22,11 @@ export default function NotePage() {
     // Debounce refs
     const timeoutRef = useRef<NodeJS.Timeout | null>(null);
 
+    // Server state this tab last saw, used to detect edits made elsewhere
+    const versionRef = useRef<number | undefined>(undefined);
+    const savedRef = useRef<{ title: string; notes: string }>({ title: '', notes: '' });
+    const [editorKey, setEditorKey] = useState(0);
+
     // Fetch meeting data
     useEffect(() => {
         const fetchMeeting = async () => {
@@ -30,6 +35,8 @@ export default function NotePage() {
                 const { data } = await meetingsApi.getOne(id);
                 setMeeting(data);
                 setTitle(data.title || 'Untitled Meeting');
+                versionRef.current = data.version;
+                savedRef.current = { title: data.title, notes: data.notes };
             } catch (err) {
                 console.error('Failed to fetch meeting:', err);
             } finally {
@@ -41,12 +48,38 @@ export default function NotePage() {
     }, [id, authLoading]);
 
     // Auto-save function
-    const saveChanges = useCallback(async (updates: { title?: string; notes?: string }) => {
+    const saveChanges = useCallback(async (updates: { title?: string; notes?: string; notes_source?: string }) => {
+        // Nothing to edit until the meeting, and so its version, has loaded
+        const version = versionRef.current;
+        if (version === undefined) return;
+
         setSaving(true);
         try {
-            await meetingsApi.update(id, updates);
-        } catch (err) {
-            console.error('Failed to save changes:', err);
+            const { data } = await meetingsApi.update(id, updates, version);
+            versionRef.current = data.version;
+            savedRef.current = { title: data.title, notes: data.notes };
+        } catch (err: any) {
+            if (err.response?.status !== 412) {
+                console.error('Failed to save changes:', err);
+                return;
+            }
+
+            // Someone else saved first. If they changed other fields than ours,
+            // our edit still applies on top; otherwise let the user pick a version.
+            const current: Meeting = err.response.data.meeting;
+            versionRef.current = current.version;
+            const clashes =
+                (updates.notes !== undefined && current.notes !== savedRef.current.notes) ||
+                (updates.title !== undefined && current.title !== savedRef.current.title);
+            savedRef.current = { title: current.title, notes: current.notes };
+
+            if (!clashes || window.confirm('This note was changed in another tab. Keep your version? Cancel loads the other version instead.')) {
+                await saveChanges(updates);
+                return;
+            }
+            setMeeting(current);
+            setTitle(current.title);
+            setEditorKey((key) => key + 1);
         } finally {
             // fast fake "saved" state for UX, realistic delay would be fine too
             setTimeout(() => setSaving(false), 500);
@@ -54,9 +87,15 @@ export default function NotePage() {
     }, [id]);
 
     // Debounced update handler
-    const handleContentUpdate = (newContent: string) => {
+    const handleContentUpdate = (newContent: string, source?: string) => {
         if (timeoutRef.current) clearTimeout(timeoutRef.current);
 
+        // AI rewrites are saved right away as their own revision
+        if (source) {
+            saveChanges({ notes: newContent, notes_source: source });
+            return;
+        }
+
         timeoutRef.current = setTimeout(() => {
             saveChanges({ notes: newContent });
         }, 2000); // Auto-save after 2 seconds of inactivity
@@ -97,7 +136,7 @@ export default function NotePage() {
                         )}
                         {lastChunk && <span className="text-emerald-500 flex items-center gap-1"><span className="w-1.5 h-1.5 rounded-full bg-emerald-500 animate-pulse" /> Live</span>}
                     </span>
-                    <LiveRecorder onTranscriptionChunk={appendChunk} />
+                    <LiveRecorder meetingId={id} onTranscriptionChunk={appendChunk} />
                 </header>
 
                 {/* The Workspace */}
@@ -112,6 +151,8 @@ export default function NotePage() {
                         />
                         {/* Only render editor if we have initial content or if it's empty but loaded */}
                         <TiptapEditor
+                            key={editorKey}
+                            meetingId={id}
                             liveTranscript={lastChunk}
                             initialContent={meeting?.notes || ''}
                             onUpdate={handleContentUpdate}
@@ -3,13 +3,15 @@ import { useState, useRef } from 'react';
 import { Mic, Square, Loader2 } from 'lucide-react';
 
 interface LiveRecorderProps {
+    meetingId?: number;
     onTranscriptionChunk?: (text: string) => void;
 }
 
-export default function LiveRecorder({ onTranscriptionChunk }: LiveRecorderProps) {
+export default function LiveRecorder({ meetingId, onTranscriptionChunk }: LiveRecorderProps) {
     const [status, setStatus] = useState<'idle' | 'recording' | 'processing'>('idle');
     const mediaRecorderRef = useRef<MediaRecorder | null>(null);
     const isRecordingRef = useRef<boolean>(false);
+    const recordingStartRef = useRef<number>(0);
     const [error, setError] = useState<string>('');
 
     // Keep latest callback in ref to avoid stale closures in event listener
@@ -25,6 +27,7 @@ export default function LiveRecorder({ onTranscriptionChunk }: LiveRecorderProps
             setError('');
             const stream = await navigator.mediaDevices.getUserMedia({ audio: true });
             isRecordingRef.current = true;
+            recordingStartRef.current = Date.now();
             setStatus('recording');
 
             // Function to handle the recording cycle
@@ -34,6 +37,7 @@ export default function LiveRecorder({ onTranscriptionChunk }: LiveRecorderProps
                 // Create a fresh recorder for each segment
                 const recorder = new MediaRecorder(stream, { mimeType: 'audio/webm' });
                 mediaRecorderRef.current = recorder;
+                const segmentStart = Date.now() - recordingStartRef.current;
 
                 recorder.ondataavailable = async (e) => {
                     if (e.data.size === 0) return;
@@ -41,6 +45,11 @@ export default function LiveRecorder({ onTranscriptionChunk }: LiveRecorderProps
 
                     const formData = new FormData();
                     formData.append('audio', e.data, `chunk-${Date.now()}.webm`);
+                    if (meetingId) {
+                        formData.append('meeting_id', meetingId.toString());
+                        formData.append('start_ms', segmentStart.toString());
+                        formData.append('end_ms', (Date.now() - recordingStartRef.current).toString());
+                    }
 
                     try {
                         const token = localStorage.getItem('echo_token');
@@ -4,12 +4,12 @@ import Link from 'next/link';
 import { usePathname, useRouter } from 'next/navigation';
 import Image from 'next/image';
 import { useEffect, useState } from 'react';
-import { meetingsApi, Meeting } from '@/lib/api';
+import { meetingsApi, MeetingSummary } from '@/lib/api';
 
 export default function Sidebar() {
   const pathname = usePathname();
   const router = useRouter();
-  const [meetings, setMeetings] = useState<Meeting[]>([]);
+  const [meetings, setMeetings] = useState<MeetingSummary[]>([]);
   const [loading, setLoading] = useState(true);
 
   const fetchMeetings = async () => {
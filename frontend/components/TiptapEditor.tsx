@@ -10,6 +10,7 @@ import Suggestion from "@tiptap/suggestion";
 import { Extension } from "@tiptap/core";
 import { useEffect, useRef, useState } from "react";
 import { Poppins } from "next/font/google";
+import { aiApi, ExtractedTask } from "@/lib/api";
 import {
     Sparkles,
     CheckSquare,
@@ -25,6 +26,29 @@ const poppins = Poppins({
     weight: ["400", "500", "600"],
 });
 
+/* ---------------- Helpers ---------------- */
+
+const escapeHtml = (text: string) =>
+    text.replace(/&/g, "&amp;").replace(/</g, "&lt;").replace(/>/g, "&gt;");
+
+const tasksToTaskList = (tasks: ExtractedTask[]) => {
+    if (tasks.length === 0) return "No tasks found";
+
+    const items = tasks.map((task) => {
+        const meta = [
+            task.assignee && `@${task.assignee}`,
+            task.due_date && `due ${task.due_date}`,
+            task.priority === "high" && "high priority",
+        ].filter(Boolean);
+        const label = meta.length
+            ? `${task.content} (${meta.join(", ")})`
+            : task.content;
+        return `<li data-type="taskItem" data-checked="false">${escapeHtml(label)}</li>`;
+    });
+
+    return `<ul data-type="taskList">${items.join("")}</ul>`;
+};
+
 /* ---------------- Types ---------------- */
 
 interface LiveTranscript {
@@ -33,9 +57,12 @@ interface LiveTranscript {
 }
 
 interface TiptapEditorProps {
+    // meetingId dates extracted tasks and stores them with the meeting
+    meetingId: number;
     liveTranscript?: LiveTranscript | null;
     initialContent?: string;
-    onUpdate?: (content: string) => void;
+    // source is set when the change came from an AI action, e.g. "beautify"
+    onUpdate?: (content: string, source?: string) => void;
 }
 
 /* ---------------- Slash Commands ---------------- */
@@ -152,12 +179,14 @@ const SlashCommand = Extension.create({
 /* ---------------- Component ---------------- */
 
 export default function TiptapEditor({
+    meetingId,
     liveTranscript,
     initialContent,
     onUpdate,
 }: TiptapEditorProps) {
     const [isAiLoading, setIsAiLoading] = useState(false);
     const lastInsertedRef = useRef<number>(0);
+    const aiSourceRef = useRef<string | undefined>(undefined);
 
     const editor = useEditor({
         extensions: [
@@ -173,7 +202,7 @@ export default function TiptapEditor({
             }),
         ],
         content: initialContent,
-        onUpdate: ({ editor }) => onUpdate?.(editor.getHTML()),
+        onUpdate: ({ editor }) => onUpdate?.(editor.getHTML(), aiSourceRef.current),
         editorProps: {
             attributes: {
                 class: `prose prose-lg max-w-none focus:outline-none min-h-[500px] ${poppins.className}`,
@@ -223,20 +252,18 @@ export default function TiptapEditor({
         setIsAiLoading(true);
 
         try {
-            const token = localStorage.getItem("echo_token");
-            const res = await fetch("/api/ai-format", {
-                method: "POST",
-                headers: {
-                    "Content-Type": "application/json",
-                    Authorization: `Bearer ${token}`,
-                },
-                body: JSON.stringify({ text, action }),
-            });
-
-            if (res.ok) {
-                const data = await res.json();
-                editor.chain().focus().insertContentAt({ from, to }, data.result).run();
+            const content =
+                action === "extract-tasks"
+                    ? tasksToTaskList((await aiApi.extractTasks(text, meetingId)).data.result)
+                    : (await aiApi.format(text, "beautify", meetingId)).data.result;
+            aiSourceRef.current = action;
+            try {
+                editor.chain().focus().insertContentAt({ from, to }, content).run();
+            } finally {
+                aiSourceRef.current = undefined;
             }
+        } catch (err) {
+            console.error(`AI ${action} failed:`, err);
         } finally {
             setIsAiLoading(false);
         }
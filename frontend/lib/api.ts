@@ -35,6 +35,9 @@ api.interceptors.response.use(
     }
 );
 
+// Scheduled meetings come from a calendar and are started with meetingsApi.start
+export type MeetingStatus = 'scheduled' | 'recording' | 'finished';
+
 export interface Meeting {
     id: number;
     title: string;
@@ -45,6 +48,58 @@ export interface Meeting {
     audio_path: string;
     duration_seconds: number;
     is_recording: boolean;
+    status: MeetingStatus;
+    scheduled_start: string | null;
+    scheduled_end: string | null;
+    language: string;
+    workspace: string; // whose redaction policy applies to AI calls
+    participants: string[];
+    template_id: number | null;
+    version: number;
+}
+
+// JSON Merge Patch of a meeting: null clears a field, absent fields stay as they are
+export interface MeetingPatch {
+    title?: string | null;
+    description?: string | null;
+    notes?: string | null;
+    transcript?: string | null;
+    meeting_type?: string | null;
+    tags?: string[] | null;
+    folder_id?: number | null;
+    language?: string | null;
+    participants?: string[] | null;
+}
+
+// Lightweight meeting returned by list endpoints
+export interface MeetingSummary {
+    id: number;
+    title: string;
+    created_at: string;
+    updated_at: string;
+    duration_seconds: number;
+    is_recording: boolean;
+    status: MeetingStatus;
+    scheduled_start: string | null;
+    has_audio: boolean;
+    meeting_type: string;
+    tags: string[];
+    snippet: string;
+}
+
+export interface MeetingListParams {
+    limit?: number;
+    cursor?: string;
+    sort?: 'created_at' | 'updated_at' | 'title' | 'duration';
+    order?: 'asc' | 'desc';
+    from?: string;
+    to?: string;
+    tag?: string;
+    type?: string;
+    status?: MeetingStatus;
+    is_recording?: boolean;
+    has_audio?: boolean;
+    person?: number;
 }
 
 // Auth
@@ -53,17 +108,220 @@ export const authApi = {
         api.post<{ token: string }>('/auth/login', { username, password }),
 };
 
+// ifMatch is the header that makes an edit apply only to this meeting version
+const ifMatch = (version: number) => ({ 'If-Match': `"${version}"` });
+
 // Meetings
 export const meetingsApi = {
-    getAll: () => api.get<{ meetings: Meeting[] }>('/meetings'),
+    getAll: (params?: MeetingListParams) =>
+        api.get<{ meetings: MeetingSummary[]; next_cursor?: string }>('/meetings', { params }),
     getOne: (id: number) => api.get<Meeting>(`/meetings/${id}`),
-    create: (title?: string) => api.post<Meeting>('/meetings', { title }),
-    update: (id: number, data: { title?: string; notes?: string }) =>
-        api.put<Meeting>(`/meetings/${id}`, data),
+    // A template sets the title (unless given), notes skeleton, tags and prompt
+    create: (title?: string, templateId?: number) => api.post<Meeting>('/meetings', { title, template_id: templateId }),
+    // Edits send the version they were based on; the server rejects them with
+    // 412 if the meeting changed since
+    update: (id: number, data: { title?: string; notes?: string; notes_source?: string }, version: number) =>
+        api.put<Meeting>(`/meetings/${id}`, data, { headers: ifMatch(version) }),
+    patch: (id: number, data: MeetingPatch, version: number) =>
+        api.patch<Meeting>(`/meetings/${id}`, data, {
+            headers: { 'Content-Type': 'application/merge-patch+json', ...ifMatch(version) },
+        }),
     delete: (id: number) => api.delete(`/meetings/${id}`),
+    start: (id: number) => api.post<Meeting>(`/meetings/${id}/start`),
     finish: (id: number) => api.post<Meeting>(`/meetings/${id}/finish`),
 };
 
+// Meeting templates; title patterns may use {date}, {time} and {weekday}
+export interface TemplateSection {
+    name: string;
+    hint: string;
+}
+
+export interface MeetingTemplate {
+    id: number;
+    name: string;
+    title_pattern: string;
+    agenda: string[];
+    sections: TemplateSection[];
+    tags: string[];
+    prompt: string;
+    created_at: string;
+    updated_at: string;
+}
+
+export type MeetingTemplateInput = Omit<MeetingTemplate, 'id' | 'created_at' | 'updated_at'>;
+
+export const templatesApi = {
+    getAll: () => api.get<{ templates: MeetingTemplate[] }>('/templates'),
+    getOne: (id: number) => api.get<MeetingTemplate>(`/templates/${id}`),
+    create: (data: MeetingTemplateInput) => api.post<MeetingTemplate>('/templates', data),
+    update: (id: number, data: MeetingTemplateInput) => api.put<MeetingTemplate>(`/templates/${id}`, data),
+    delete: (id: number) => api.delete(`/templates/${id}`),
+    // Fills the blank sections of the notes from the transcript, hand-written ones are kept
+    autoFill: (meetingId: number, version: number) =>
+        api.post<{ meeting: Meeting; filled: string[] }>(`/meetings/${meetingId}/autofill`, undefined,
+            { headers: ifMatch(version) }),
+};
+
+// Meeting export; zip bundles the Markdown, HTML and JSON exports with the audio
+export type ExportFormat = 'md' | 'html' | 'pdf' | 'docx' | 'json' | 'zip';
+
+export const exportApi = {
+    download: (id: number, format: ExportFormat = 'md') =>
+        api.get<Blob>(`/meetings/${id}/export`, { params: { format }, responseType: 'blob' }),
+};
+
+// Calendar import
+export interface CalendarImportResult {
+    events: number;
+    created: number;
+    updated: number;
+    cancelled: number;
+    skipped: number;
+}
+
+export const calendarApi = {
+    importFile: (file: File, folderId?: number) => {
+        const formData = new FormData();
+        formData.append('file', file);
+        if (folderId) formData.append('folder_id', folderId.toString());
+        return api.post<CalendarImportResult>('/calendar/import', formData, {
+            headers: { 'Content-Type': 'multipart/form-data' },
+        });
+    },
+    importFeed: (url: string, folderId?: number) =>
+        api.post<CalendarImportResult>('/calendar/import', { url, folder_id: folderId }),
+};
+
+// Transcript import from other meeting tools
+export type ImportFormat = 'vtt' | 'srt' | 'teams' | 'otter';
+
+export interface ImportResult {
+    file: string;
+    format: ImportFormat;
+    meeting_id: number;
+    title: string;
+    date: string;
+    segments: number;
+    participants: string[];
+    has_audio: boolean;
+    duplicate: boolean;
+    restored: boolean; // the duplicate was in the trash and has been restored
+}
+
+export interface ImportOptions {
+    audio?: File;
+    format?: ImportFormat;
+    title?: string;
+    date?: string;
+    folderId?: number;
+}
+
+export const importApi = {
+    importFile: (file: File, options: ImportOptions = {}) => {
+        const formData = new FormData();
+        formData.append('file', file);
+        if (options.audio) formData.append('audio', options.audio);
+        if (options.format) formData.append('format', options.format);
+        if (options.title) formData.append('title', options.title);
+        if (options.date) formData.append('date', options.date);
+        if (options.folderId) formData.append('folder_id', options.folderId.toString());
+        return api.post<ImportResult>('/import', formData, {
+            headers: { 'Content-Type': 'multipart/form-data' },
+        });
+    },
+};
+
+// Backups of the database and audio
+export interface Backup {
+    name: string;
+    size: number;
+    created_at: string;
+}
+
+export const backupApi = {
+    getAll: () => api.get<Backup[]>('/admin/backups'),
+    create: () => api.post<Backup>('/admin/backups'),
+    download: (name: string) =>
+        api.get<Blob>(`/admin/backups/${encodeURIComponent(name)}`, { responseType: 'blob' }),
+    verify: (name: string) => api.post<{ valid: boolean }>(`/admin/backups/${encodeURIComponent(name)}/verify`),
+    delete: (name: string) => api.delete(`/admin/backups/${encodeURIComponent(name)}`),
+};
+
+// Notes revision history
+export interface NoteRevision {
+    id: number;
+    meeting_id: number;
+    author: string;
+    source: string;
+    notes?: string;
+    size: number;
+    created_at: string;
+    updated_at: string;
+}
+
+export interface DiffLine {
+    op: 'equal' | 'insert' | 'delete';
+    text: string;
+}
+
+export const revisionsApi = {
+    getAll: (meetingId: number) =>
+        api.get<{ revisions: NoteRevision[] }>(`/meetings/${meetingId}/revisions`),
+    getOne: (meetingId: number, revisionId: number) =>
+        api.get<NoteRevision>(`/meetings/${meetingId}/revisions/${revisionId}`),
+    diff: (meetingId: number, from: number, to?: number) =>
+        api.get<{ from: number; to: number | null; diff: DiffLine[] }>(`/meetings/${meetingId}/revisions/diff`, { params: { from, to } }),
+    restore: (meetingId: number, revisionId: number, version: number) =>
+        api.post<Meeting>(`/meetings/${meetingId}/revisions/${revisionId}/restore`, undefined,
+            { headers: ifMatch(version) }),
+};
+
+// People directory and meeting participants
+export interface Person {
+    id: number;
+    name: string;
+    email: string;
+    aliases: string[];
+    meeting_count: number;
+    created_at: string;
+}
+
+export type ParticipantRole = 'host' | 'attendee' | 'external';
+
+export interface Participant {
+    id: number;
+    meeting_id: number;
+    person_id: number | null;
+    name: string;
+    email: string;
+    role: ParticipantRole;
+    speaker: string;
+}
+
+export const peopleApi = {
+    getAll: (q?: string) => api.get<{ people: Person[] }>('/people', { params: { q } }),
+    getOne: (id: number) => api.get<Person>(`/people/${id}`),
+    create: (data: { name: string; email?: string; aliases?: string[] }) => api.post<Person>('/people', data),
+    update: (id: number, data: { name: string; email?: string; aliases?: string[] }) => api.put<Person>(`/people/${id}`, data),
+    delete: (id: number) => api.delete(`/people/${id}`),
+    getMeetings: (id: number, cursor?: string) =>
+        api.get<{ meetings: MeetingSummary[]; next_cursor?: string }>(`/people/${id}/meetings`, { params: { cursor } }),
+};
+
+export const participantsApi = {
+    getAll: (meetingId: number) =>
+        api.get<{ participants: Participant[]; roles: ParticipantRole[] }>(`/meetings/${meetingId}/participants`),
+    add: (meetingId: number, data: { person_id?: number; name?: string; role?: ParticipantRole }) =>
+        api.post<Participant>(`/meetings/${meetingId}/participants`, data),
+    setRole: (meetingId: number, participantId: number, role: ParticipantRole) =>
+        api.put<Participant>(`/meetings/${meetingId}/participants/${participantId}`, { role }),
+    remove: (meetingId: number, participantId: number) =>
+        api.delete(`/meetings/${meetingId}/participants/${participantId}`),
+    mapSpeaker: (meetingId: number, speaker: string, participantId: number) =>
+        api.put<{ participant: Participant; segments_relabelled: number }>(`/meetings/${meetingId}/speakers`, { speaker, participant_id: participantId }),
+};
+
 // Transcription
 export const transcriptionApi = {
     uploadChunk: (meetingId: number, audioBlob: Blob) => {
@@ -77,9 +335,20 @@ export const transcriptionApi = {
 };
 
 // AI
+export interface ExtractedTask {
+    content: string;
+    assignee: string;
+    due_date: string;
+    priority: 'low' | 'medium' | 'high';
+    confidence: number;
+    quote: string;
+}
+
 export const aiApi = {
-    format: (text: string, action: 'beautify' | 'extract-tasks') =>
-        api.post<{ result: string }>('/ai-format', { text, action }),
+    format: (text: string, action: 'beautify', meetingId?: number) =>
+        api.post<{ result: string }>('/ai-format', { text, action, meeting_id: meetingId }),
+    extractTasks: (text: string, meetingId: number) =>
+        api.post<{ result: ExtractedTask[] }>('/ai-format', { text, action: 'extract-tasks', meeting_id: meetingId }),
 };
 
 export default api;